@@ -1,6 +1,7 @@
 package benchmarks
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/seuros/gopher-cypher/src/cypher"
@@ -48,3 +49,21 @@ func BenchmarkComplexQueryConstruction(b *testing.B) {
 		q.BuildCypher()
 	}
 }
+
+// BenchmarkRegisterParameter shows the cost of the dedup scan, which walks
+// every registered parameter before adding a new one.
+func BenchmarkRegisterParameter(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		for _, dedup := range []bool{true, false} {
+			b.Run(fmt.Sprintf("params=%d/dedup=%t", n, dedup), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					q := cypher.NewQuery()
+					q.SetParameterDedup(dedup)
+					for v := 0; v < n; v++ {
+						q.RegisterParameter(v)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/seuros/gopher-cypher/src/driver"
+)
+
+func explainCommand(args []string) error {
+	fs := flag.NewFlagSet("explain", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	urlFlag := fs.String("url", os.Getenv("CYQ_URL"), "Connection URL (or set CYQ_URL)")
+	queryFlag := fs.String("query", "", "Query string (if no file is provided)")
+	paramsFlag := fs.String("params", "", "Params as JSON object (e.g. '{\"n\": 1}')")
+	paramsFileFlag := fs.String("params-file", "", "Path to JSON file containing params")
+	formatFlag := fs.String("format", "text", "Output format: text|json")
+	timeoutFlag := fs.Duration("timeout", 0, "Optional context timeout (e.g. 10s, 1m). 0 disables.")
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return &exitError{code: 0}
+		}
+		return usageErrorf(2, "%v", err)
+	}
+
+	format := strings.ToLower(*formatFlag)
+	if format != "text" && format != "json" {
+		return usageErrorf(2, "Unknown --format %q (expected text|json)", *formatFlag)
+	}
+
+	if *urlFlag == "" {
+		return usageErrorf(2, "Missing --url (or set CYQ_URL)")
+	}
+
+	query, err := resolveQuery(*queryFlag, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	params, err := resolveParams(*paramsFlag, *paramsFileFlag)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if *timeoutFlag > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeoutFlag)
+		defer cancel()
+	}
+
+	dr, err := driver.NewDriver(*urlFlag)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dr.Close() }()
+
+	streaming, ok := dr.(driver.StreamingDriver)
+	if !ok {
+		return fmt.Errorf("driver does not support streaming")
+	}
+
+	result, err := streaming.RunStream(ctx, explainQuery(query), params, nil)
+	if err != nil {
+		return err
+	}
+
+	summary, err := result.Consume(ctx)
+	if err != nil {
+		return err
+	}
+
+	var plan interface{}
+	switch {
+	case summary != nil && summary.Profile != nil:
+		plan = summary.Profile
+	case summary != nil && summary.Plan != nil:
+		plan = summary.Plan
+	default:
+		return fmt.Errorf("server did not return a query plan")
+	}
+
+	if format == "json" {
+		return writePlanJSON(os.Stdout, plan)
+	}
+	return writePlanText(os.Stdout, plan)
+}
+
+// explainQuery prefixes query with EXPLAIN unless it already asks for a plan.
+func explainQuery(query string) string {
+	upper := strings.ToUpper(strings.TrimSpace(query))
+	if strings.HasPrefix(upper, "EXPLAIN ") || strings.HasPrefix(upper, "PROFILE ") {
+		return query
+	}
+	return "EXPLAIN " + query
+}
+
+func writePlanJSON(w io.Writer, plan interface{}) error {
+	b, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", b)
+	return err
+}
+
+func writePlanText(w io.Writer, plan interface{}) error {
+	switch p := plan.(type) {
+	case *driver.QueryPlan:
+		writePlanTextAt(w, p, 0)
+	case *driver.QueryProfile:
+		writeProfileTextAt(w, p, 0)
+	default:
+		return fmt.Errorf("unsupported plan type %T", plan)
+	}
+	return nil
+}
+
+func writePlanTextAt(w io.Writer, p *driver.QueryPlan, depth int) {
+	writePlanNode(w, depth, p.OperatorType, p.Identifiers, p.Arguments, "")
+	for _, child := range p.Children {
+		writePlanTextAt(w, child, depth+1)
+	}
+}
+
+func writeProfileTextAt(w io.Writer, p *driver.QueryProfile, depth int) {
+	writePlanNode(w, depth, p.OperatorType, p.Identifiers, p.Arguments, fmt.Sprintf(" rows=%d dbHits=%d", p.Rows, p.DbHits))
+	for _, child := range p.Children {
+		writeProfileTextAt(w, child, depth+1)
+	}
+}
+
+func writePlanNode(w io.Writer, depth int, operator string, identifiers []string, args map[string]interface{}, stats string) {
+	indent := strings.Repeat("  ", depth)
+	_, _ = fmt.Fprintf(w, "%s+%s", indent, operator)
+	if len(identifiers) > 0 {
+		_, _ = fmt.Fprintf(w, " (%s)", strings.Join(identifiers, ", "))
+	}
+	_, _ = fmt.Fprintf(w, "%s\n", stats)
+
+	if details, ok := args["Details"]; ok {
+		_, _ = fmt.Fprintf(w, "%s  %s\n", indent, stringifyValue(details))
+		return
+	}
+	keys := make([]string, 0, len(args))
+	for k := range args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		_, _ = fmt.Fprintf(w, "%s  %s: %s\n", indent, k, stringifyValue(args[k]))
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/seuros/gopher-cypher/src/driver"
+)
+
+func TestExplainQuery(t *testing.T) {
+	tests := map[string]string{
+		"MATCH (n) RETURN n":         "EXPLAIN MATCH (n) RETURN n",
+		"explain MATCH (n) RETURN n": "explain MATCH (n) RETURN n",
+		"PROFILE MATCH (n) RETURN n": "PROFILE MATCH (n) RETURN n",
+	}
+	for in, want := range tests {
+		if got := explainQuery(in); got != want {
+			t.Errorf("explainQuery(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestWritePlanJSONAndText(t *testing.T) {
+	plan := &driver.QueryPlan{
+		OperatorType: "ProduceResults",
+		Identifiers:  []string{"n"},
+		Children: []*driver.QueryPlan{
+			{OperatorType: "AllNodesScan", Identifiers: []string{"n"}},
+		},
+	}
+
+	var jsonBuf bytes.Buffer
+	if err := writePlanJSON(&jsonBuf, plan); err != nil {
+		t.Fatalf("writePlanJSON failed: %v", err)
+	}
+	if !json.Valid(jsonBuf.Bytes()) {
+		t.Fatalf("invalid JSON: %s", jsonBuf.String())
+	}
+
+	var textBuf bytes.Buffer
+	if err := writePlanText(&textBuf, plan); err != nil {
+		t.Fatalf("writePlanText failed: %v", err)
+	}
+	want := "+ProduceResults (n)\n  +AllNodesScan (n)\n"
+	if textBuf.String() != want {
+		t.Errorf("unexpected text plan:\n%s\nwant:\n%s", textBuf.String(), want)
+	}
+	if strings.Contains(jsonBuf.String(), "null") {
+		t.Errorf("expected empty collections instead of null: %s", jsonBuf.String())
+	}
+}
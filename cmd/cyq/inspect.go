@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/seuros/gopher-cypher/src/cypher"
+	"github.com/seuros/gopher-cypher/src/driver"
+	"github.com/seuros/gopher-cypher/src/parser"
+)
+
+func inspectCommand(args []string) error {
+	if len(args) != 1 {
+		return usageErrorf(2, "Usage: cyq inspect <file>")
+	}
+
+	filename := args[0]
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	p, err := parser.New()
+	if err != nil {
+		return err
+	}
+
+	query, err := p.Parse(string(content))
+	if err != nil {
+		return err
+	}
+
+	return writeInspect(os.Stdout, filename, query)
+}
+
+// writeInspect prints the generated Cypher, its inferred query type and a
+// table of the extracted parameters sorted by name, with where in the source
+// each one was written.
+func writeInspect(w io.Writer, filename string, query *cypher.Query) error {
+	generated, params := query.BuildCypher()
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "Query structure for %s:\n", filename)
+	fmt.Fprintf(bw, "Generated Cypher: %s\n", generated)
+	fmt.Fprintf(bw, "Query type: %s\n", driver.InferQueryType(generated))
+
+	if len(params) == 0 {
+		fmt.Fprintln(bw, "Parameters: none")
+		return bw.Flush()
+	}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		// Compare by length first so p10 sorts after p9.
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) < len(names[j])
+		}
+		return names[i] < names[j]
+	})
+
+	spans := query.ParameterSpans()
+	rows := [][]string{{"NAME", "TYPE", "VALUE", "SOURCE"}}
+	for _, name := range names {
+		v := params[name]
+		rows = append(rows, []string{"$" + name, fmt.Sprintf("%T", v), formatTableCell(v), formatSpans(spans[name])})
+	}
+
+	widths := make([]int, 4)
+	for _, row := range rows {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	fmt.Fprintln(bw, "Parameters:")
+	for _, row := range rows {
+		writeTableRow(bw, row, widths)
+	}
+	return bw.Flush()
+}
+
+// formatSpans renders source spans as line:column ranges, such as "1:22-27"
+// or "1:40-2:3" for one that crosses a line.
+func formatSpans(spans []cypher.SourceSpan) string {
+	parts := make([]string, len(spans))
+	for i, s := range spans {
+		if s.EndLine == s.Line {
+			parts[i] = fmt.Sprintf("%d:%d-%d", s.Line, s.Column, s.EndColumn)
+		} else {
+			parts[i] = fmt.Sprintf("%d:%d-%d:%d", s.Line, s.Column, s.EndLine, s.EndColumn)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/seuros/gopher-cypher/src/parser"
+)
+
+func TestWriteInspectParameterTable(t *testing.T) {
+	p, err := parser.New()
+	if err != nil {
+		t.Fatalf("parser.New failed: %v", err)
+	}
+	query, err := p.Parse(`MATCH (n:User) WHERE n.name = "Alice" RETURN n.name LIMIT 10`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeInspect(&buf, "query.cypher", query); err != nil {
+		t.Fatalf("writeInspect failed: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"Query type: READ\n",
+		"NAME  TYPE    VALUE  SOURCE\n",
+		"$p1   string  Alice  1:31-38\n",
+		"$p2   int     10     1:59-61\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/seuros/gopher-cypher/src/cypher"
 	"github.com/seuros/gopher-cypher/src/driver"
 	"github.com/seuros/gopher-cypher/src/lsp"
 	"github.com/seuros/gopher-cypher/src/parser"
@@ -29,6 +30,8 @@ func main() {
 		err = inspectCommand(args)
 	case "run":
 		err = runCommand(args)
+	case "explain":
+		err = explainCommand(args)
 	case "ping":
 		err = pingCommand(args)
 	case "lsp":
@@ -65,6 +68,7 @@ func printUsage() {
 	fmt.Println("  cyq fmt <file>                 - Format Cypher query")
 	fmt.Println("  cyq inspect <file>             - Inspect AST structure")
 	fmt.Println("  cyq run [flags] [file|-]       - Execute a query against a database")
+	fmt.Println("  cyq explain [flags] [file|-]   - Show the execution plan (--format text|json)")
 	fmt.Println("  cyq ping [flags]               - Test database connectivity")
 	fmt.Println("  cyq lsp                        - Start Language Server")
 	fmt.Println("  cyq version                    - Show version information")
@@ -75,6 +79,9 @@ func printUsage() {
 	fmt.Println("  --params-file <path>           - Params from JSON file")
 	fmt.Println("  --format table|json|jsonl      - Output format (default: table)")
 	fmt.Println("  --timeout 10s                  - Optional context timeout (default: none)")
+	fmt.Println("  --max-col-width 50             - Truncate wide table cells (0 disables)")
+	fmt.Println("  --dry-run                      - Check the query and print it with its params without connecting")
+	fmt.Println("  --stats                        - Print update counters after execution")
 }
 
 func versionCommand() error {
@@ -100,6 +107,10 @@ func lintCommand(args []string) error {
 	}
 
 	_, err = p.Parse(string(content))
+	var serr *parser.SyntaxError
+	if errors.As(err, &serr) {
+		return usageErrorf(1, "Syntax error in %s:%d:%d: %s", filename, serr.Line, serr.Column, serr.Message)
+	}
 	if err != nil {
 		return usageErrorf(1, "Syntax error in %s: %v", filename, err)
 	}
@@ -129,36 +140,7 @@ func fmtCommand(args []string) error {
 		return err
 	}
 
-	formatted, _ := query.BuildCypher()
-	fmt.Print(formatted)
-	return nil
-}
-
-func inspectCommand(args []string) error {
-	if len(args) != 1 {
-		return usageErrorf(2, "Usage: cyq inspect <file>")
-	}
-
-	filename := args[0]
-	content, err := os.ReadFile(filename)
-	if err != nil {
-		return err
-	}
-
-	p, err := parser.New()
-	if err != nil {
-		return err
-	}
-
-	query, err := p.Parse(string(content))
-	if err != nil {
-		return err
-	}
-
-	fmt.Printf("Query structure for %s:\n", filename)
-	cypher, params := query.BuildCypher()
-	fmt.Printf("Generated Cypher: %s\n", cypher)
-	fmt.Printf("Parameters: %v\n", params)
+	fmt.Print(cypher.Format(query, cypher.FormatOptions{IndentWidth: 2, UppercaseKeywords: true}))
 	return nil
 }
 
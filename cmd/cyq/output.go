@@ -1,43 +1,101 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"strings"
-	"text/tabwriter"
+	"unicode/utf8"
 
 	"github.com/seuros/gopher-cypher/src/driver"
 )
 
-func writeTable(ctx context.Context, w io.Writer, keys []string, result driver.Result) (int64, error) {
-	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
-	defer func() { _ = tw.Flush() }()
-
-	if len(keys) > 0 {
-		_, _ = fmt.Fprintln(tw, strings.Join(keys, "\t"))
+// writeTable buffers the result, sizes each column to its widest cell and
+// renders an aligned table. Cells wider than maxColWidth runes are truncated
+// with an ellipsis; maxColWidth <= 0 disables truncation.
+func writeTable(ctx context.Context, w io.Writer, keys []string, result driver.Result, maxColWidth int) (int64, error) {
+	widths := make([]int, len(keys))
+	for i, key := range keys {
+		widths[i] = utf8.RuneCountInString(truncateCell(key, maxColWidth))
 	}
 
-	var rows int64
+	var rows [][]string
 	for result.Next(ctx) {
 		rec := result.Record()
 		if rec == nil {
 			continue
 		}
-		rows++
 
-		line := make([]string, 0, len(keys))
-		for _, key := range keys {
-			line = append(line, stringifyValue((*rec)[key]))
+		line := make([]string, len(keys))
+		for i, key := range keys {
+			line[i] = truncateCell(formatTableCell((*rec)[key]), maxColWidth)
+			if n := utf8.RuneCountInString(line[i]); n > widths[i] {
+				widths[i] = n
+			}
 		}
-		_, _ = fmt.Fprintln(tw, strings.Join(line, "\t"))
+		rows = append(rows, line)
 	}
-
 	if err := result.Err(); err != nil {
-		return rows, err
+		return int64(len(rows)), err
 	}
-	return rows, nil
+
+	if len(keys) == 0 {
+		return int64(len(rows)), nil
+	}
+
+	header := make([]string, len(keys))
+	separator := make([]string, len(keys))
+	for i, key := range keys {
+		header[i] = truncateCell(key, maxColWidth)
+		separator[i] = strings.Repeat("-", widths[i])
+	}
+
+	bw := bufio.NewWriter(w)
+	writeTableRow(bw, header, widths)
+	writeTableRow(bw, separator, widths)
+	for _, line := range rows {
+		writeTableRow(bw, line, widths)
+	}
+	return int64(len(rows)), bw.Flush()
+}
+
+func writeTableRow(w *bufio.Writer, cells []string, widths []int) {
+	for i, cell := range cells {
+		if i > 0 {
+			_, _ = w.WriteString("  ")
+		}
+		_, _ = w.WriteString(cell)
+		// Don't pad the last column; trailing spaces only add noise.
+		if i < len(cells)-1 {
+			_, _ = w.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+		}
+	}
+	_ = w.WriteByte('\n')
+}
+
+// formatTableCell renders nil as an empty cell and everything else via
+// stringifyValue (maps and lists become compact JSON). Line breaks are escaped
+// so a single value can't break the row layout.
+func formatTableCell(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	s := stringifyValue(v)
+	s = strings.ReplaceAll(s, "\r", `\r`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
+
+func truncateCell(s string, maxWidth int) string {
+	if maxWidth <= 0 || utf8.RuneCountInString(s) <= maxWidth {
+		return s
+	}
+	if maxWidth == 1 {
+		return "…"
+	}
+	runes := []rune(s)
+	return string(runes[:maxWidth-1]) + "…"
 }
 
 func writeJSONLines(ctx context.Context, w io.Writer, result driver.Result) (int64, error) {
@@ -121,3 +179,37 @@ func stringifyValue(v interface{}) string {
 		return fmt.Sprint(v)
 	}
 }
+
+// writeStats prints the non-zero update counters of summary on one line, in
+// the style of the cypher-shell summary ("Added 1 nodes, Set 2 properties").
+// Nothing is written when the query changed nothing.
+func writeStats(w io.Writer, summary *driver.ResultSummary) error {
+	counters := []struct {
+		format string
+		n      int64
+	}{
+		{"Added %d nodes", summary.NodesCreated},
+		{"Deleted %d nodes", summary.NodesDeleted},
+		{"Created %d relationships", summary.RelationshipsCreated},
+		{"Deleted %d relationships", summary.RelationshipsDeleted},
+		{"Set %d properties", summary.PropertiesSet},
+		{"Added %d labels", summary.LabelsAdded},
+		{"Removed %d labels", summary.LabelsRemoved},
+		{"Added %d indexes", summary.IndexesAdded},
+		{"Removed %d indexes", summary.IndexesRemoved},
+		{"Added %d constraints", summary.ConstraintsAdded},
+		{"Removed %d constraints", summary.ConstraintsRemoved},
+	}
+
+	var parts []string
+	for _, c := range counters {
+		if c.n != 0 {
+			parts = append(parts, fmt.Sprintf(c.format, c.n))
+		}
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	_, err := fmt.Fprintln(w, strings.Join(parts, ", "))
+	return err
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/seuros/gopher-cypher/src/driver"
+)
+
+// sliceStream serves a fixed set of records through driver.StreamConnection.
+type sliceStream struct {
+	keys    []string
+	records []*driver.Record
+	index   int
+}
+
+func (s *sliceStream) GetKeys() ([]string, error) {
+	return s.keys, nil
+}
+
+func (s *sliceStream) PullNext(ctx context.Context, batchSize int) (*driver.Record, *driver.ResultSummary, error) {
+	if s.index >= len(s.records) {
+		return nil, &driver.ResultSummary{}, nil
+	}
+	rec := s.records[s.index]
+	s.index++
+	return rec, nil, nil
+}
+
+func (s *sliceStream) Close() error {
+	return nil
+}
+
+func newSliceResult(keys []string, records ...*driver.Record) driver.Result {
+	return driver.NewStreamingResult(&sliceStream{keys: keys, records: records}, "", nil)
+}
+
+func TestWriteTable(t *testing.T) {
+	keys := []string{"name", "age", "tags"}
+	result := newSliceResult(keys,
+		&driver.Record{"name": "CHAD", "age": int64(30), "tags": []interface{}{"a", "b"}},
+		&driver.Record{"name": "Bartholomew the Magnificent", "age": nil, "tags": map[string]interface{}{"k": 1}},
+	)
+
+	var buf bytes.Buffer
+	rows, err := writeTable(context.Background(), &buf, keys, result, 12)
+	if err != nil {
+		t.Fatalf("writeTable failed: %v", err)
+	}
+	if rows != 2 {
+		t.Errorf("expected 2 rows, got %d", rows)
+	}
+
+	expected := "" +
+		"name          age  tags\n" +
+		"------------  ---  ---------\n" +
+		"CHAD          30   [\"a\",\"b\"]\n" +
+		"Bartholomew…       {\"k\":1}\n"
+	if buf.String() != expected {
+		t.Errorf("unexpected table:\n%s\nwant:\n%s", buf.String(), expected)
+	}
+}
+
+func TestWriteStats(t *testing.T) {
+	summary := &driver.ResultSummary{
+		NodesCreated:         2,
+		RelationshipsCreated: 1,
+		PropertiesSet:        5,
+		LabelsAdded:          2,
+		ContainsUpdates:      true,
+	}
+
+	var buf bytes.Buffer
+	if err := writeStats(&buf, summary); err != nil {
+		t.Fatalf("writeStats failed: %v", err)
+	}
+	want := "Added 2 nodes, Created 1 relationships, Set 5 properties, Added 2 labels\n"
+	if buf.String() != want {
+		t.Errorf("unexpected stats %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := writeStats(&buf, &driver.ResultSummary{}); err != nil {
+		t.Fatalf("writeStats failed: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output for a read-only query, got %q", buf.String())
+	}
+}
+
+func TestTruncateCell(t *testing.T) {
+	tests := []struct {
+		in   string
+		max  int
+		want string
+	}{
+		{"hello", 0, "hello"},
+		{"hello", 5, "hello"},
+		{"hello", 4, "hel…"},
+		{"héllo wörld", 6, "héllo…"},
+		{"hello", 1, "…"},
+	}
+	for _, tt := range tests {
+		if got := truncateCell(tt.in, tt.max); got != tt.want {
+			t.Errorf("truncateCell(%q, %d) = %q, want %q", tt.in, tt.max, got, tt.want)
+		}
+	}
+}
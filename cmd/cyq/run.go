@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -11,9 +12,16 @@ import (
 	"time"
 
 	"github.com/seuros/gopher-cypher/src/driver"
+	"github.com/seuros/gopher-cypher/src/parser"
 )
 
 func runCommand(args []string) error {
+	return runQuery(os.Stdout, args)
+}
+
+// runQuery implements `cyq run`, writing results to w and the summary to
+// stderr.
+func runQuery(w io.Writer, args []string) error {
 	fs := flag.NewFlagSet("run", flag.ContinueOnError)
 	fs.SetOutput(os.Stderr)
 
@@ -24,6 +32,9 @@ func runCommand(args []string) error {
 	formatFlag := fs.String("format", "table", "Output format: table|json|jsonl")
 	timeoutFlag := fs.Duration("timeout", 0, "Optional context timeout (e.g. 10s, 1m). 0 disables.")
 	noSummaryFlag := fs.Bool("no-summary", false, "Do not print summary to stderr")
+	maxColWidthFlag := fs.Int("max-col-width", 50, "Truncate table cells wider than this many characters (0 disables)")
+	dryRunFlag := fs.Bool("dry-run", false, "Check the query and print it with its params without connecting")
+	statsFlag := fs.Bool("stats", false, "Print the update counters (nodes created, properties set, ...) to stderr")
 
 	if err := fs.Parse(args); err != nil {
 		if err == flag.ErrHelp {
@@ -32,7 +43,7 @@ func runCommand(args []string) error {
 		return usageErrorf(2, "%v", err)
 	}
 
-	if *urlFlag == "" {
+	if *urlFlag == "" && !*dryRunFlag {
 		return usageErrorf(2, "Missing --url (or set CYQ_URL)")
 	}
 
@@ -46,6 +57,13 @@ func runCommand(args []string) error {
 		return err
 	}
 
+	if *dryRunFlag {
+		if err := checkQuery(query); err != nil {
+			return err
+		}
+		return writeDryRun(w, query, params)
+	}
+
 	ctx := context.Background()
 	if *timeoutFlag > 0 {
 		var cancel context.CancelFunc
@@ -77,11 +95,11 @@ func runCommand(args []string) error {
 	var rows int64
 	switch strings.ToLower(*formatFlag) {
 	case "table":
-		rows, err = writeTable(ctx, os.Stdout, keys, result)
+		rows, err = writeTable(ctx, w, keys, result, *maxColWidthFlag)
 	case "json":
-		rows, err = writeJSONArray(ctx, os.Stdout, result)
+		rows, err = writeJSONArray(ctx, w, result)
 	case "jsonl":
-		rows, err = writeJSONLines(ctx, os.Stdout, result)
+		rows, err = writeJSONLines(ctx, w, result)
 	default:
 		return usageErrorf(2, "Unknown --format %q (expected table|json|jsonl)", *formatFlag)
 	}
@@ -98,10 +116,52 @@ func runCommand(args []string) error {
 	if !*noSummaryFlag && summary != nil {
 		fmt.Fprintf(os.Stderr, "rows=%d time=%s\n", rows, summary.ExecutionTime.Truncate(time.Microsecond))
 	}
+	if *statsFlag && summary != nil {
+		if err := writeStats(os.Stderr, summary); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
+// checkQuery parses and validates query the way lint does, so a dry run
+// reports the syntax and scope errors the server would otherwise reject.
+func checkQuery(query string) error {
+	p, err := parser.New()
+	if err != nil {
+		return err
+	}
+
+	q, err := p.Parse(query)
+	var serr *parser.SyntaxError
+	if errors.As(err, &serr) {
+		return usageErrorf(1, "Syntax error at %d:%d: %s", serr.Line, serr.Column, serr.Message)
+	}
+	if err != nil {
+		return usageErrorf(1, "Syntax error: %v", err)
+	}
+
+	if errs := q.Validate(); len(errs) > 0 {
+		msgs := make([]string, len(errs))
+		for i, e := range errs {
+			msgs[i] = e.Error()
+		}
+		return usageErrorf(1, "Invalid query: %s", strings.Join(msgs, "; "))
+	}
+	return nil
+}
+
+// writeDryRun prints the query followed by its params as indented JSON.
+func writeDryRun(w io.Writer, query string, params map[string]interface{}) error {
+	data, err := json.MarshalIndent(params, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n\nParams: %s\n", query, data)
+	return err
+}
+
 func resolveQuery(queryFlag string, remainingArgs []string) (string, error) {
 	if queryFlag != "" {
 		if len(remainingArgs) != 0 {
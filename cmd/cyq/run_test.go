@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/seuros/gopher-cypher/src/bolt/messaging"
+	"github.com/seuros/gopher-cypher/src/driver/testserver"
+)
+
+func TestRunQueryDryRun(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	var accepted atomic.Int32
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			accepted.Add(1)
+			_ = conn.Close()
+		}
+	}()
+
+	var buf bytes.Buffer
+	err = runQuery(&buf, []string{
+		"--dry-run",
+		"--url", "bolt://neo4j:password@" + listener.Addr().String(),
+		"--params", `{"name": "Alice", "limit": 10}`,
+		"--query", "MATCH (n {name: $name}) RETURN n LIMIT $limit;",
+	})
+	_ = listener.Close()
+	if err != nil {
+		t.Fatalf("runQuery failed: %v", err)
+	}
+	if n := accepted.Load(); n != 0 {
+		t.Errorf("expected no connection attempts, got %d", n)
+	}
+
+	want := "MATCH (n {name: $name}) RETURN n LIMIT $limit\n\n" +
+		"Params: {\n  \"limit\": 10,\n  \"name\": \"Alice\"\n}\n"
+	if buf.String() != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestRunQueryDryRunWithoutURL(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runQuery(&buf, []string{"--dry-run", "--query", "RETURN 1"}); err != nil {
+		t.Fatalf("runQuery failed: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "RETURN 1\n") {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+}
+
+func TestRunQueryDryRunChecksQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"syntax error", "MATCH (n RETURN n", "Syntax error at 1:"},
+		{"undefined variable", "MATCH (n) RETURN m", "Invalid query: "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := runQuery(&buf, []string{"--dry-run", "--query", tt.query})
+			var exitErr *exitError
+			if !errors.As(err, &exitErr) || exitErr.code != 1 || !strings.HasPrefix(exitErr.msg, tt.want) {
+				t.Fatalf("expected an exit error starting %q, got %v", tt.want, err)
+			}
+			if buf.Len() != 0 {
+				t.Errorf("expected nothing printed for a rejected query, got %q", buf.String())
+			}
+		})
+	}
+}
+
+func TestRunQueryShowCommand(t *testing.T) {
+	srv, err := testserver.New()
+	if err != nil {
+		t.Fatalf("failed to start test server: %v", err)
+	}
+	defer func() { _ = srv.Close() }()
+	srv.Handle("SHOW DATABASES", testserver.Result{
+		Fields: []string{"name", "currentStatus", "aliases", "default"},
+		Records: [][]interface{}{
+			{"neo4j", "online", []interface{}{}, true},
+			{"system", "online", []interface{}{"sys"}, false},
+		},
+	})
+
+	var buf bytes.Buffer
+	if err := runQuery(&buf, []string{"--url", srv.URL(), "--no-summary", "--query", "SHOW DATABASES;"}); err != nil {
+		t.Fatalf("runQuery failed: %v", err)
+	}
+
+	var sent []string
+	for _, msg := range srv.Requests() {
+		if msg.Signature() == messaging.RunSignature {
+			sent = append(sent, msg.Fields()[0].(string))
+		}
+	}
+	if len(sent) != 1 || sent[0] != "SHOW DATABASES" {
+		t.Errorf("expected SHOW DATABASES to be sent unchanged, got %q", sent)
+	}
+
+	want := "name    currentStatus  aliases  default\n" +
+		"------  -------------  -------  -------\n" +
+		"neo4j   online         []       true\n" +
+		"system  online         [\"sys\"]  false\n"
+	if buf.String() != want {
+		t.Errorf("unexpected table:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
@@ -82,7 +82,13 @@ func (m *Hello) Metadata() map[string]interface{} {
 }
 
 func (m *Hello) Send(conn net.Conn) (Message, error) {
-	return sendRequest(m.Signature(), m.Fields(), conn)
+	return sendRequest(m.Signature(), m.Fields(), conn, DefaultMaxMessageSize)
+}
+
+// SendLimit is Send with the response capped at maxSize bytes; see
+// ReadChunkedMessageLimit.
+func (m *Hello) SendLimit(conn net.Conn, maxSize int) (Message, error) {
+	return sendRequest(m.Signature(), m.Fields(), conn, maxSize)
 }
 
 // Logon represents the Login message
@@ -107,7 +113,13 @@ func (m *Logon) Metadata() map[string]interface{} {
 }
 
 func (m *Logon) Send(conn net.Conn) (Message, error) {
-	return sendRequest(m.Signature(), m.Fields(), conn)
+	return sendRequest(m.Signature(), m.Fields(), conn, DefaultMaxMessageSize)
+}
+
+// SendLimit is Send with the response capped at maxSize bytes; see
+// ReadChunkedMessageLimit.
+func (m *Logon) SendLimit(conn net.Conn, maxSize int) (Message, error) {
+	return sendRequest(m.Signature(), m.Fields(), conn, maxSize)
 }
 
 // Goodbye represents the GOODBYE message
@@ -191,6 +203,19 @@ func (m *Run) Send(conn net.Conn) ([]string, []map[string]interface{}, error) {
 	return sendRequestData(m.Signature(), m.Fields(), conn)
 }
 
+// SendWithSummary sends the RUN and a PULL for all records, returning the
+// metadata of the final SUCCESS alongside the columns and rows. The RUN
+// response's t_first is copied into that metadata.
+func (m *Run) SendWithSummary(conn net.Conn) ([]string, []map[string]interface{}, map[string]interface{}, error) {
+	return sendRequestDataWithSummary(m.Signature(), m.Fields(), conn, DefaultMaxMessageSize)
+}
+
+// SendWithSummaryLimit is SendWithSummary with every response message capped
+// at maxSize bytes; a maxSize <= 0 uses DefaultMaxMessageSize.
+func (m *Run) SendWithSummaryLimit(conn net.Conn, maxSize int) ([]string, []map[string]interface{}, map[string]interface{}, error) {
+	return sendRequestDataWithSummary(m.Signature(), m.Fields(), conn, maxSize)
+}
+
 // Begin represents the BEGIN message
 type Begin struct {
 	metadata map[string]interface{}
@@ -324,16 +349,31 @@ func (m *Pull) Metadata() map[string]interface{} {
 	return m.metadata
 }
 
-// Route represents the ROUTE message
+// Route represents the ROUTE message. The metadata map is the routing
+// context; bookmarks and extra (db, imp_user) follow it on the wire.
 type Route struct {
-	metadata map[string]interface{}
+	metadata  map[string]interface{}
+	bookmarks []interface{}
+	extra     map[string]interface{}
 }
 
 func NewRoute(metadata map[string]interface{}) *Route {
+	return NewRouteWithExtra(metadata, nil, nil)
+}
+
+// NewRouteWithExtra builds a ROUTE message with bookmarks and extra metadata
+// such as the target database.
+func NewRouteWithExtra(metadata map[string]interface{}, bookmarks []interface{}, extra map[string]interface{}) *Route {
 	if metadata == nil {
 		metadata = make(map[string]interface{})
 	}
-	return &Route{metadata: metadata}
+	if bookmarks == nil {
+		bookmarks = []interface{}{}
+	}
+	if extra == nil {
+		extra = make(map[string]interface{})
+	}
+	return &Route{metadata: metadata, bookmarks: bookmarks, extra: extra}
 }
 
 func (m *Route) Signature() byte {
@@ -341,13 +381,31 @@ func (m *Route) Signature() byte {
 }
 
 func (m *Route) Fields() []interface{} {
-	return []interface{}{m.metadata}
+	return []interface{}{m.metadata, m.bookmarks, m.extra}
 }
 
 func (m *Route) Metadata() map[string]interface{} {
 	return m.metadata
 }
 
+func (m *Route) Bookmarks() []interface{} {
+	return m.bookmarks
+}
+
+func (m *Route) Extra() map[string]interface{} {
+	return m.extra
+}
+
+func (m *Route) Send(conn net.Conn) (Message, error) {
+	return sendRequest(m.Signature(), m.Fields(), conn, DefaultMaxMessageSize)
+}
+
+// SendLimit is Send with the response capped at maxSize bytes; see
+// ReadChunkedMessageLimit.
+func (m *Route) SendLimit(conn net.Conn, maxSize int) (Message, error) {
+	return sendRequest(m.Signature(), m.Fields(), conn, maxSize)
+}
+
 // Success represents the SUCCESS message
 type Success struct {
 	metadata map[string]interface{}
@@ -15,7 +15,22 @@ import (
 // DefaultReadTimeout is the default timeout for reading from the connection
 const DefaultReadTimeout = 30 * time.Second
 
-func sendRequest(signature byte, fields []interface{}, conn net.Conn) (Message, error) {
+// DefaultMaxMessageSize caps how large a message the reader reassembles from
+// chunks, so a misbehaving server can't exhaust memory with an endless message.
+const DefaultMaxMessageSize = 16 << 20
+
+// ErrMessageTooLarge is returned when a message grows past the maximum size.
+// The rest of the message is left unread, so the connection can't be reused.
+var ErrMessageTooLarge = errors.New("message exceeds the maximum size")
+
+// ErrIgnored is returned when the server answers a request with IGNORED. After
+// a FAILURE the server ignores every request until it receives RESET, so the
+// connection has to be reset before it can be used again.
+var ErrIgnored = errors.New("request ignored by server after a previous failure; connection needs RESET")
+
+// sendRequest writes one request and reads the response, which may be at most
+// maxSize bytes; see ReadChunkedMessageLimit.
+func sendRequest(signature byte, fields []interface{}, conn net.Conn, maxSize int) (Message, error) {
 	messageBytes, err := packMessage(signature, fields)
 	if err != nil {
 		return nil, err
@@ -35,7 +50,7 @@ func sendRequest(signature byte, fields []interface{}, conn net.Conn) (Message,
 	if err != nil {
 		return nil, err
 	}
-	messageIn, err := readChunkedMessage(conn)
+	messageIn, err := ReadChunkedMessageLimit(conn, maxSize)
 	if err != nil {
 		return nil, err
 	}
@@ -43,58 +58,72 @@ func sendRequest(signature byte, fields []interface{}, conn net.Conn) (Message,
 }
 
 func sendRequestData(signature byte, fields []interface{}, conn net.Conn) ([]string, []map[string]interface{}, error) {
+	cols, rows, _, err := sendRequestDataWithSummary(signature, fields, conn, DefaultMaxMessageSize)
+	return cols, rows, err
+}
+
+// sendRequestDataWithSummary behaves like sendRequestData and also returns the
+// metadata of the SUCCESS message that terminates the PULL (bookmark, stats,
+// timings). Every response read may be at most maxSize bytes.
+func sendRequestDataWithSummary(signature byte, fields []interface{}, conn net.Conn, maxSize int) ([]string, []map[string]interface{}, map[string]interface{}, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxMessageSize
+	}
 	messageBytes, err := packMessage(signature, fields)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	messageSize := len(messageBytes)
 	chunkHeader := make([]byte, 2)
 	binary.BigEndian.PutUint16(chunkHeader, uint16(messageSize))
 	_, err = conn.Write(chunkHeader)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	_, err = conn.Write(messageBytes)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	_, err = conn.Write([]byte{0x00, 0x00})
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
-	messageIn, err := readChunkedMessage(conn)
+	messageIn, err := readChunkedMessageLimit(conn, maxSize)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	// Check for FAILURE response first
 	if messageIn.Signature() == FailureSignature {
 		if failure, ok := messageIn.(*Failure); ok {
-			return nil, nil, fmt.Errorf("query failed: [%s] %s", failure.Code(), failure.Message())
+			return nil, nil, nil, fmt.Errorf("query failed: [%s] %s", failure.Code(), failure.Message())
 		}
-		return nil, nil, errors.New("query execution failed")
+		return nil, nil, nil, errors.New("query execution failed")
+	}
+	if messageIn.Signature() == IgnoredSignature {
+		return nil, nil, nil, ErrIgnored
 	}
 
 	// Check for unexpected response types
 	if messageIn.Signature() != SuccessSignature {
-		return nil, nil, fmt.Errorf("unexpected response type: 0x%02X", messageIn.Signature())
+		return nil, nil, nil, fmt.Errorf("unexpected response type: 0x%02X", messageIn.Signature())
 	}
 
 	fieldsW := messageIn.Fields()
 	if len(fieldsW) != 1 {
-		return nil, nil, errors.New("invalid fields length")
+		return nil, nil, nil, errors.New("invalid fields length")
 	}
 
 	// Safely extract fields with type checking
 	fieldsMap, ok := fieldsW[0].(map[string]interface{})
 	if !ok {
-		return nil, nil, errors.New("invalid response format: expected map")
+		return nil, nil, nil, errors.New("invalid response format: expected map")
 	}
 
 	fieldsVal, exists := fieldsMap["fields"]
 	if !exists {
-		return nil, nil, errors.New("invalid response format: missing 'fields' key")
+		return nil, nil, nil, errors.New("invalid response format: missing 'fields' key")
 	}
 
 	fieldsCols, ok := fieldsVal.([]interface{})
@@ -103,7 +132,7 @@ func sendRequestData(signature byte, fields []interface{}, conn net.Conn) ([]str
 		if fieldsVal == nil {
 			fieldsCols = []interface{}{}
 		} else {
-			return nil, nil, fmt.Errorf("invalid response format: 'fields' is %T, expected []interface{}", fieldsVal)
+			return nil, nil, nil, fmt.Errorf("invalid response format: 'fields' is %T, expected []interface{}", fieldsVal)
 		}
 	}
 
@@ -127,30 +156,47 @@ func sendRequestData(signature byte, fields []interface{}, conn net.Conn) ([]str
 	// The server can send multiple RECORD messages in response to a single PULL.
 	// Read until the terminating SUCCESS/FAILURE for this PULL so the connection
 	// remains in a clean state for subsequent queries.
-	pullResponse, err := sendRequest(pull.Signature(), pull.Fields(), conn)
+	pullResponse, err := sendRequest(pull.Signature(), pull.Fields(), conn, maxSize)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	for {
 		switch pullResponse.Signature() {
 		case FailureSignature:
 			if failure, ok := pullResponse.(*Failure); ok {
-				return nil, nil, fmt.Errorf("pull failed: [%s] %s", failure.Code(), failure.Message())
+				return nil, nil, nil, fmt.Errorf("pull failed: [%s] %s", failure.Code(), failure.Message())
 			}
-			return nil, nil, errors.New("pull failed")
+			return nil, nil, nil, errors.New("pull failed")
+
+		case IgnoredSignature:
+			return nil, nil, nil, ErrIgnored
 
 		case SuccessSignature:
-			return strFieldsCols, allData, nil
+			var summary map[string]interface{}
+			if successFields := pullResponse.Fields(); len(successFields) > 0 {
+				summary, _ = successFields[0].(map[string]interface{})
+			}
+			// t_first is only reported on the RUN response; carry it over so
+			// callers see both timings in one map.
+			if tFirst, exists := fieldsMap["t_first"]; exists {
+				if summary == nil {
+					summary = map[string]interface{}{}
+				}
+				if _, exists := summary["t_first"]; !exists {
+					summary["t_first"] = tFirst
+				}
+			}
+			return strFieldsCols, allData, summary, nil
 
 		case RecordSignature:
 			pullFields := pullResponse.Fields()
 			if len(pullFields) != 1 {
-				return nil, nil, errors.New("invalid record format")
+				return nil, nil, nil, errors.New("invalid record format")
 			}
 			colsValues, ok := pullFields[0].([]interface{})
 			if !ok {
-				return nil, nil, errors.New("invalid record format: expected []interface{}")
+				return nil, nil, nil, errors.New("invalid record format: expected []interface{}")
 			}
 
 			row := make(map[string]interface{}, len(strFieldsCols))
@@ -164,12 +210,12 @@ func sendRequestData(signature byte, fields []interface{}, conn net.Conn) ([]str
 			allData = append(allData, row)
 
 		default:
-			return nil, nil, fmt.Errorf("unexpected pull response type: 0x%02X", pullResponse.Signature())
+			return nil, nil, nil, fmt.Errorf("unexpected pull response type: 0x%02X", pullResponse.Signature())
 		}
 
-		pullResponse, err = readChunkedMessage(conn)
+		pullResponse, err = readChunkedMessageLimit(conn, maxSize)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 	}
 }
@@ -203,7 +249,21 @@ func ReadChunkedMessage(conn net.Conn) (Message, error) {
 	return readChunkedMessage(conn)
 }
 
+// ReadChunkedMessageLimit is ReadChunkedMessage with the message size capped
+// at maxSize bytes instead of DefaultMaxMessageSize. A maxSize <= 0 uses the
+// default.
+func ReadChunkedMessageLimit(conn net.Conn, maxSize int) (Message, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxMessageSize
+	}
+	return readChunkedMessageLimit(conn, maxSize)
+}
+
 func readChunkedMessage(conn net.Conn) (Message, error) {
+	return readChunkedMessageLimit(conn, DefaultMaxMessageSize)
+}
+
+func readChunkedMessageLimit(conn net.Conn, maxSize int) (Message, error) {
 	var messageData bytes.Buffer
 
 	// Set read deadline to prevent hanging
@@ -217,18 +277,27 @@ func readChunkedMessage(conn net.Conn) (Message, error) {
 		sizeBytes := make([]byte, 2)
 		if _, err := io.ReadFull(conn, sizeBytes); err != nil {
 			if err == io.EOF {
-				return nil, errors.New("connection closed while reading chunk header")
+				return nil, fmt.Errorf("connection closed while reading chunk header: %w", err)
 			}
 			return nil, fmt.Errorf("error reading chunk header: %w", err)
 		}
 
 		chunkSize := binary.BigEndian.Uint16(sizeBytes)
 
-		// If chunk size is 0, we've reached the end of the message
+		// A zero-length chunk ends the message once data has been read. Before
+		// that it is a NOOP (keep-alive) and is skipped.
 		if chunkSize == 0 {
+			if messageData.Len() == 0 {
+				continue
+			}
 			break
 		}
 
+		// Refuse the chunk before reading it, so memory stays bounded.
+		if messageData.Len()+int(chunkSize) > maxSize {
+			return nil, fmt.Errorf("%w of %d bytes", ErrMessageTooLarge, maxSize)
+		}
+
 		// Read chunk data
 		chunk := make([]byte, chunkSize)
 		if _, err := io.ReadFull(conn, chunk); err != nil {
@@ -238,7 +307,16 @@ func readChunkedMessage(conn net.Conn) (Message, error) {
 		// Append to message buffer
 		messageData.Write(chunk)
 	}
-	reader := packstream.NewUnpacker(&messageData)
+	return decodeMessage(messageData.Bytes())
+}
+
+// DecodeMessage decodes a complete, de-chunked message body.
+func DecodeMessage(data []byte) (Message, error) {
+	return decodeMessage(data)
+}
+
+func decodeMessage(data []byte) (Message, error) {
+	reader := packstream.NewUnpacker(bytes.NewReader(data))
 	unpacked, err := reader.Unpack()
 	if err != nil {
 		return nil, fmt.Errorf("error unpacking chunk data: %w", err)
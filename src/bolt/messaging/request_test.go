@@ -0,0 +1,121 @@
+package messaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// bufferConn is a net.Conn that reads from a fixed byte stream.
+type bufferConn struct {
+	net.Conn
+	in bytes.Buffer
+}
+
+func (c *bufferConn) Read(b []byte) (int, error) {
+	if c.in.Len() == 0 {
+		return 0, io.EOF
+	}
+	return c.in.Read(b)
+}
+
+func (c *bufferConn) SetReadDeadline(t time.Time) error {
+	return nil
+}
+
+// writeChunks appends data to the stream split into chunks of at most size
+// bytes, followed by the end-of-message marker.
+func (c *bufferConn) writeChunks(t *testing.T, data []byte, size int) {
+	t.Helper()
+	for len(data) > 0 {
+		n := size
+		if n > len(data) {
+			n = len(data)
+		}
+		header := make([]byte, 2)
+		binary.BigEndian.PutUint16(header, uint16(n))
+		c.in.Write(header)
+		c.in.Write(data[:n])
+		data = data[n:]
+	}
+	c.in.Write([]byte{0x00, 0x00})
+}
+
+func packTestMessage(t *testing.T, signature byte, fields ...interface{}) []byte {
+	t.Helper()
+	data, err := PackMessage(signature, fields)
+	if err != nil {
+		t.Fatalf("failed to pack message: %v", err)
+	}
+	return data
+}
+
+func TestReadChunkedMessage_SkipsNoopBetweenMessages(t *testing.T) {
+	conn := &bufferConn{}
+	conn.writeChunks(t, packTestMessage(t, RecordSignature, []interface{}{int64(1)}), 3)
+	conn.in.Write([]byte{0x00, 0x00}) // keep-alive NOOP
+	conn.writeChunks(t, packTestMessage(t, SuccessSignature, map[string]interface{}{"has_more": false}), 4)
+
+	first, err := ReadChunkedMessage(conn)
+	if err != nil {
+		t.Fatalf("first read failed: %v", err)
+	}
+	if first.Signature() != RecordSignature {
+		t.Fatalf("expected RECORD, got 0x%02X", first.Signature())
+	}
+
+	second, err := ReadChunkedMessage(conn)
+	if err != nil {
+		t.Fatalf("second read failed: %v", err)
+	}
+	if second.Signature() != SuccessSignature {
+		t.Fatalf("expected SUCCESS after NOOP, got 0x%02X", second.Signature())
+	}
+}
+
+func TestReadChunkedMessage_SkipsLeadingNoops(t *testing.T) {
+	conn := &bufferConn{}
+	conn.in.Write([]byte{0x00, 0x00, 0x00, 0x00})
+	conn.writeChunks(t, packTestMessage(t, SuccessSignature, map[string]interface{}{"fields": []interface{}{"n"}}), 64)
+
+	msg, err := ReadChunkedMessage(conn)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	success, ok := msg.(*Success)
+	if !ok {
+		t.Fatalf("expected *Success, got %T", msg)
+	}
+	fields, ok := success.Metadata()["fields"].([]interface{})
+	if !ok || len(fields) != 1 || fields[0] != "n" {
+		t.Errorf("unexpected metadata: %v", success.Metadata())
+	}
+	if conn.in.Len() != 0 {
+		t.Errorf("expected the whole stream to be consumed, %d bytes left", conn.in.Len())
+	}
+}
+
+func TestReadChunkedMessageLimit_RejectsOversizedMessage(t *testing.T) {
+	conn := &bufferConn{}
+	conn.writeChunks(t, bytes.Repeat([]byte{0x01}, 10*500), 500)
+	total := conn.in.Len()
+
+	_, err := ReadChunkedMessageLimit(conn, 1024)
+	if !errors.Is(err, ErrMessageTooLarge) {
+		t.Fatalf("expected ErrMessageTooLarge, got %v", err)
+	}
+	// Two 500-byte chunks fit; the third header is read and refused.
+	if read := total - conn.in.Len(); read != 2*502+2 {
+		t.Errorf("expected reading to stop at the limit, consumed %d of %d bytes", read, total)
+	}
+
+	conn = &bufferConn{}
+	conn.writeChunks(t, packTestMessage(t, SuccessSignature, map[string]interface{}{}), 64)
+	if _, err := ReadChunkedMessageLimit(conn, 0); err != nil {
+		t.Errorf("expected a small message to pass the default limit: %v", err)
+	}
+}
@@ -37,6 +37,10 @@ const (
 
 	FLOAT_64 = 0xC1
 
+	BYTES_8_MARKER  = 0xCC
+	BYTES_16_MARKER = 0xCD
+	// BYTES_32_MARKER = 0xCE // Not implementing 32-bit sizes for now
+
 	TINY_STRUCT_MARKER_BASE = 0xB0
 	STRUCT_8_MARKER         = 0xDC
 	STRUCT_16_MARKER        = 0xDD
@@ -65,9 +69,19 @@ func (e ProtocolError) Error() string {
 	return e.Message
 }
 
+// Structure is a PackStream structure: a signature byte tagging a fixed list
+// of fields. Bolt uses structures for messages and for values such as
+// temporal types.
+type Structure struct {
+	Signature byte
+	Fields    []interface{}
+}
+
 // Packer handles serializing Go types to Packstream format
 type Packer struct {
 	writer io.Writer
+	// header holds marker and size bytes so writing them does not allocate.
+	header [3]byte
 }
 
 // NewPacker creates a new Packstream packer
@@ -83,10 +97,9 @@ func (p *Packer) Pack(value interface{}) error {
 	case map[string]interface{}:
 		return p.packMap(v)
 	case []byte:
-		// PackStream supports a dedicated "Bytes" type, but this implementation
-		// does not currently implement it. Without this case, the reflect-slice
-		// fallback would start writing a list header and then fail on uint8 elems.
-		return &ProtocolError{Message: "Cannot pack type: []byte (bytes are not supported)"}
+		// Without this case the reflect-slice fallback would pack a list of
+		// integers instead of a byte array.
+		return p.packBytes(v)
 	case int, int8, int16, int32, int64:
 		// Convert to int64 for consistent handling
 		var intValue int64
@@ -103,6 +116,26 @@ func (p *Packer) Pack(value interface{}) error {
 			intValue = iv
 		}
 		return p.packInteger(intValue)
+	case uint, uint8, uint16, uint32, uint64:
+		// PackStream integers are signed 64-bit; anything above MaxInt64
+		// cannot be represented and must not wrap to a negative value.
+		var uintValue uint64
+		switch uv := v.(type) {
+		case uint:
+			uintValue = uint64(uv)
+		case uint8:
+			uintValue = uint64(uv)
+		case uint16:
+			uintValue = uint64(uv)
+		case uint32:
+			uintValue = uint64(uv)
+		case uint64:
+			uintValue = uv
+		}
+		if uintValue > math.MaxInt64 {
+			return &ProtocolError{Message: fmt.Sprintf("Cannot pack %T value %d: exceeds the maximum integer %d", v, uintValue, int64(math.MaxInt64))}
+		}
+		return p.packInteger(int64(uintValue))
 	case bool:
 		if v {
 			return p.writeMarker([]byte{TRUETHY})
@@ -112,6 +145,8 @@ func (p *Packer) Pack(value interface{}) error {
 		return p.writeMarker([]byte{NULL})
 	case []interface{}:
 		return p.packList(v)
+	case Structure:
+		return p.packStructure(v)
 	default:
 		// Use reflection to handle typed slices ([]string, []int, etc.)
 		rv := reflect.ValueOf(value)
@@ -122,6 +157,22 @@ func (p *Packer) Pack(value interface{}) error {
 	}
 }
 
+func (p *Packer) packStructure(s Structure) error {
+	size := len(s.Fields)
+	if size >= 16 {
+		return &ProtocolError{Message: fmt.Sprintf("Structure too large to pack (fields: %d)", size)}
+	}
+	if err := p.writeMarker([]byte{TINY_STRUCT_MARKER_BASE | byte(size), s.Signature}); err != nil {
+		return err
+	}
+	for _, field := range s.Fields {
+		if err := p.Pack(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (p *Packer) packListHeader(size int) error {
 	if size < 16 { // TinyList
 		return p.writeMarker([]byte{TINY_LIST_MARKER_BASE | byte(size)})
@@ -139,22 +190,62 @@ func (p *Packer) packListHeader(size int) error {
 	return &ProtocolError{Message: fmt.Sprintf("List too large to pack (size: %d)", size)}
 }
 
+// packString writes the marker and size from p.header and the string through
+// io.WriteString, so writers implementing io.StringWriter (bytes.Buffer,
+// bufio.Writer) pack strings without allocating.
 func (p *Packer) packString(str string) error {
-	bytes := []byte(str)
-	size := len(bytes)
+	size := len(str)
 
+	var header []byte
 	if size < 16 { // TinyString
-		return p.writeMarkerAndData([]byte{TINY_STRING_MARKER_BASE | byte(size)}, bytes)
+		p.header[0] = TINY_STRING_MARKER_BASE | byte(size)
+		header = p.header[:1]
 	} else if size < 256 { // STRING_8
-		return p.writeMarkerAndData([]byte{STRING_8_MARKER, byte(size)}, bytes)
+		p.header[0] = STRING_8_MARKER
+		p.header[1] = byte(size)
+		header = p.header[:2]
 	} else if size < 65536 { // STRING_16
-		marker := []byte{STRING_16_MARKER}
-		sizeBytes := make([]byte, 2)
-		binary.BigEndian.PutUint16(sizeBytes, uint16(size))
-		return p.writeMarkerAndData(append(marker, sizeBytes...), bytes)
+		p.header[0] = STRING_16_MARKER
+		binary.BigEndian.PutUint16(p.header[1:], uint16(size))
+		header = p.header[:3]
 	} else {
 		return &ProtocolError{Message: fmt.Sprintf("String too large to pack (size: %d)", size)}
 	}
+
+	if _, err := p.writer.Write(header); err != nil {
+		return err
+	}
+	if size > 0 {
+		_, err := io.WriteString(p.writer, str)
+		return err
+	}
+	return nil
+}
+
+func (p *Packer) packBytes(b []byte) error {
+	size := len(b)
+
+	var header []byte
+	if size < 256 { // BYTES_8
+		p.header[0] = BYTES_8_MARKER
+		p.header[1] = byte(size)
+		header = p.header[:2]
+	} else if size < 65536 { // BYTES_16
+		p.header[0] = BYTES_16_MARKER
+		binary.BigEndian.PutUint16(p.header[1:], uint16(size))
+		header = p.header[:3]
+	} else {
+		return &ProtocolError{Message: fmt.Sprintf("Bytes too large to pack (size: %d)", size)}
+	}
+
+	if _, err := p.writer.Write(header); err != nil {
+		return err
+	}
+	if size > 0 {
+		_, err := p.writer.Write(b)
+		return err
+	}
+	return nil
 }
 
 func (p *Packer) packMap(m map[string]interface{}) error {
@@ -333,6 +424,18 @@ func (u *Unpacker) unpackValue(marker byte) (interface{}, error) {
 			return nil, err
 		}
 		return u.unpackString(int(size))
+	case BYTES_8_MARKER:
+		size, err := u.readSize(1)
+		if err != nil {
+			return nil, err
+		}
+		return u.readBytes(int(size))
+	case BYTES_16_MARKER:
+		size, err := u.readSize(2)
+		if err != nil {
+			return nil, err
+		}
+		return u.readBytes(int(size))
 	case LIST_8_MARKER:
 		size, err := u.readSize(1)
 		if err != nil {
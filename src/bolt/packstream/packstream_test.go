@@ -2,6 +2,9 @@ package packstream
 
 import (
 	"bytes"
+	"errors"
+	"io"
+	"math"
 	"reflect"
 	"testing"
 )
@@ -49,19 +52,55 @@ func TestPackInteger(t *testing.T) {
 	}
 }
 
-func TestPackString(t *testing.T) {
+func TestPackUnsignedInteger(t *testing.T) {
 	tests := []struct {
 		name     string
-		input    string
-		expected []byte
+		input    interface{}
+		expected int64
 	}{
-		{"Empty String", "", []byte{0x80}},
-		{"Small String", "hello", []byte{0x85, 0x68, 0x65, 0x6C, 0x6C, 0x6F}},
-		{"String8", string(bytes.Repeat([]byte("a"), 20)), append([]byte{0xD0, 0x14}, bytes.Repeat([]byte("a"), 20)...)},
-		{"String16", string(bytes.Repeat([]byte("a"), 300)), append([]byte{0xD1, 0x01, 0x2C}, bytes.Repeat([]byte("a"), 300)...)},
+		{"uint", uint(42), 42},
+		{"uint8", uint8(200), 200},
+		{"uint16", uint16(65535), 65535},
+		{"uint32", uint32(4294967295), 4294967295},
+		{"uint64 max int64", uint64(math.MaxInt64), math.MaxInt64},
 	}
 
 	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := Pack(test.input)
+			if err != nil {
+				t.Fatalf("Failed to pack: %v", err)
+			}
+			val, err := Unpack(data)
+			if err != nil {
+				t.Fatalf("Failed to unpack: %v", err)
+			}
+			if val.(int64) != test.expected {
+				t.Errorf("Unpack returned %v, expected %v", val, test.expected)
+			}
+		})
+	}
+
+	_, err := Pack(uint64(math.MaxUint64))
+	var protocolErr *ProtocolError
+	if !errors.As(err, &protocolErr) {
+		t.Fatalf("Expected ProtocolError for uint64 overflow, got %v", err)
+	}
+}
+
+var packStringTests = []struct {
+	name     string
+	input    string
+	expected []byte
+}{
+	{"Empty String", "", []byte{0x80}},
+	{"Small String", "hello", []byte{0x85, 0x68, 0x65, 0x6C, 0x6C, 0x6F}},
+	{"String8", string(bytes.Repeat([]byte("a"), 20)), append([]byte{0xD0, 0x14}, bytes.Repeat([]byte("a"), 20)...)},
+	{"String16", string(bytes.Repeat([]byte("a"), 300)), append([]byte{0xD1, 0x01, 0x2C}, bytes.Repeat([]byte("a"), 300)...)},
+}
+
+func TestPackString(t *testing.T) {
+	for _, test := range packStringTests {
 		t.Run(test.name, func(t *testing.T) {
 			buf := &bytes.Buffer{}
 			p := NewPacker(buf)
@@ -89,6 +128,96 @@ func TestPackString(t *testing.T) {
 	}
 }
 
+// writerOnly hides bytes.Buffer's WriteString so packString takes the
+// plain io.Writer path.
+type writerOnly struct{ buf bytes.Buffer }
+
+func (w *writerOnly) Write(b []byte) (int, error) { return w.buf.Write(b) }
+
+func TestPackStringWriterPaths(t *testing.T) {
+	// Pack every case back to back through one Packer so a header left over
+	// from a previous string would show up in the output.
+	var expected []byte
+	for _, test := range packStringTests {
+		expected = append(expected, test.expected...)
+	}
+
+	buf := &bytes.Buffer{}
+	plain := &writerOnly{}
+	for _, w := range []io.Writer{buf, plain} {
+		p := NewPacker(w)
+		for _, test := range packStringTests {
+			if err := p.packString(test.input); err != nil {
+				t.Fatalf("%s: failed to pack: %v", test.name, err)
+			}
+		}
+	}
+	if !bytes.Equal(buf.Bytes(), expected) {
+		t.Errorf("bytes.Buffer output differs:\nexpected %X\ngot      %X", expected, buf.Bytes())
+	}
+	if !bytes.Equal(plain.buf.Bytes(), expected) {
+		t.Errorf("io.Writer output differs:\nexpected %X\ngot      %X", expected, plain.buf.Bytes())
+	}
+}
+
+func TestPackStringDoesNotAllocate(t *testing.T) {
+	buf := &bytes.Buffer{}
+	buf.Grow(1024)
+	p := NewPacker(buf)
+	for _, test := range packStringTests {
+		allocs := testing.AllocsPerRun(100, func() {
+			buf.Reset()
+			_ = p.packString(test.input)
+		})
+		if allocs != 0 {
+			t.Errorf("%s: expected no allocations, got %v", test.name, allocs)
+		}
+	}
+}
+
+func BenchmarkPackString(b *testing.B) {
+	for _, test := range packStringTests {
+		b.Run(test.name, func(b *testing.B) {
+			buf := &bytes.Buffer{}
+			buf.Grow(1024)
+			p := NewPacker(buf)
+			b.ReportAllocs()
+			b.SetBytes(int64(len(test.input)))
+			for i := 0; i < b.N; i++ {
+				buf.Reset()
+				if err := p.packString(test.input); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestPackStructure(t *testing.T) {
+	buf := &bytes.Buffer{}
+	p := NewPacker(buf)
+	if err := p.Pack(Structure{Signature: 0x44, Fields: []interface{}{int64(19359)}}); err != nil {
+		t.Fatalf("Failed to pack: %v", err)
+	}
+	expected := []byte{0xB1, 0x44, 0xC9, 0x4B, 0x9F}
+	if !bytes.Equal(buf.Bytes(), expected) {
+		t.Errorf("Expected %X, got %X", expected, buf.Bytes())
+	}
+
+	val, err := NewUnpacker(bytes.NewReader(buf.Bytes())).Unpack()
+	if err != nil {
+		t.Fatalf("Failed to unpack: %v", err)
+	}
+	want := []interface{}{byte(0x44), []interface{}{int64(19359)}}
+	if !reflect.DeepEqual(val, want) {
+		t.Errorf("Unpack returned %#v, expected %#v", val, want)
+	}
+
+	if err := p.Pack(Structure{Fields: make([]interface{}, 16)}); err == nil {
+		t.Error("Expected an error for a structure with 16 fields")
+	}
+}
+
 func TestPackList(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -432,15 +561,36 @@ func TestPackTypedSliceAliases(t *testing.T) {
 	}
 }
 
-func TestPackByteSliceFailsWithoutWriting(t *testing.T) {
-	buf := &bytes.Buffer{}
-	p := NewPacker(buf)
-
-	err := p.Pack([]byte{0x01, 0x02})
-	if err == nil {
-		t.Fatalf("Expected error for []byte, got nil")
+func TestPackBytes(t *testing.T) {
+	large := bytes.Repeat([]byte{0xAB}, 300)
+	tests := []struct {
+		name   string
+		input  []byte
+		header []byte
+	}{
+		{"Empty Bytes", []byte{}, []byte{0xCC, 0x00}},
+		{"Bytes8", []byte{0x01, 0x02}, []byte{0xCC, 0x02}},
+		{"Bytes16", large, []byte{0xCD, 0x01, 0x2C}},
 	}
-	if buf.Len() != 0 {
-		t.Fatalf("Expected no bytes written on []byte error, wrote %d", buf.Len())
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := NewPacker(buf).Pack(tt.input); err != nil {
+				t.Fatalf("Pack failed: %v", err)
+			}
+			expected := append(append([]byte{}, tt.header...), tt.input...)
+			if !bytes.Equal(buf.Bytes(), expected) {
+				t.Errorf("Expected % X, got % X", expected, buf.Bytes())
+			}
+
+			result, err := NewUnpacker(buf).Unpack()
+			if err != nil {
+				t.Fatalf("Unpack failed: %v", err)
+			}
+			if b, ok := result.([]byte); !ok || !bytes.Equal(b, tt.input) {
+				t.Errorf("Expected the bytes back, got %#v", result)
+			}
+		})
 	}
 }
@@ -13,9 +13,11 @@ func checkVersion(conn net.Conn) error {
 }
 
 func sendHello(conn net.Conn) error {
-	return boltutil.SendHello(conn)
+	return boltutil.SendHello(conn, map[string]interface{}{
+		"notifications_minimum_severity": "WARNING",
+	})
 }
 
 func authenticate(conn net.Conn, urlResolver *connection_url_resolver.ConnectionUrlResolver) error {
-	return boltutil.Authenticate(conn, urlResolver)
+	return boltutil.Authenticate(conn, urlResolver, 0)
 }
@@ -39,13 +39,32 @@ type ConnectionConfig struct {
 type ConnectionUrlResolver struct {
 	urlString string
 	parsed    *ConnectionConfig
+
+	defaultDatabase    string
+	hasDefaultDatabase bool
+}
+
+// Option configures a ConnectionUrlResolver.
+type Option func(*ConnectionUrlResolver)
+
+// WithDefaultDatabase sets the database used when the URL has no path,
+// replacing the adapter's own default ("neo4j" for Neo4j, none for
+// Memgraph). A database named in the URL always wins.
+func WithDefaultDatabase(db string) Option {
+	return func(r *ConnectionUrlResolver) {
+		r.defaultDatabase = db
+		r.hasDefaultDatabase = true
+	}
 }
 
 // NewConnectionUrlResolver initializes a new resolver with a URL string
-func NewConnectionUrlResolver(urlString string) *ConnectionUrlResolver {
+func NewConnectionUrlResolver(urlString string, opts ...Option) *ConnectionUrlResolver {
 	resolver := &ConnectionUrlResolver{
 		urlString: urlString,
 	}
+	for _, opt := range opts {
+		opt(resolver)
+	}
 	resolver.parsed = resolver.parseURL(urlString)
 	return resolver
 }
@@ -78,6 +97,35 @@ func (r *ConnectionUrlResolver) SSLConnectionParams() map[string]bool {
 	}
 }
 
+// driverOptions are URL query parameters that configure the client rather
+// than describe a routing policy, so they are kept out of the routing context.
+var driverOptions = []string{
+	"timeout", "connect_timeout", "connection_timeout", "read_timeout", "write_timeout",
+	"ssl", "tls", "ssc", "sslmode", "verify", "verify_cert", "ca_cert", "trusted_certificates",
+	"user_agent", "fetch_size", "max_connection_pool_size", "max_connection_lifetime",
+}
+
+// RoutingContext returns the routing context the driver sends with ROUTE.
+// Following the Neo4j URI convention query parameters (e.g. policy, region)
+// are forwarded verbatim, except those that configure the driver itself such
+// as timeouts and TLS flags; "address" carries the host:port the client
+// originally dialled.
+func (r *ConnectionUrlResolver) RoutingContext() map[string]interface{} {
+	if r.parsed == nil {
+		return map[string]interface{}{}
+	}
+
+	context := make(map[string]interface{}, len(r.parsed.Options)+1)
+	for key, value := range r.parsed.Options {
+		if contains(driverOptions, strings.ToLower(key)) || strings.HasSuffix(strings.ToLower(key), "_timeout") {
+			continue
+		}
+		context[key] = value
+	}
+	context["address"] = r.Address()
+	return context
+}
+
 // parseURL parses the URL string into a ConnectionConfig structure
 func (r *ConnectionUrlResolver) parseURL(urlString string) *ConnectionConfig {
 	if urlString == "" {
@@ -115,18 +163,10 @@ func (r *ConnectionUrlResolver) parseURL(urlString string) *ConnectionConfig {
 		}
 	}
 
-	// Extract database from path. By default the database name matches the
-	// adapter except for Memgraph which doesn't use a default database.
-	var database string
-	if uri.Path != "" && uri.Path != "/" {
-		database = strings.TrimPrefix(uri.Path, "/")
-		if database == "" {
-			if adapter != "memgraph" {
-				database = adapter
-			}
-		}
-	} else if adapter != "memgraph" {
-		database = adapter
+	// Extract database from path, falling back to the default for the adapter.
+	database := strings.TrimPrefix(uri.Path, "/")
+	if database == "" {
+		database = r.fallbackDatabase(adapter)
 	}
 
 	// Extract username and password
@@ -178,6 +218,19 @@ func (r *ConnectionUrlResolver) parseURL(urlString string) *ConnectionConfig {
 	}
 }
 
+// fallbackDatabase returns the database for a URL without one: the
+// configured default if any, otherwise the adapter name, except for
+// Memgraph which doesn't use a default database.
+func (r *ConnectionUrlResolver) fallbackDatabase(adapter string) string {
+	if r.hasDefaultDatabase {
+		return r.defaultDatabase
+	}
+	if adapter == "memgraph" {
+		return ""
+	}
+	return adapter
+}
+
 // extractAdapterAndModifiers extracts the adapter name and modifiers from the scheme
 func (r *ConnectionUrlResolver) extractAdapterAndModifiers(scheme string) (string, []string, bool) {
 	parts := strings.Split(scheme, "+")
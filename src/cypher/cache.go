@@ -53,8 +53,6 @@ func (c *SimpleCache) Fetch(key string, fn func() string) string {
 	return val
 }
 
-var simpleCache = NewSimpleCache()
-
 // OptimizedCache is a specialized cache keyed by node instances.
 // Thread-safe with RWMutex and FIFO eviction.
 type OptimizedCache struct {
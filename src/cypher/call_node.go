@@ -1,9 +1,16 @@
 package cypher
 
-// ProcedureCallNode represents a CALL of a procedure with optional YIELD items.
+// ProcedureCallNode represents a CALL of a procedure with optional YIELD
+// items. When Arguments is non-nil, Procedure is just the name and the
+// arguments are rendered in parentheses after it, values other than
+// expressions becoming parameters; otherwise Procedure carries its own
+// argument list. WhereConditions filter the yielded rows and are joined
+// with AND.
 type ProcedureCallNode struct {
-	Procedure  interface{}
-	YieldItems []string
+	Procedure       interface{}
+	Arguments       []interface{}
+	YieldItems      []string
+	WhereConditions []interface{}
 }
 
 func (n *ProcedureCallNode) Accept(v Visitor) error {
@@ -21,6 +21,7 @@ const (
 	UnwindClause
 	WhereClause
 	WithClause // Not in current grammar.go but for completeness
+	CreateClause
 	// Add other clause types as they are implemented
 )
 
@@ -44,7 +45,7 @@ func ClauseOrder(c Clause) int {
 	// SET, REMOVE modify data.
 	// RETURN projects results.
 	// SKIP, LIMIT paginate results.
-	// Other clauses like CALL, CREATE, DELETE, WITH, etc., would fit specific spots.
+	// Other clauses like CALL, DELETE, WITH, etc., would fit specific spots.
 	case MatchClause:
 		return 5
 	case MergeClause:
@@ -53,6 +54,8 @@ func ClauseOrder(c Clause) int {
 		return 7
 	case WhereClause: // Often follows MATCH/MERGE/UNWIND
 		return 11
+	case CreateClause: // After the clauses and filter that feed it
+		return 15
 	case SetClause:
 		return 20
 	case RemoveClause:
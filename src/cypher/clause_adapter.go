@@ -1,29 +1,27 @@
 package cypher
 
-import (
-	"fmt"
-	"reflect"
-)
-
 // ClauseAdapter bridges AST nodes with the Clause interface used by Query.
 type ClauseAdapter struct {
 	Node Node
-	key  uintptr
 }
 
 // NewClauseAdapter constructs a ClauseAdapter for a given node.
 func NewClauseAdapter(n Node) *ClauseAdapter {
-	return &ClauseAdapter{Node: n, key: reflect.ValueOf(n).Pointer()}
+	return &ClauseAdapter{Node: n}
 }
 
-// BuildCypher compiles the AST node, using a cache to reuse results.
+// BuildCypher compiles the AST node. The output names parameters registered
+// on q, so it is cached on q itself: building q again reuses it, while a node
+// shared by a query and its clones is compiled once for each.
 func (c *ClauseAdapter) BuildCypher(q *Query) string {
-	cacheKey := fmt.Sprintf("%d:%T", c.key, c.Node)
-	return simpleCache.Fetch(cacheKey, func() string {
-		compiler := NewQueryIntegratedCompiler(q)
-		compiler.Compile(c.Node)
-		return compiler.Output()
-	})
+	if out, ok := q.compiledClause(c); ok {
+		return out
+	}
+	compiler := NewQueryIntegratedCompiler(q)
+	compiler.Compile(c.Node)
+	out := compiler.Output()
+	q.storeCompiledClause(c, out)
+	return out
 }
 
 // Type returns the ClauseType of the underlying Node.
@@ -34,6 +32,8 @@ func (c *ClauseAdapter) Type() ClauseType {
 		return MatchClause
 	case *MergeNode:
 		return MergeClause
+	case *CreateNode:
+		return CreateClause
 	case *UnwindNode:
 		return UnwindClause
 	case *WhereNode:
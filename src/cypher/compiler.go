@@ -2,6 +2,7 @@ package cypher
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/seuros/gopher-cypher/src/optimized"
@@ -13,11 +14,42 @@ type Compiler struct {
 	parameters   map[string]interface{}
 	paramCounter int
 	firstClause  bool
+	indentWidth  int // spaces per nesting level; 0 keeps subqueries inline
+	depth        int
+	// query, when set, receives parameters instead of the compiler's own table.
+	query          *Query
+	inlineLiterals bool
+	scope          *scopeTracker // set by WithScopeValidation
+	adapter        string        // set by WithAdapter
+}
+
+// CompilerOption configures a Compiler.
+type CompilerOption func(*Compiler)
+
+// WithInlineLiterals makes the compiler render values as escaped Cypher
+// literals instead of $pN parameters, for servers or tools that cannot take
+// parameterized queries. No parameters are collected in this mode.
+func WithInlineLiterals() CompilerOption {
+	return func(c *Compiler) { c.inlineLiterals = true }
+}
+
+// WithAdapter targets the output at a database adapter, as named by the
+// connection URL ("neo4j" or "memgraph"). For "memgraph" LOAD CSV uses
+// Memgraph's FROM ... WITH HEADER|NO HEADER ... DELIMITER form and the
+// dbms.procedures and dbms.functions listings become mg.procedures and
+// mg.functions. Parameters are written as $name for both. Any other value,
+// including "", compiles for Neo4j.
+func WithAdapter(adapter string) CompilerOption {
+	return func(c *Compiler) { c.adapter = adapter }
 }
 
 // NewCompiler creates a new compiler instance.
-func NewCompiler() *Compiler {
-	return &Compiler{parameters: make(map[string]interface{}), firstClause: true}
+func NewCompiler(opts ...CompilerOption) *Compiler {
+	c := &Compiler{parameters: make(map[string]interface{}), firstClause: true}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // Output returns the compiled query string.
@@ -27,7 +59,10 @@ func (c *Compiler) Output() string { return c.output.String() }
 func (c *Compiler) Compile(nodes ...Node) (string, map[string]interface{}) {
 	for _, n := range nodes {
 		if !c.firstClause {
-			c.output.WriteByte('\n')
+			c.newline()
+		}
+		if c.scope != nil {
+			c.scope.visit(n)
 		}
 		n.Accept(c)
 		c.firstClause = false
@@ -35,8 +70,17 @@ func (c *Compiler) Compile(nodes ...Node) (string, map[string]interface{}) {
 	return c.output.String(), c.parameters
 }
 
+// newline starts a new line at the current nesting depth.
+func (c *Compiler) newline() {
+	c.output.WriteByte('\n')
+	c.output.WriteString(strings.Repeat(" ", c.depth*c.indentWidth))
+}
+
 // internal helper to register parameters
 func (c *Compiler) registerParameter(val interface{}) string {
+	if c.query != nil {
+		return c.query.RegisterParameter(val)
+	}
 	for k, v := range c.parameters {
 		if v == val {
 			return k
@@ -50,6 +94,10 @@ func (c *Compiler) registerParameter(val interface{}) string {
 
 // VisitLiteralNode renders a literal value.
 func (c *Compiler) VisitLiteralNode(n *LiteralNode) error {
+	if c.inlineLiterals {
+		c.output.WriteString(formatInlineLiteral(n.Value))
+		return nil
+	}
 	key := c.registerParameter(n.Value)
 	c.output.WriteString("$" + key)
 	return nil
@@ -69,6 +117,14 @@ func (c *Compiler) VisitOptimizedLiteralData(n *optimized.LiteralData) error {
 func (c *Compiler) renderExpression(expr interface{}) {
 	switch v := expr.(type) {
 	case Expression:
+		if c.inlineLiterals {
+			c.output.WriteString(v.BuildCypher(&Query{inlineLiterals: true}))
+			return
+		}
+		if c.query != nil {
+			c.output.WriteString(v.BuildCypher(c.query))
+			return
+		}
 		// Create a temporary Query facade for the Expression to use.
 		// This allows Expression.BuildCypher to call RegisterParameter,
 		// which might be overridden by QueryIntegratedCompiler to use its own Query instance.
@@ -90,20 +146,36 @@ func (c *Compiler) renderExpression(expr interface{}) {
 func (c *Compiler) formatArrayLiteral(arr []interface{}) string {
 	parts := make([]string, len(arr))
 	for i, el := range arr {
-		switch v := el.(type) {
-		case string:
-			escaped := strings.ReplaceAll(v, "\\", "\\\\")
-			escaped = strings.ReplaceAll(escaped, "\"", "\\\"")
-			parts[i] = fmt.Sprintf("\"%s\"", escaped)
-		case []interface{}:
-			parts[i] = c.formatArrayLiteral(v)
-		default:
-			parts[i] = fmt.Sprint(v)
-		}
+		parts[i] = c.formatLiteralElement(el)
 	}
 	return "[" + strings.Join(parts, ", ") + "]"
 }
 
+// formatLiteralElement renders one element of a list literal: parameter
+// references stay references, nested lists and maps recurse, and scalars use
+// the inline literal syntax (double-quoted strings, decimal floats).
+func (c *Compiler) formatLiteralElement(el interface{}) string {
+	switch v := el.(type) {
+	case *ParameterExpr:
+		return "$" + v.Name
+	case []interface{}:
+		return c.formatArrayLiteral(v)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, len(keys))
+		for i, k := range keys {
+			parts[i] = quoteCypherKey(k) + ": " + c.formatLiteralElement(v[k])
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	default:
+		return formatInlineLiteral(v)
+	}
+}
+
 // VisitSetNode handles SET clauses
 func (c *Compiler) VisitSetNode(n *SetNode) error {
 	if len(n.Assignments) == 0 {
@@ -167,13 +239,40 @@ func (c *Compiler) VisitReturnNode(n *ReturnNode) error {
 	if n.Distinct {
 		c.output.WriteString("DISTINCT ")
 	}
-	for i, item := range n.Items {
-		if i > 0 {
+	c.renderProjectionItems(n.All, n.Items)
+	c.renderProjectionModifiers(n.OrderBy, n.Skip, n.Limit)
+	return nil
+}
+
+// renderProjectionItems renders the items of a RETURN or WITH, led by * when
+// all variables in scope are projected.
+func (c *Compiler) renderProjectionItems(all bool, items []interface{}) {
+	if all {
+		c.output.WriteByte('*')
+	}
+	for i, item := range items {
+		if i > 0 || all {
 			c.output.WriteString(", ")
 		}
 		c.renderExpression(item)
 	}
-	return nil
+}
+
+// renderProjectionModifiers renders the ORDER BY, SKIP and LIMIT attached to a
+// RETURN or WITH, always in that order.
+func (c *Compiler) renderProjectionModifiers(orderBy *OrderByNode, skip *SkipNode, limit *LimitNode) {
+	if orderBy != nil && len(orderBy.Items) > 0 {
+		c.output.WriteByte(' ')
+		orderBy.Accept(c)
+	}
+	if skip != nil {
+		c.output.WriteByte(' ')
+		skip.Accept(c)
+	}
+	if limit != nil {
+		c.output.WriteByte(' ')
+		limit.Accept(c)
+	}
 }
 
 // VisitWithNode handles WITH clauses
@@ -182,22 +281,25 @@ func (c *Compiler) VisitWithNode(n *WithNode) error {
 	if n.Distinct {
 		c.output.WriteString("DISTINCT ")
 	}
-	for i, item := range n.Items {
-		if i > 0 {
-			c.output.WriteString(", ")
-		}
-		c.renderExpression(item)
+	c.renderProjectionItems(n.All, n.Items)
+	c.renderProjectionModifiers(n.OrderBy, n.Skip, n.Limit)
+	c.renderWhereConditions(n.WhereConditions)
+	return nil
+}
+
+// renderWhereConditions renders the WHERE attached to a WITH or a procedure
+// call on its own line, joining the conditions with AND.
+func (c *Compiler) renderWhereConditions(conditions []interface{}) {
+	if len(conditions) == 0 {
+		return
 	}
-	if len(n.WhereConditions) > 0 {
-		c.output.WriteString("\nWHERE ")
-		for i, cond := range n.WhereConditions {
-			if i > 0 {
-				c.output.WriteString(" AND ")
-			}
-			c.renderExpression(cond)
+	c.output.WriteString("\nWHERE ")
+	for i, cond := range conditions {
+		if i > 0 {
+			c.output.WriteString(" AND ")
 		}
+		c.renderExpression(cond)
 	}
-	return nil
 }
 
 // VisitUnwindNode handles UNWIND clauses
@@ -289,11 +391,21 @@ func (c *Compiler) VisitOrderByNode(n *OrderByNode) error {
 
 // VisitMatchNode handles MATCH clauses
 func (c *Compiler) VisitMatchNode(n *MatchNode) error {
+	if n.Optional {
+		c.output.WriteString("OPTIONAL ")
+	}
 	c.output.WriteString("MATCH ")
 	c.renderExpression(n.Pattern)
 	return nil
 }
 
+// VisitCreateNode handles CREATE clauses
+func (c *Compiler) VisitCreateNode(n *CreateNode) error {
+	c.output.WriteString("CREATE ")
+	c.renderExpression(n.Pattern)
+	return nil
+}
+
 // VisitMergeNode handles MERGE clauses
 func (c *Compiler) VisitMergeNode(n *MergeNode) error {
 	c.output.WriteString("MERGE ")
@@ -302,13 +414,37 @@ func (c *Compiler) VisitMergeNode(n *MergeNode) error {
 		c.output.WriteString(" ON CREATE ")
 		n.OnCreate.Accept(c)
 	}
+	if n.OnMatch != nil {
+		c.output.WriteString(" ON MATCH ")
+		n.OnMatch.Accept(c)
+	}
 	return nil
 }
 
 // VisitProcedureCallNode handles CALL procedure clauses
 func (c *Compiler) VisitProcedureCallNode(n *ProcedureCallNode) error {
 	c.output.WriteString("CALL ")
-	c.renderExpression(n.Procedure)
+	procedure := n.Procedure
+	if name, ok := procedure.(string); ok && c.adapter == "memgraph" {
+		procedure = memgraphProcedure(name)
+	}
+	c.renderExpression(procedure)
+	if n.Arguments != nil {
+		c.output.WriteByte('(')
+		for i, arg := range n.Arguments {
+			if i > 0 {
+				c.output.WriteString(", ")
+			}
+			switch arg.(type) {
+			case Expression, Node:
+				c.renderExpression(arg)
+			default:
+				// Unlike other positions, a string argument is a value.
+				c.VisitLiteralNode(&LiteralNode{Value: arg})
+			}
+		}
+		c.output.WriteByte(')')
+	}
 	if len(n.YieldItems) > 0 {
 		c.output.WriteString(" YIELD ")
 		for i, y := range n.YieldItems {
@@ -318,12 +454,24 @@ func (c *Compiler) VisitProcedureCallNode(n *ProcedureCallNode) error {
 			c.output.WriteString(y)
 		}
 	}
+	c.renderWhereConditions(n.WhereConditions)
 	return nil
 }
 
 // VisitCallSubqueryNode handles CALL { ... } subqueries
 func (c *Compiler) VisitCallSubqueryNode(n *CallSubqueryNode) error {
 	c.output.WriteString("CALL {")
+	if c.indentWidth > 0 {
+		c.depth++
+		for _, node := range n.Body {
+			c.newline()
+			node.Accept(c)
+		}
+		c.depth--
+		c.newline()
+		c.output.WriteByte('}')
+		return nil
+	}
 	origFirst := c.firstClause
 	c.firstClause = true
 	for i, node := range n.Body {
@@ -358,6 +506,9 @@ func (c *Compiler) VisitDeleteNode(n *DeleteNode) error {
 
 // VisitLoadCSVNode handles LOAD CSV clauses
 func (c *Compiler) VisitLoadCSVNode(n *LoadCSVNode) error {
+	if c.adapter == "memgraph" {
+		return c.visitMemgraphLoadCSV(n)
+	}
 	c.output.WriteString("LOAD CSV ")
 	if n.WithHeaders {
 		c.output.WriteString("WITH HEADERS ")
@@ -368,5 +519,50 @@ func (c *Compiler) VisitLoadCSVNode(n *LoadCSVNode) error {
 		c.output.WriteString(" AS ")
 		c.output.WriteString(n.As)
 	}
+	if n.FieldTerminator != "" {
+		c.output.WriteString(" FIELDTERMINATOR ")
+		c.output.WriteString(quoteCypherStringWith(n.FieldTerminator, '\''))
+	}
 	return nil
 }
+
+// visitMemgraphLoadCSV renders LOAD CSV in Memgraph's syntax, where the
+// header clause is mandatory and follows the source, and the field
+// terminator is a DELIMITER placed before AS.
+func (c *Compiler) visitMemgraphLoadCSV(n *LoadCSVNode) error {
+	c.output.WriteString("LOAD CSV FROM ")
+	c.renderExpression(n.From)
+	if n.WithHeaders {
+		c.output.WriteString(" WITH HEADER")
+	} else {
+		c.output.WriteString(" NO HEADER")
+	}
+	if n.FieldTerminator != "" {
+		c.output.WriteString(" DELIMITER ")
+		c.output.WriteString(quoteCypherStringWith(n.FieldTerminator, '\''))
+	}
+	if n.As != "" {
+		c.output.WriteString(" AS ")
+		c.output.WriteString(n.As)
+	}
+	return nil
+}
+
+// memgraphProcedures maps Neo4j procedures to their Memgraph equivalents.
+var memgraphProcedures = map[string]string{
+	"dbms.procedures": "mg.procedures",
+	"dbms.functions":  "mg.functions",
+}
+
+// memgraphProcedure renames a Neo4j procedure, given with or without its
+// argument list, to the Memgraph equivalent if there is one.
+func memgraphProcedure(name string) string {
+	proc, args := name, ""
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		proc, args = name[:i], name[i:]
+	}
+	if mapped, ok := memgraphProcedures[strings.TrimSpace(proc)]; ok {
+		return mapped + args
+	}
+	return name
+}
@@ -0,0 +1,18 @@
+package cypher
+
+// CreateNode represents a CREATE clause.
+type CreateNode struct {
+	Pattern interface{}
+}
+
+func (n *CreateNode) Accept(v Visitor) error {
+	if vv, ok := v.(interface{ VisitCreateNode(*CreateNode) error }); ok {
+		return vv.VisitCreateNode(n)
+	}
+	return nil
+}
+
+// Type returns the ClauseType for CreateNode.
+func (n *CreateNode) Type() ClauseType {
+	return CreateClause
+}
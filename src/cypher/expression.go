@@ -1,6 +1,9 @@
 package cypher
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Expression defines any value that can appear in a Cypher statement.
 type Expression interface {
@@ -16,9 +19,75 @@ type ComparisonExpr struct {
 	Op  string
 }
 
+// comparisonOperators maps each legal comparison operator to whether it
+// takes a right-hand operand.
+var comparisonOperators = map[string]bool{
+	"=":           true,
+	"<>":          true,
+	"<":           true,
+	"<=":          true,
+	">":           true,
+	">=":          true,
+	"IN":          true,
+	"CONTAINS":    true,
+	"STARTS WITH": true,
+	"ENDS WITH":   true,
+	"=~":          true,
+	"IS NULL":     false,
+	"IS NOT NULL": false,
+}
+
+// normalizeOperator returns the canonical spelling of op (upper case, single
+// spaces) and whether it is a legal comparison operator.
+func normalizeOperator(op string) (string, bool) {
+	canonical := strings.ToUpper(strings.Join(strings.Fields(op), " "))
+	_, ok := comparisonOperators[canonical]
+	return canonical, ok
+}
+
+// Validate reports an error if Op is not a legal Cypher comparison operator.
+func (e *ComparisonExpr) Validate() error {
+	if _, ok := normalizeOperator(e.Op); !ok {
+		return fmt.Errorf("cypher: invalid comparison operator %q", e.Op)
+	}
+	return nil
+}
+
 // BuildCypher implements the Expression interface for ComparisonExpr.
+// It panics if Op is not a legal operator, since rendering it verbatim
+// would produce broken or injectable Cypher; call Validate first to handle
+// untrusted operators gracefully. IS NULL and IS NOT NULL ignore RHS.
 func (e *ComparisonExpr) BuildCypher(q *Query) string {
-	return e.LHS.BuildCypher(q) + " " + e.Op + " " + e.RHS.BuildCypher(q)
+	op, ok := normalizeOperator(e.Op)
+	if !ok {
+		panic(e.Validate())
+	}
+	if !comparisonOperators[op] {
+		return e.LHS.BuildCypher(q) + " " + op
+	}
+	return e.LHS.BuildCypher(q) + " " + op + " " + e.RHS.BuildCypher(q)
+}
+
+// In returns a comparison testing lhs for membership in values (e.g.
+// n.id IN $p1). The whole slice is registered as a single list parameter,
+// so the query text does not grow with the number of values.
+func In(lhs Expression, values interface{}) *ComparisonExpr {
+	return &ComparisonExpr{LHS: lhs, Op: "IN", RHS: &LiteralExpr{Value: values}}
+}
+
+// NullCheckExpr represents a null test (e.g., n.deleted IS NULL). When
+// Negated is set it renders as IS NOT NULL.
+type NullCheckExpr struct {
+	Operand Expression
+	Negated bool
+}
+
+// BuildCypher implements the Expression interface for NullCheckExpr.
+func (e *NullCheckExpr) BuildCypher(q *Query) string {
+	if e.Negated {
+		return e.Operand.BuildCypher(q) + " IS NOT NULL"
+	}
+	return e.Operand.BuildCypher(q) + " IS NULL"
 }
 
 // PropertyAccessExpr represents accessing a property on a variable (e.g., n.name).
@@ -39,8 +108,29 @@ type LiteralExpr struct {
 
 // BuildCypher implements the Expression interface for LiteralExpr.
 func (e *LiteralExpr) BuildCypher(q *Query) string {
-	paramKey := q.RegisterParameter(e.Value)
-	return fmt.Sprintf("$%s", paramKey)
+	return q.placeholder(e.Value)
+}
+
+// ParameterExpr references a query parameter supplied by the caller (e.g.
+// $rows). It renders as-is and registers nothing, unlike LiteralExpr.
+type ParameterExpr struct {
+	Name string
+}
+
+// BuildCypher implements the Expression interface for ParameterExpr.
+func (e *ParameterExpr) BuildCypher(q *Query) string {
+	return "$" + e.Name
+}
+
+// VariableExpr references a variable bound earlier in the query (e.g. n in
+// count(n)). Like ParameterExpr it renders as-is and registers nothing.
+type VariableExpr struct {
+	Name string
+}
+
+// BuildCypher implements the Expression interface for VariableExpr.
+func (e *VariableExpr) BuildCypher(q *Query) string {
+	return e.Name
 }
 
 // FunctionCallExpr represents a function call (e.g., collect(n), coalesce(a, b)).
@@ -56,13 +146,34 @@ func (e *FunctionCallExpr) BuildCypher(q *Query) string {
 		if i > 0 {
 			result += ", "
 		}
-		paramKey := q.RegisterParameter(arg)
-		result += fmt.Sprintf("$%s", paramKey)
+		if expr, ok := arg.(Expression); ok {
+			result += expr.BuildCypher(q)
+		} else {
+			result += q.placeholder(arg)
+		}
 	}
 	result += ")"
 	return result
 }
 
+// CountExpr is a COUNT { ... } subquery counting the rows its body matches
+// (e.g. COUNT { MATCH (n)-[:KNOWS]->() }). The body is compiled against the
+// enclosing query, so its parameters continue the query's numbering.
+type CountExpr struct {
+	Body []Node
+}
+
+// BuildCypher implements the Expression interface for CountExpr.
+func (e *CountExpr) BuildCypher(q *Query) string {
+	parts := make([]string, len(e.Body))
+	for i, n := range e.Body {
+		compiler := NewQueryIntegratedCompiler(q)
+		compiler.Compile(n)
+		parts[i] = compiler.Output()
+	}
+	return "COUNT { " + strings.Join(parts, " ") + " }"
+}
+
 // AliasExpr represents an expression with an alias (e.g., expr AS alias).
 type AliasExpr struct {
 	Expression interface{}
@@ -75,8 +186,7 @@ func (e *AliasExpr) BuildCypher(q *Query) string {
 	if expr, ok := e.Expression.(Expression); ok {
 		exprStr = expr.BuildCypher(q)
 	} else {
-		paramKey := q.RegisterParameter(e.Expression)
-		exprStr = fmt.Sprintf("$%s", paramKey)
+		exprStr = q.placeholder(e.Expression)
 	}
 	return exprStr + " AS " + e.Alias
 }
@@ -90,15 +200,5 @@ type MathExpr struct {
 
 // BuildCypher implements the Expression interface for MathExpr.
 func (e *MathExpr) BuildCypher(q *Query) string {
-	leftStr := ""
-	rightStr := ""
-
-	if leftParam := q.RegisterParameter(e.Left); leftParam != "" {
-		leftStr = "$" + leftParam
-	}
-	if rightParam := q.RegisterParameter(e.Right); rightParam != "" {
-		rightStr = "$" + rightParam
-	}
-
-	return leftStr + " " + e.Operator + " " + rightStr
+	return q.placeholder(e.Left) + " " + e.Operator + " " + q.placeholder(e.Right)
 }
@@ -0,0 +1,105 @@
+package cypher
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FormatOptions controls how Format lays out a query.
+type FormatOptions struct {
+	// IndentWidth is the number of spaces used per nesting level for
+	// CALL { ... } bodies. Zero keeps subqueries on a single line.
+	IndentWidth int
+	// UppercaseKeywords rewrites Cypher keywords found in verbatim
+	// expression text (identifiers and literals are left untouched).
+	UppercaseKeywords bool
+}
+
+// Format renders q as human-readable Cypher: one clause per line, aligned at
+// the left margin, with subquery bodies indented by opts.IndentWidth.
+// Unlike BuildCypher it bypasses the clause cache, since the cached output
+// is the compact form.
+func Format(q *Query, opts FormatOptions) string {
+	clauses := q.orderedClauses()
+
+	lines := make([]string, 0, len(clauses))
+	for _, c := range clauses {
+		adapter, ok := c.(*ClauseAdapter)
+		if !ok {
+			lines = append(lines, c.BuildCypher(q))
+			continue
+		}
+		compiler := NewQueryIntegratedCompiler(q)
+		compiler.indentWidth = opts.IndentWidth
+		compiler.Compile(adapter.Node)
+		lines = append(lines, compiler.Output())
+	}
+
+	out := strings.Join(lines, "\n")
+	if opts.UppercaseKeywords {
+		out = uppercaseKeywords(out)
+	}
+	return out
+}
+
+// cypherKeywords lists the reserved words normalized by uppercaseKeywords.
+var cypherKeywords = map[string]bool{
+	"MATCH": true, "OPTIONAL": true, "WHERE": true, "RETURN": true,
+	"WITH": true, "UNWIND": true, "AS": true, "CALL": true, "YIELD": true,
+	"CREATE": true, "MERGE": true, "ON": true, "SET": true, "REMOVE": true,
+	"DELETE": true, "DETACH": true, "ORDER": true, "BY": true, "SKIP": true,
+	"LIMIT": true, "ASC": true, "DESC": true, "DISTINCT": true, "AND": true,
+	"OR": true, "XOR": true, "NOT": true, "IN": true, "IS": true,
+	"NULL": true, "TRUE": true, "FALSE": true, "FOREACH": true, "LOAD": true,
+	"CSV": true, "HEADERS": true, "FROM": true, "CASE": true, "WHEN": true,
+	"THEN": true, "ELSE": true, "END": true, "UNION": true, "STARTS": true,
+	"ENDS": true, "CONTAINS": true,
+}
+
+// uppercaseKeywords uppercases keywords outside string literals and
+// backtick-quoted names. Words following '.', ':' or '$' are property keys,
+// labels and parameters, so they keep their casing.
+func uppercaseKeywords(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '\'' || r == '"' || r == '`':
+			j := i + 1
+			for j < len(runes) && runes[j] != r {
+				if runes[j] == '\\' && r != '`' {
+					j++
+				}
+				j++
+			}
+			if j < len(runes) {
+				j++
+			}
+			if j > len(runes) {
+				j = len(runes)
+			}
+			b.WriteString(string(runes[i:j]))
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			word := string(runes[i:j])
+			upper := strings.ToUpper(word)
+			qualified := i > 0 && (runes[i-1] == '.' || runes[i-1] == ':' || runes[i-1] == '$')
+			if cypherKeywords[upper] && !qualified {
+				b.WriteString(upper)
+			} else {
+				b.WriteString(word)
+			}
+			i = j
+		default:
+			b.WriteRune(r)
+			i++
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,60 @@
+package cypher
+
+import "testing"
+
+func TestFormatIndentsSubqueryBody(t *testing.T) {
+	q := NewQuery()
+	q.AddClause(NewClauseAdapter(&ReturnNode{Items: []interface{}{"total"}}))
+	q.AddClause(NewClauseAdapter(&CallSubqueryNode{Body: []Node{
+		&MatchNode{Pattern: "(n:User)"},
+		&ReturnNode{Items: []interface{}{"count(n) AS total"}},
+	}}))
+
+	tests := []struct {
+		width int
+		want  string
+	}{
+		{2, "CALL {\n  MATCH (n:User)\n  RETURN count(n) AS total\n}\nRETURN total"},
+		{4, "CALL {\n    MATCH (n:User)\n    RETURN count(n) AS total\n}\nRETURN total"},
+	}
+	for _, tt := range tests {
+		if got := Format(q, FormatOptions{IndentWidth: tt.width}); got != tt.want {
+			t.Errorf("IndentWidth %d:\ngot  %q\nwant %q", tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestFormatNestedSubquery(t *testing.T) {
+	q := NewQuery()
+	q.AddClause(NewClauseAdapter(&CallSubqueryNode{Body: []Node{
+		&CallSubqueryNode{Body: []Node{&ReturnNode{Items: []interface{}{"1 AS x"}}}},
+		&ReturnNode{Items: []interface{}{"x"}},
+	}}))
+
+	want := "CALL {\n  CALL {\n    RETURN 1 AS x\n  }\n  RETURN x\n}"
+	if got := Format(q, FormatOptions{IndentWidth: 2}); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestFormatZeroIndentMatchesBuildCypher(t *testing.T) {
+	q := NewQuery()
+	q.AddClause(NewClauseAdapter(&CallSubqueryNode{Body: []Node{&ReturnNode{Items: []interface{}{"1 AS x"}}}}))
+	q.AddClause(NewClauseAdapter(&ReturnNode{Items: []interface{}{"x"}}))
+
+	built, _ := q.BuildCypher()
+	if got := Format(q, FormatOptions{}); got != built {
+		t.Errorf("got %q, want %q", got, built)
+	}
+}
+
+func TestFormatUppercaseKeywords(t *testing.T) {
+	q := NewQuery()
+	q.AddClause(NewClauseAdapter(&MatchNode{Pattern: "(n:match {name: 'where'})"}))
+	q.AddClause(NewClauseAdapter(&ReturnNode{Items: []interface{}{"n.order as total"}}))
+
+	want := "MATCH (n:match {name: 'where'})\nRETURN n.order AS total"
+	if got := Format(q, FormatOptions{UppercaseKeywords: true}); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
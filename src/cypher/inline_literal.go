@@ -0,0 +1,143 @@
+package cypher
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// formatInlineLiteral renders value as a Cypher literal. Strings are double
+// quoted with backslashes, quotes and control characters escaped, so the
+// result is safe to splice into a query.
+func formatInlineLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return quoteCypherString(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.FormatInt(int64(v), 10)
+	case int8:
+		return strconv.FormatInt(int64(v), 10)
+	case int16:
+		return strconv.FormatInt(int64(v), 10)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float32:
+		return formatFloatLiteral(float64(v))
+	case float64:
+		return formatFloatLiteral(v)
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, el := range v {
+			parts[i] = formatInlineLiteral(el)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, len(keys))
+		for i, k := range keys {
+			parts[i] = quoteCypherKey(k) + ": " + formatInlineLiteral(v[k])
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	}
+
+	// Typed slices ([]string, []int, ...) render like []interface{}.
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Slice {
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = rv.Index(i).Interface()
+		}
+		return formatInlineLiteral(items)
+	}
+	return quoteCypherString(fmt.Sprint(value))
+}
+
+// formatFloatLiteral keeps floats distinguishable from integers (1.0, not 1).
+func formatFloatLiteral(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	}
+	// Plain decimals read better than 1e+06; only extreme magnitudes fall
+	// back to exponent notation, which Cypher also accepts.
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	s := strconv.FormatFloat(f, format, -1, 64)
+	if !strings.ContainsAny(s, ".eE") {
+		s += ".0"
+	}
+	return s
+}
+
+func quoteCypherString(s string) string {
+	return quoteCypherStringWith(s, '"')
+}
+
+// quoteCypherStringWith quotes s with the given quote character, which is
+// escaped along with backslashes and control characters.
+func quoteCypherStringWith(s string, quote rune) string {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+	b.WriteRune(quote)
+	for _, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case quote:
+			b.WriteRune('\\')
+			b.WriteRune(quote)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteRune(quote)
+	return b.String()
+}
+
+// quoteCypherKey backtick-quotes map keys that are not plain identifiers.
+func quoteCypherKey(k string) string {
+	for i, r := range k {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9' {
+			continue
+		}
+		return "`" + strings.ReplaceAll(k, "`", "``") + "`"
+	}
+	if k == "" {
+		return "``"
+	}
+	return k
+}
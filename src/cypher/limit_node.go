@@ -2,9 +2,19 @@ package cypher
 
 // LimitNode represents a LIMIT clause.
 type LimitNode struct {
+	// Expression is the row count. A string is written as raw Cypher (e.g.
+	// "$limit"), so it must not carry untrusted input; any other value is
+	// rendered as a literal: a parameter, or inline under
+	// WithInlineLiterals. Prefer Limit for a count known in Go.
 	Expression interface{}
 }
 
+// Limit returns a LIMIT clause whose count is rendered as a literal, never as
+// raw Cypher: a parameter by default, or inline under WithInlineLiterals.
+func Limit(n int) *LimitNode {
+	return &LimitNode{Expression: n}
+}
+
 func (n *LimitNode) Accept(v Visitor) error {
 	if vv, ok := v.(interface{ VisitLimitNode(*LimitNode) error }); ok {
 		return vv.VisitLimitNode(n)
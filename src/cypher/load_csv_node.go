@@ -1,10 +1,38 @@
 package cypher
 
-// LoadCSVNode represents a LOAD CSV clause.
+import (
+	"fmt"
+	"strings"
+)
+
+// LoadCSVNode represents a LOAD CSV clause. FieldTerminator, when set, is
+// rendered as a quoted FIELDTERMINATOR literal (e.g. "\t" for TSV files).
 type LoadCSVNode struct {
-	WithHeaders bool
-	From        interface{}
-	As          string
+	WithHeaders     bool
+	From            interface{}
+	As              string
+	FieldTerminator string
+	Options         LoadCSVOptions
+}
+
+// LoadCSVCompression identifies how a LOAD CSV source is compressed.
+type LoadCSVCompression string
+
+const (
+	// CompressionAuto infers the compression from the source URL's suffix.
+	CompressionAuto LoadCSVCompression = ""
+	CompressionNone LoadCSVCompression = "none"
+	CompressionGzip LoadCSVCompression = "gzip"
+	CompressionZip  LoadCSVCompression = "zip"
+)
+
+// LoadCSVOptions describes a LOAD CSV source beyond what the clause itself
+// can express. It never changes the compiled Cypher; it only feeds
+// CompressionAdvisory.
+type LoadCSVOptions struct {
+	// Compression declares the source's compression, for URLs whose suffix
+	// does not reveal it (or a source given as a parameter).
+	Compression LoadCSVCompression
 }
 
 func (n *LoadCSVNode) Accept(v Visitor) error {
@@ -13,3 +41,62 @@ func (n *LoadCSVNode) Accept(v Visitor) error {
 	}
 	return nil
 }
+
+// CompressionAdvisory warns about a compressed source the server is unlikely
+// to decompress. Neo4j only unpacks gzip and ZIP archives read from local
+// file:/// URLs; a remote archive is read as raw bytes and fails or yields
+// garbage rows. It returns "" when there is nothing to report.
+func (n *LoadCSVNode) CompressionAdvisory() string {
+	url, known := loadCSVSource(n.From)
+	compression := n.Options.Compression
+	if compression == CompressionAuto && known {
+		compression = compressionFromURL(url)
+	}
+	if compression != CompressionGzip && compression != CompressionZip {
+		return ""
+	}
+	if known && strings.HasPrefix(strings.ToLower(url), "file:") {
+		return ""
+	}
+
+	source := "the LOAD CSV source"
+	if known {
+		source = url
+	}
+	return fmt.Sprintf("%s is %s-compressed; the server only decompresses local file:/// archives, so serve it uncompressed or copy it into the import directory", source, compression)
+}
+
+// loadCSVSource returns the URL of a literal LOAD CSV source, reporting false
+// for parameters and other expressions.
+func loadCSVSource(from interface{}) (string, bool) {
+	switch v := from.(type) {
+	case string:
+		s := strings.TrimSpace(v)
+		if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+			return s[1 : len(s)-1], true
+		}
+	case *LiteralExpr:
+		if s, ok := v.Value.(string); ok {
+			return s, true
+		}
+	case *LiteralNode:
+		if s, ok := v.Value.(string); ok {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+func compressionFromURL(url string) LoadCSVCompression {
+	path := strings.ToLower(url)
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	switch {
+	case strings.HasSuffix(path, ".gz"), strings.HasSuffix(path, ".gzip"):
+		return CompressionGzip
+	case strings.HasSuffix(path, ".zip"):
+		return CompressionZip
+	}
+	return CompressionNone
+}
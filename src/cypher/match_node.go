@@ -1,8 +1,10 @@
 package cypher
 
-// MatchNode represents a MATCH clause.
+// MatchNode represents a MATCH clause. When Optional is set the clause is
+// rendered as OPTIONAL MATCH.
 type MatchNode struct {
-	Pattern interface{}
+	Pattern  interface{}
+	Optional bool
 }
 
 func (n *MatchNode) Accept(v Visitor) error {
@@ -1,9 +1,11 @@
 package cypher
 
-// MergeNode represents a MERGE clause with optional ON CREATE actions.
+// MergeNode represents a MERGE clause with optional ON CREATE and ON MATCH
+// actions. ON CREATE is always rendered before ON MATCH.
 type MergeNode struct {
 	Pattern  interface{}
-	OnCreate Node // optional clause executed on CREATE
+	OnCreate Node     // optional clause executed on CREATE
+	OnMatch  *SetNode // optional SET executed when the pattern already exists
 }
 
 func (n *MergeNode) Accept(v Visitor) error {
@@ -2,6 +2,8 @@ package cypher
 
 import (
 	"reflect"
+	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -201,6 +203,82 @@ func TestComparisonExprBuildCypher(t *testing.T) {
 	}
 }
 
+func TestIn(t *testing.T) {
+	q := NewQuery()
+	ids := []int{1, 2, 3}
+	expr := In(&PropertyAccessExpr{Variable: &VariableExpr{Name: "n"}, PropertyName: "id"}, ids)
+	if out := expr.BuildCypher(q); out != "n.id IN $p1" {
+		t.Errorf("expected n.id IN $p1, got %s", out)
+	}
+	if !reflect.DeepEqual(q.parameters, map[string]interface{}{"p1": ids}) {
+		t.Errorf("expected the slice as one parameter, got %v", q.parameters)
+	}
+
+	// A second slice of the same type must not panic on the dedup lookup.
+	other := In(&PropertyAccessExpr{Variable: &VariableExpr{Name: "m"}, PropertyName: "id"}, []int{4})
+	if out := other.BuildCypher(q); out != "m.id IN $p2" {
+		t.Errorf("expected m.id IN $p2, got %s", out)
+	}
+}
+
+func TestComparisonExprValidOperators(t *testing.T) {
+	tests := []struct {
+		op       string
+		expected string
+	}{
+		{"=", "$p1.v = $p2"},
+		{"<>", "$p1.v <> $p2"},
+		{"<", "$p1.v < $p2"},
+		{"<=", "$p1.v <= $p2"},
+		{">", "$p1.v > $p2"},
+		{">=", "$p1.v >= $p2"},
+		{"IN", "$p1.v IN $p2"},
+		{"CONTAINS", "$p1.v CONTAINS $p2"},
+		{"STARTS WITH", "$p1.v STARTS WITH $p2"},
+		{"ENDS WITH", "$p1.v ENDS WITH $p2"},
+		{"=~", "$p1.v =~ $p2"},
+		{"IS NULL", "$p1.v IS NULL"},
+		{"IS NOT NULL", "$p1.v IS NOT NULL"},
+		{"starts  with", "$p1.v STARTS WITH $p2"},
+	}
+	for _, tt := range tests {
+		expr := &ComparisonExpr{
+			LHS: &PropertyAccessExpr{Variable: &LiteralExpr{Value: "n"}, PropertyName: "v"},
+			Op:  tt.op,
+			RHS: &LiteralExpr{Value: "x"},
+		}
+		if err := expr.Validate(); err != nil {
+			t.Errorf("op %q: unexpected error: %v", tt.op, err)
+			continue
+		}
+		if got := expr.BuildCypher(NewQuery()); got != tt.expected {
+			t.Errorf("op %q: expected %q, got %q", tt.op, tt.expected, got)
+		}
+	}
+}
+
+func TestComparisonExprRejectsInvalidOperator(t *testing.T) {
+	expr := &ComparisonExpr{
+		LHS: &LiteralExpr{Value: 1},
+		Op:  "=> 1 RETURN 1 //",
+		RHS: &LiteralExpr{Value: 2},
+	}
+	if err := expr.Validate(); err == nil {
+		t.Fatal("expected Validate to reject the operator")
+	}
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("expected BuildCypher to panic")
+		}
+		if err, ok := r.(error); !ok || !strings.Contains(err.Error(), "invalid comparison operator") {
+			t.Errorf("unexpected panic value: %v", r)
+		}
+	}()
+	expr.BuildCypher(NewQuery())
+}
+
 func TestSetNodeLabel(t *testing.T) {
 	node := &SetNode{Assignments: []SetAssignment{LabelAssignment{"n", "Person"}}}
 	out, _ := compileNode(node)
@@ -248,6 +326,25 @@ func TestWithNodeWhere(t *testing.T) {
 	}
 }
 
+func TestProjectionAll(t *testing.T) {
+	out, _ := compileNode(&ReturnNode{All: true})
+	if out != "RETURN *" {
+		t.Fatalf("got %s", out)
+	}
+	out, _ = compileNode(&WithNode{All: true, Items: []interface{}{"n.age AS age"}, WhereConditions: []interface{}{"age > 30"}})
+	if out != "WITH *, n.age AS age\nWHERE age > 30" {
+		t.Fatalf("got %s", out)
+	}
+
+	// WITH * keeps every variable in scope for the clauses after it.
+	q := NewQuery()
+	q.PreserveClauseOrder()
+	q.Append(&MatchNode{Pattern: "(n)-[r]->(m)"}, &WithNode{All: true}, &ReturnNode{Items: []interface{}{"n", "r", "m"}})
+	if errs := q.Validate(); len(errs) != 0 {
+		t.Errorf("expected WITH * to keep n, r and m in scope, got %v", errs)
+	}
+}
+
 func TestUnwindNode(t *testing.T) {
 	node := &UnwindNode{Expression: []interface{}{1, 2}, AliasName: "x"}
 	out, _ := compileNode(node)
@@ -456,6 +553,33 @@ func TestLimitNode(t *testing.T) {
 	}
 }
 
+func TestLimitAndSkipConstructors(t *testing.T) {
+	out, params := compileNode(Limit(10))
+	if out != "LIMIT $p1" || params["p1"] != 10 {
+		t.Fatalf("got %s %v", out, params)
+	}
+	out, params = compileNode(Skip(20))
+	if out != "SKIP $p1" || params["p1"] != 20 {
+		t.Fatalf("got %s %v", out, params)
+	}
+
+	// A string is raw Cypher and registers nothing.
+	out, params = compileNode(&LimitNode{Expression: "10"})
+	if out != "LIMIT 10" || len(params) != 0 {
+		t.Fatalf("got %s %v", out, params)
+	}
+	out, params = compileNode(&SkipNode{Amount: "$offset"})
+	if out != "SKIP $offset" || len(params) != 0 {
+		t.Fatalf("got %s %v", out, params)
+	}
+	// Under WithInlineLiterals the count is written inline instead.
+	c := NewCompiler(WithInlineLiterals())
+	c.Compile(Limit(10))
+	if out := c.Output(); out != "LIMIT 10" || len(c.parameters) != 0 {
+		t.Fatalf("got %s %v", out, c.parameters)
+	}
+}
+
 func TestOrderByNode(t *testing.T) {
 	items := []OrderByItem{{Expression: "n.name", Direction: "asc"}, {Expression: "n.age", Direction: "desc"}}
 	node := &OrderByNode{Items: items}
@@ -481,6 +605,44 @@ func TestMatchNode(t *testing.T) {
 	}
 }
 
+func TestOptionalMatchNode(t *testing.T) {
+	node := &MatchNode{Pattern: "(n)-[r]->(m)", Optional: true}
+	out, _ := compileNode(node)
+	if out != "OPTIONAL MATCH (n)-[r]->(m)" {
+		t.Fatalf("got %s", out)
+	}
+}
+
+func TestCreateNode(t *testing.T) {
+	node := &CreateNode{Pattern: "(a)-[:KNOWS]->(b)"}
+	out, _ := compileNode(node)
+	if out != "CREATE (a)-[:KNOWS]->(b)" {
+		t.Fatalf("got %s", out)
+	}
+}
+
+func TestBulkInsertWithUnwindCreateSet(t *testing.T) {
+	q := NewQuery()
+	// Added out of order on purpose: clause ordering puts CREATE between
+	// UNWIND and SET.
+	q.AddClause(NewClauseAdapter(&SetNode{Assignments: []SetAssignment{
+		VariablePropertiesAssignment{Variable: "n", Value: "row"},
+	}}))
+	q.AddClause(NewClauseAdapter(&CreateNode{Pattern: "(n)"}))
+	q.AddClause(NewClauseAdapter(&UnwindNode{Expression: &ParameterExpr{Name: "rows"}, AliasName: "row"}))
+
+	out, params := q.BuildCypher()
+	if out != "UNWIND $rows AS row\nCREATE (n)\nSET n = row" {
+		t.Fatalf("got %q", out)
+	}
+	if len(params) != 0 {
+		t.Errorf("expected the variable reference not to be parameterized, got %v", params)
+	}
+	if errs := q.Validate(); len(errs) != 0 {
+		t.Errorf("expected a valid query, got %v", errs)
+	}
+}
+
 func TestMergeNode(t *testing.T) {
 	set := &SetNode{Assignments: []SetAssignment{PropertyAssignment{"n.created_at", 42}}}
 	node := &MergeNode{Pattern: "(n)", OnCreate: set}
@@ -493,6 +655,51 @@ func TestMergeNode(t *testing.T) {
 	}
 }
 
+func TestMergeNodeOnCreateAndOnMatch(t *testing.T) {
+	node := &MergeNode{
+		Pattern:  "(n:User {email: \"a@example.com\"})",
+		OnMatch:  &SetNode{Assignments: []SetAssignment{PropertyAssignment{"n.seen_at", 2}}},
+		OnCreate: &SetNode{Assignments: []SetAssignment{PropertyAssignment{"n.created_at", 1}}},
+	}
+	out, params := compileNode(node)
+	want := "MERGE (n:User {email: \"a@example.com\"}) ON CREATE SET n.created_at = $p1 ON MATCH SET n.seen_at = $p2"
+	if out != want {
+		t.Fatalf("got %s", out)
+	}
+	if params["p1"] != 1 || params["p2"] != 2 {
+		t.Fatalf("params %v", params)
+	}
+}
+
+func TestReturnNodeWithModifiers(t *testing.T) {
+	node := &ReturnNode{
+		Items:   []interface{}{"n"},
+		Limit:   &LimitNode{Expression: 10},
+		Skip:    &SkipNode{Amount: 5},
+		OrderBy: &OrderByNode{Items: []OrderByItem{{Expression: "n.x"}}},
+	}
+	out, params := compileNode(node)
+	if out != "RETURN n ORDER BY n.x SKIP $p1 LIMIT $p2" {
+		t.Fatalf("got %s", out)
+	}
+	if params["p1"] != 5 || params["p2"] != 10 {
+		t.Fatalf("params %v", params)
+	}
+}
+
+func TestWithNodeWithModifiers(t *testing.T) {
+	node := &WithNode{
+		Items:           []interface{}{"n"},
+		OrderBy:         &OrderByNode{Items: []OrderByItem{{Expression: "n.x", Direction: "desc"}}},
+		Limit:           &LimitNode{Expression: "$limit"},
+		WhereConditions: []interface{}{"n.x > 1"},
+	}
+	out, _ := compileNode(node)
+	if out != "WITH n ORDER BY n.x DESC LIMIT $limit\nWHERE n.x > 1" {
+		t.Fatalf("got %q", out)
+	}
+}
+
 func TestProcedureCallNode(t *testing.T) {
 	node := &ProcedureCallNode{Procedure: "db.labels()", YieldItems: []string{"label"}}
 	out, _ := compileNode(node)
@@ -501,6 +708,37 @@ func TestProcedureCallNode(t *testing.T) {
 	}
 }
 
+func TestProcedureCallNodeArguments(t *testing.T) {
+	node := &ProcedureCallNode{
+		Procedure:  "db.index.fulltext.queryNodes",
+		Arguments:  []interface{}{"titles", "matrix"},
+		YieldItems: []string{"node"},
+	}
+	out, params := compileNode(node)
+	if out != "CALL db.index.fulltext.queryNodes($p1, $p2) YIELD node" {
+		t.Fatalf("got %s", out)
+	}
+	if params["p1"] != "titles" || params["p2"] != "matrix" {
+		t.Fatalf("params %v", params)
+	}
+
+	node = &ProcedureCallNode{
+		Procedure:       "db.index.fulltext.queryNodes",
+		Arguments:       []interface{}{&ParameterExpr{Name: "index"}, "matrix"},
+		YieldItems:      []string{"node", "score"},
+		WhereConditions: []interface{}{"score > 1.5"},
+	}
+	out, _ = compileNode(node)
+	if out != "CALL db.index.fulltext.queryNodes($index, $p1) YIELD node, score\nWHERE score > 1.5" {
+		t.Fatalf("got %s", out)
+	}
+
+	out, _ = compileNode(&ProcedureCallNode{Procedure: "db.labels", Arguments: []interface{}{}})
+	if out != "CALL db.labels()" {
+		t.Fatalf("got %s", out)
+	}
+}
+
 func TestCallSubqueryNode(t *testing.T) {
 	ret := &ReturnNode{Items: []interface{}{&LiteralNode{Value: 1}}}
 	node := &CallSubqueryNode{Body: []Node{ret}}
@@ -536,3 +774,423 @@ func TestLoadCSVNode(t *testing.T) {
 		t.Fatalf("got %s", out)
 	}
 }
+
+func TestLoadCSVNodeFieldTerminator(t *testing.T) {
+	node := &LoadCSVNode{WithHeaders: true, From: "'file:///data.csv'", As: "row", FieldTerminator: ";"}
+	out, _ := compileNode(node)
+	if out != "LOAD CSV WITH HEADERS FROM 'file:///data.csv' AS row FIELDTERMINATOR ';'" {
+		t.Fatalf("got %s", out)
+	}
+
+	out, _ = compileNode(&LoadCSVNode{From: "'file:///data.tsv'", As: "row", FieldTerminator: "\t"})
+	if out != `LOAD CSV FROM 'file:///data.tsv' AS row FIELDTERMINATOR '\t'` {
+		t.Fatalf("got %s", out)
+	}
+}
+
+func TestBuildCypherRegistersClauseParameters(t *testing.T) {
+	q := NewQuery()
+	q.AddClause(NewClauseAdapter(&WhereNode{Conditions: []Expression{&ComparisonExpr{
+		LHS: &PropertyAccessExpr{Variable: &LiteralExpr{Value: "n"}, PropertyName: "age"},
+		Op:  ">",
+		RHS: &LiteralExpr{Value: 30},
+	}}}))
+	q.AddClause(NewClauseAdapter(&LimitNode{Expression: 10}))
+
+	out, params := q.BuildCypher()
+	if out != "WHERE $p1.age > $p2\nLIMIT $p3" {
+		t.Fatalf("got %q", out)
+	}
+	if params["p1"] != "n" || params["p2"] != 30 || params["p3"] != 10 {
+		t.Fatalf("params %v", params)
+	}
+}
+
+func TestBuildCypherShortLivedQueries(t *testing.T) {
+	// Each query is collected right after building, so later ones are
+	// likely to reuse its address; none may pick up another query's output.
+	for i := 0; i < 2000; i++ {
+		q := NewQuery().Append(&LimitNode{Expression: i})
+		out, params := q.BuildCypher()
+		if out != "LIMIT $p1" || params["p1"] != i {
+			t.Fatalf("iteration %d: got %q %v", i, out, params)
+		}
+		runtime.GC()
+	}
+}
+
+func TestQueryCloneAppend(t *testing.T) {
+	base := NewQuery()
+	base.Append(&MatchNode{Pattern: "(n:Person)"}, &ReturnNode{Items: []interface{}{"n"}})
+
+	adults := base.Clone().Append(&WhereNode{Conditions: []Expression{&ComparisonExpr{
+		LHS: &PropertyAccessExpr{Variable: &VariableExpr{Name: "n"}, PropertyName: "age"},
+		Op:  ">",
+		RHS: &LiteralExpr{Value: 30},
+	}}})
+	firstTen := base.Clone().Append(&LimitNode{Expression: 10})
+	// Cloned before adults is built: the shared WHERE must still register
+	// its parameter on each query that compiles it.
+	firstFiveAdults := adults.Clone().Append(&LimitNode{Expression: 5})
+
+	out, params := firstFiveAdults.BuildCypher()
+	if out != "MATCH (n:Person)\nWHERE n.age > $p1\nRETURN n\nLIMIT $p2" {
+		t.Errorf("got %q", out)
+	}
+	if !reflect.DeepEqual(params, map[string]interface{}{"p1": 30, "p2": 5}) {
+		t.Errorf("params %v", params)
+	}
+
+	out, params = adults.BuildCypher()
+	if out != "MATCH (n:Person)\nWHERE n.age > $p1\nRETURN n" {
+		t.Errorf("got %q", out)
+	}
+	if !reflect.DeepEqual(params, map[string]interface{}{"p1": 30}) {
+		t.Errorf("params %v", params)
+	}
+
+	out, params = firstTen.BuildCypher()
+	if out != "MATCH (n:Person)\nRETURN n\nLIMIT $p1" {
+		t.Errorf("got %q", out)
+	}
+	if !reflect.DeepEqual(params, map[string]interface{}{"p1": 10}) {
+		t.Errorf("params %v", params)
+	}
+
+	out, params = base.BuildCypher()
+	if out != "MATCH (n:Person)\nRETURN n" || len(params) != 0 {
+		t.Errorf("expected the base query unchanged, got %q %v", out, params)
+	}
+}
+
+func TestQueryCloneIsDeep(t *testing.T) {
+	where := &WhereNode{Conditions: []Expression{&ComparisonExpr{
+		LHS: &PropertyAccessExpr{Variable: &VariableExpr{Name: "n"}, PropertyName: "tags"},
+		Op:  "IN",
+		RHS: &LiteralExpr{Value: "a"},
+	}}}
+	base := NewQuery().Append(&MatchNode{Pattern: "(n)"}, where, &ReturnNode{Items: []interface{}{"n"}})
+	base.RegisterParameter([]interface{}{"x", "y"})
+
+	clone := base.Clone()
+	clone.mu.Lock()
+	cloneWhere := clone.clauses[1].(*ClauseAdapter).Node.(*WhereNode)
+	cloneWhere.Conditions[0].(*ComparisonExpr).RHS.(*LiteralExpr).Value = "b"
+	cloneWhere.Conditions = append(cloneWhere.Conditions, &NullCheckExpr{Operand: &VariableExpr{Name: "n"}})
+	clone.clauses[2].(*ClauseAdapter).Node.(*ReturnNode).Items[0] = "m"
+	clone.parameters["p1"].([]interface{})[0] = "z"
+	clone.mu.Unlock()
+
+	out, params := base.BuildCypher()
+	if out != "MATCH (n)\nWHERE n.tags IN $p2\nRETURN n" {
+		t.Errorf("expected the original clauses unchanged, got %q", out)
+	}
+	if !reflect.DeepEqual(params, map[string]interface{}{"p1": []interface{}{"x", "y"}, "p2": "a"}) {
+		t.Errorf("expected the original parameters unchanged, got %v", params)
+	}
+
+	out, _ = clone.BuildCypher()
+	if out != "MATCH (n)\nWHERE n.tags IN $p2 AND n IS NULL\nRETURN m" {
+		t.Errorf("expected the clone to carry its changes, got %q", out)
+	}
+}
+
+func TestSetParameterDedup(t *testing.T) {
+	build := func(dedup bool) (string, map[string]interface{}) {
+		q := NewQuery()
+		q.SetParameterDedup(dedup)
+		q.Append(
+			&MatchNode{Pattern: "(a), (b)"},
+			&WhereNode{Conditions: []Expression{
+				&ComparisonExpr{LHS: &PropertyAccessExpr{Variable: &VariableExpr{Name: "a"}, PropertyName: "x"}, Op: "=", RHS: &LiteralExpr{Value: 1}},
+				&ComparisonExpr{LHS: &PropertyAccessExpr{Variable: &VariableExpr{Name: "b"}, PropertyName: "x"}, Op: "=", RHS: &LiteralExpr{Value: 1}},
+			}},
+		)
+		return q.BuildCypher()
+	}
+
+	cypher, params := build(true)
+	if want := "MATCH (a), (b)\nWHERE a.x = $p1 AND b.x = $p1"; cypher != want {
+		t.Errorf("expected %q, got %q", want, cypher)
+	}
+	if len(params) != 1 {
+		t.Errorf("expected equal literals to share one parameter, got %v", params)
+	}
+
+	cypher, params = build(false)
+	if want := "MATCH (a), (b)\nWHERE a.x = $p1 AND b.x = $p2"; cypher != want {
+		t.Errorf("expected %q, got %q", want, cypher)
+	}
+	if len(params) != 2 || params["p1"] != 1 || params["p2"] != 1 {
+		t.Errorf("expected two parameters bound to 1, got %v", params)
+	}
+}
+
+func TestNullCheckExpr(t *testing.T) {
+	operand := &PropertyAccessExpr{Variable: &LiteralExpr{Value: "n"}, PropertyName: "deleted"}
+
+	out, params := compileNode(&WhereNode{Conditions: []Expression{&NullCheckExpr{Operand: operand}}})
+	if out != "WHERE $p1.deleted IS NULL" {
+		t.Fatalf("got %s", out)
+	}
+	if params["p1"] != "n" {
+		t.Fatalf("params %v", params)
+	}
+
+	out, _ = compileNode(&WhereNode{Conditions: []Expression{&NullCheckExpr{Operand: operand, Negated: true}}})
+	if out != "WHERE $p1.deleted IS NOT NULL" {
+		t.Fatalf("got %s", out)
+	}
+}
+
+func TestCompilerWithInlineLiterals(t *testing.T) {
+	c := NewCompiler(WithInlineLiterals())
+	out, params := c.Compile(&SetNode{Assignments: []SetAssignment{
+		PropertyAssignment{Property: "n.name", Value: &LiteralExpr{Value: `O"Brien`}},
+		PropertyAssignment{Property: "n.path", Value: &LiteralNode{Value: `C:\tmp`}},
+		PropertyAssignment{Property: "n.age", Value: &LiteralNode{Value: 42}},
+		PropertyAssignment{Property: "n.score", Value: &LiteralNode{Value: 1.0}},
+		PropertyAssignment{Property: "n.active", Value: &LiteralNode{Value: true}},
+		PropertyAssignment{Property: "n.nick", Value: &LiteralNode{Value: nil}},
+	}})
+	want := `SET n.name = "O\"Brien", n.path = "C:\\tmp", n.age = 42, n.score = 1.0, n.active = true, n.nick = null`
+	if out != want {
+		t.Fatalf("got %s", out)
+	}
+	if len(params) != 0 {
+		t.Fatalf("expected no parameters, got %v", params)
+	}
+}
+
+func TestFormatInlineLiteral(t *testing.T) {
+	cases := []struct {
+		in   interface{}
+		want string
+	}{
+		{"line\nbreak", `"line\nbreak"`},
+		{int64(-7), "-7"},
+		{uint8(7), "7"},
+		{2.5, "2.5"},
+		{false, "false"},
+		{[]string{"a", "b"}, `["a", "b"]`},
+		{map[string]interface{}{"b": 1, "a b": "x"}, "{`a b`: \"x\", b: 1}"},
+	}
+	for _, tc := range cases {
+		if got := formatInlineLiteral(tc.in); got != tc.want {
+			t.Errorf("formatInlineLiteral(%#v) = %s, want %s", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestCompilerScopeValidation(t *testing.T) {
+	compileScoped := func(nodes ...Node) []ScopeDiagnostic {
+		c := NewCompiler(WithScopeValidation())
+		c.Compile(nodes...)
+		return c.ScopeDiagnostics()
+	}
+
+	diags := compileScoped(
+		&MatchNode{Pattern: "(n:Person)"},
+		&WithNode{Items: []interface{}{"n.age AS a"}},
+		&ReturnNode{Items: []interface{}{"n"}},
+	)
+	if len(diags) != 1 || diags[0].Variable != "n" || diags[0].Clause != 2 {
+		t.Fatalf("expected n to be flagged on the RETURN, got %v", diags)
+	}
+
+	diags = compileScoped(
+		&MatchNode{Pattern: "(n:Person)"},
+		&WithNode{Items: []interface{}{"n"}},
+		&ReturnNode{Items: []interface{}{"n"}},
+	)
+	if len(diags) != 0 {
+		t.Fatalf("expected no diagnostics, got %v", diags)
+	}
+}
+
+func TestCompilerScopeValidationExpressions(t *testing.T) {
+	c := NewCompiler(WithScopeValidation())
+	c.Compile(
+		&MatchNode{Pattern: "p = (a:Person)-[r:KNOWS]->(b {name: $name})"},
+		&WithNode{
+			Items:           []interface{}{&AliasExpr{Expression: &VariableExpr{Name: "a"}, Alias: "person"}, "count(r) AS friends", "p"},
+			WhereConditions: []interface{}{"friends > 2 AND size([x IN nodes(p) WHERE x.active]) > 0"},
+		},
+		&WhereNode{Conditions: []Expression{&ComparisonExpr{
+			LHS: &PropertyAccessExpr{Variable: &VariableExpr{Name: "person"}, PropertyName: "name"},
+			Op:  "=",
+			// A string value, not the out-of-scope variable a.
+			RHS: &LiteralExpr{Value: "a"},
+		}}},
+		&ReturnNode{
+			Items:   []interface{}{"person.name AS name", "b", &FunctionCallExpr{Name: "size", Arguments: []interface{}{&VariableExpr{Name: "r"}}}},
+			OrderBy: &OrderByNode{Items: []OrderByItem{{Expression: "name"}}},
+		},
+	)
+	diags := c.ScopeDiagnostics()
+	if len(diags) != 2 || diags[0].Variable != "b" || diags[1].Variable != "r" {
+		t.Fatalf("expected only b and r to be flagged, got %v", diags)
+	}
+}
+
+func TestUnwindNodeParameter(t *testing.T) {
+	out, params := compileNode(&UnwindNode{Expression: &ParameterExpr{Name: "rows"}, AliasName: "x"})
+	if out != "UNWIND $rows AS x" {
+		t.Fatalf("got %s", out)
+	}
+	if len(params) != 0 {
+		t.Fatalf("params %v", params)
+	}
+}
+
+func TestUnwindNodeNestedLiteral(t *testing.T) {
+	out, _ := compileNode(&UnwindNode{
+		Expression: []interface{}{1.5, "a", []interface{}{"b"}, 1e6, map[string]interface{}{"k": []interface{}{2.0}, "a b": "x"}},
+		AliasName:  "x",
+	})
+	want := `UNWIND [1.5, "a", ["b"], 1000000.0, {` + "`a b`" + `: "x", k: [2.0]}] AS x`
+	if out != want {
+		t.Fatalf("got %s, want %s", out, want)
+	}
+}
+
+func TestQueryValidate(t *testing.T) {
+	q := NewQuery()
+	q.AddClause(NewClauseAdapter(&MatchNode{Pattern: "(n:Person)"}))
+	q.AddClause(NewClauseAdapter(&ReturnNode{Items: []interface{}{"n.name AS name", "m"}}))
+	errs := q.Validate()
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "variable m is not defined") {
+		t.Fatalf("expected m to be flagged, got %v", errs)
+	}
+
+	q = NewQuery()
+	q.AddClause(NewClauseAdapter(&ReturnNode{Items: []interface{}{"n"}}))
+	q.AddClause(NewClauseAdapter(&MatchNode{Pattern: "(n:Person)"}))
+	q.AddClause(NewClauseAdapter(&ForeachNode{
+		Variable:      "x",
+		Expression:    "[1, 2]",
+		UpdateClauses: []Node{&SetNode{Assignments: []SetAssignment{LabelAssignment{Variable: "n", Label: "Seen"}}}},
+	}))
+	if errs := q.Validate(); len(errs) != 0 {
+		t.Fatalf("expected a valid query, got %v", errs)
+	}
+
+	q = NewQuery()
+	q.AddClause(NewClauseAdapter(&MatchNode{Pattern: "(n)"}))
+	q.AddClause(NewClauseAdapter(&ForeachNode{
+		Variable:      "x",
+		Expression:    "[1, 2]",
+		UpdateClauses: []Node{&ReturnNode{Items: []interface{}{"x"}}},
+	}))
+	errs = q.Validate()
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "FOREACH may only contain update clauses") {
+		t.Fatalf("expected the FOREACH body to be flagged, got %v", errs)
+	}
+}
+
+func TestLoadCSVNodeCompressionAdvisory(t *testing.T) {
+	gz := &LoadCSVNode{From: "'https://example.com/people.csv.gz'", As: "row"}
+	if msg := gz.CompressionAdvisory(); !strings.Contains(msg, "https://example.com/people.csv.gz is gzip-compressed") {
+		t.Errorf("expected a gzip advisory, got %q", msg)
+	}
+	out, _ := compileNode(gz)
+	if out != "LOAD CSV FROM 'https://example.com/people.csv.gz' AS row" {
+		t.Errorf("advisory must not change the compiled clause, got %s", out)
+	}
+
+	plain := &LoadCSVNode{From: "'https://example.com/people.csv'", As: "row"}
+	if msg := plain.CompressionAdvisory(); msg != "" {
+		t.Errorf("expected no advisory for a plain csv, got %q", msg)
+	}
+
+	local := &LoadCSVNode{From: &LiteralExpr{Value: "file:///people.csv.gz"}, As: "row"}
+	if msg := local.CompressionAdvisory(); msg != "" {
+		t.Errorf("expected no advisory for a local archive, got %q", msg)
+	}
+
+	declared := &LoadCSVNode{
+		From:    &ParameterExpr{Name: "url"},
+		As:      "row",
+		Options: LoadCSVOptions{Compression: CompressionZip},
+	}
+	if msg := declared.CompressionAdvisory(); !strings.Contains(msg, "zip-compressed") {
+		t.Errorf("expected a zip advisory from the declared compression, got %q", msg)
+	}
+}
+
+func TestCompilerAdapter(t *testing.T) {
+	nodes := []Node{
+		&LoadCSVNode{WithHeaders: true, From: "'file:///people.csv'", As: "row", FieldTerminator: ";"},
+		&ProcedureCallNode{Procedure: "dbms.procedures", Arguments: []interface{}{}, YieldItems: []string{"name"}},
+		&WhereNode{Conditions: []Expression{&ComparisonExpr{LHS: &VariableExpr{Name: "name"}, Op: "=", RHS: &ParameterExpr{Name: "name"}}}},
+	}
+
+	tests := []struct {
+		adapter string
+		want    string
+	}{
+		{
+			adapter: "neo4j",
+			want: "LOAD CSV WITH HEADERS FROM 'file:///people.csv' AS row FIELDTERMINATOR ';'\n" +
+				"CALL dbms.procedures() YIELD name\n" +
+				"WHERE name = $name",
+		},
+		{
+			adapter: "memgraph",
+			want: "LOAD CSV FROM 'file:///people.csv' WITH HEADER DELIMITER ';' AS row\n" +
+				"CALL mg.procedures() YIELD name\n" +
+				"WHERE name = $name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.adapter, func(t *testing.T) {
+			out, _ := NewCompiler(WithAdapter(tt.adapter)).Compile(nodes...)
+			if out != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, out)
+			}
+
+			q := NewQuery()
+			q.PreserveClauseOrder()
+			q.SetAdapter(tt.adapter)
+			q.Append(nodes...)
+			if out, _ := q.BuildCypher(); out != tt.want {
+				t.Errorf("expected query output %q, got %q", tt.want, out)
+			}
+		})
+	}
+
+	out, _ := NewCompiler(WithAdapter("memgraph")).Compile(&LoadCSVNode{From: "'file:///people.csv'", As: "row"})
+	if want := "LOAD CSV FROM 'file:///people.csv' NO HEADER AS row"; out != want {
+		t.Errorf("expected %q, got %q", want, out)
+	}
+}
+
+func TestCountExpr(t *testing.T) {
+	q := NewQuery()
+	q.Append(
+		&MatchNode{Pattern: "(n)"},
+		&WhereNode{Conditions: []Expression{&ComparisonExpr{
+			LHS: &PropertyAccessExpr{Variable: &VariableExpr{Name: "n"}, PropertyName: "age"}, Op: ">", RHS: &LiteralExpr{Value: 30},
+		}}},
+		&ReturnNode{Items: []interface{}{
+			&AliasExpr{Expression: &CountExpr{Body: []Node{&MatchNode{Pattern: "(n)-[:R]->()"}}}, Alias: "c"},
+			&AliasExpr{Expression: &CountExpr{Body: []Node{
+				&MatchNode{Pattern: "(n)-[:R]->(m)"},
+				&WhereNode{Conditions: []Expression{&ComparisonExpr{
+					LHS: &PropertyAccessExpr{Variable: &VariableExpr{Name: "m"}, PropertyName: "score"}, Op: ">", RHS: &LiteralExpr{Value: 5},
+				}}},
+			}}, Alias: "high"},
+		}},
+	)
+
+	cypher, params := q.BuildCypher()
+	want := "MATCH (n)\nWHERE n.age > $p1\n" +
+		"RETURN COUNT { MATCH (n)-[:R]->() } AS c, COUNT { MATCH (n)-[:R]->(m) WHERE m.score > $p2 } AS high"
+	if cypher != want {
+		t.Errorf("expected %q, got %q", want, cypher)
+	}
+	if len(params) != 2 || params["p1"] != 30 || params["p2"] != 5 {
+		t.Errorf("expected the subquery to continue the parameter numbering, got %v", params)
+	}
+}
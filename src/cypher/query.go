@@ -2,6 +2,7 @@ package cypher
 
 import (
 	"fmt"
+	"reflect"
 	"sort"
 	"strings"
 	"sync"
@@ -14,6 +15,25 @@ type Query struct {
 	parameters   map[string]interface{}
 	paramCounter int
 	clauses      []Clause
+	// inlineLiterals renders values as Cypher literals instead of parameters.
+	inlineLiterals bool
+	// spans holds where recorded literal values were written; see RecordSpan.
+	spans map[interface{}][]SourceSpan
+	// spansTaken counts the spans of each value assigned to a parameter, and
+	// paramSpans holds them by parameter key; see assignSpan.
+	spansTaken map[interface{}]int
+	paramSpans map[string][]SourceSpan
+	// addOrder keeps clauses in the order they were added; see
+	// PreserveClauseOrder.
+	addOrder bool
+	// noDedup gives every registered value its own key; see
+	// SetParameterDedup.
+	noDedup bool
+	// adapter is the target database; see SetAdapter.
+	adapter string
+	// compiled holds each clause's output once built. It names parameters
+	// registered on this query, so it lives and dies with the query.
+	compiled map[*ClauseAdapter]string
 }
 
 // NewQuery creates a new empty Query instance.
@@ -21,22 +41,37 @@ func NewQuery() *Query {
 	return &Query{parameters: make(map[string]interface{})}
 }
 
-// RegisterParameter stores a value and returns its parameter key.
+// RegisterParameter stores a value and returns its parameter key. Equal
+// values share a key unless dedup is disabled with SetParameterDedup; values
+// that can't be compared (slices, maps) always get a new one.
 func (q *Query) RegisterParameter(value interface{}) string {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
-	for k, v := range q.parameters {
-		if v == value {
-			return k
+	if !q.noDedup && (value == nil || reflect.TypeOf(value).Comparable()) {
+		for k, v := range q.parameters {
+			if v == value {
+				q.assignSpan(k, value)
+				return k
+			}
 		}
 	}
 	q.paramCounter++
 	key := fmt.Sprintf("p%d", q.paramCounter)
 	q.parameters[key] = value
+	q.assignSpan(key, value)
 	return key
 }
 
+// placeholder returns the Cypher text standing in for value: a $pN parameter
+// reference, or the escaped literal when the query inlines literals.
+func (q *Query) placeholder(value interface{}) string {
+	if q.inlineLiterals {
+		return formatInlineLiteral(value)
+	}
+	return "$" + q.RegisterParameter(value)
+}
+
 // AddClause appends a clause to the query.
 func (q *Query) AddClause(c Clause) {
 	q.mu.Lock()
@@ -44,21 +79,237 @@ func (q *Query) AddClause(c Clause) {
 	q.clauses = append(q.clauses, c)
 }
 
-// BuildCypher assembles the full query string from its clauses.
-func (q *Query) BuildCypher() (string, map[string]interface{}) {
+// Append adds a clause for each node and returns q, so a variant can be
+// built in one expression: base.Clone().Append(&LimitNode{Expression: 10}).
+func (q *Query) Append(nodes ...Node) *Query {
+	for _, n := range nodes {
+		q.AddClause(NewClauseAdapter(n))
+	}
+	return q
+}
+
+// Clone returns an independent copy of q: the nodes inside its clauses and
+// the parameter values are copied deeply, so appending clauses to the copy,
+// or modifying its nodes or parameters, leaves q unchanged.
+func (q *Query) Clone() *Query {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	clone := &Query{
+		parameters:     make(map[string]interface{}, len(q.parameters)),
+		paramCounter:   q.paramCounter,
+		clauses:        make([]Clause, len(q.clauses)),
+		inlineLiterals: q.inlineLiterals,
+		addOrder:       q.addOrder,
+		noDedup:        q.noDedup,
+		adapter:        q.adapter,
+	}
+	seen := make(map[copiedPointer]reflect.Value)
+	for k, v := range q.parameters {
+		clone.parameters[k] = deepCopy(v, seen)
+	}
+	for i, c := range q.clauses {
+		if adapter, ok := c.(*ClauseAdapter); ok {
+			copied := ClauseAdapter{Node: deepCopy(adapter.Node, seen).(Node)}
+			if out, ok := q.compiled[adapter]; ok {
+				if clone.compiled == nil {
+					clone.compiled = make(map[*ClauseAdapter]string)
+				}
+				clone.compiled[&copied] = out
+			}
+			c = &copied
+		}
+		clone.clauses[i] = c
+	}
+	if q.spans != nil {
+		clone.spans = make(map[interface{}][]SourceSpan, len(q.spans))
+		for k, v := range q.spans {
+			clone.spans[k] = append([]SourceSpan(nil), v...)
+		}
+	}
+	if q.paramSpans != nil {
+		clone.spansTaken = make(map[interface{}]int, len(q.spansTaken))
+		for k, v := range q.spansTaken {
+			clone.spansTaken[k] = v
+		}
+		clone.paramSpans = make(map[string][]SourceSpan, len(q.paramSpans))
+		for k, v := range q.paramSpans {
+			clone.paramSpans[k] = append([]SourceSpan(nil), v...)
+		}
+	}
+	return clone
+}
+
+// copiedPointer identifies a pointer deepCopy has already copied. The type is
+// part of it because a struct and its first field share an address.
+type copiedPointer struct {
+	typ  reflect.Type
+	addr uintptr
+}
+
+// deepCopy returns a copy of v that shares no pointers, slices or maps with
+// it. seen maps pointers already copied to their copy, so shared and cyclic
+// references keep their shape. Unexported struct fields are copied shallowly.
+func deepCopy(v interface{}, seen map[copiedPointer]reflect.Value) interface{} {
+	if v == nil {
+		return nil
+	}
+	return deepCopyValue(reflect.ValueOf(v), seen).Interface()
+}
+
+func deepCopyValue(v reflect.Value, seen map[copiedPointer]reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		key := copiedPointer{v.Type(), v.Pointer()}
+		if copied, ok := seen[key]; ok {
+			return copied
+		}
+		copied := reflect.New(v.Type().Elem())
+		seen[key] = copied
+		copied.Elem().Set(deepCopyValue(v.Elem(), seen))
+		return copied
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(deepCopyValue(v.Elem(), seen))
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if copied.Field(i).CanSet() {
+				copied.Field(i).Set(deepCopyValue(v.Field(i), seen))
+			}
+		}
+		return copied
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(deepCopyValue(v.Index(i), seen))
+		}
+		return copied
+	case reflect.Array:
+		copied := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(deepCopyValue(v.Index(i), seen))
+		}
+		return copied
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), deepCopyValue(iter.Value(), seen))
+		}
+		return copied
+	default:
+		return v
+	}
+}
+
+// PreserveClauseOrder makes BuildCypher, Format and Validate take the
+// clauses in the order they were added instead of sorting them by
+// ClauseOrder. Queries that repeat clauses, such as MATCH ... WITH ...
+// MATCH, only keep their meaning this way.
+func (q *Query) PreserveClauseOrder() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.addOrder = true
+}
+
+// SetParameterDedup controls whether RegisterParameter reuses the key of an
+// equal value already registered. Dedup is on by default; turning it off
+// gives every value its own parameter and skips the scan over the existing
+// parameters, which grows linearly with their number.
+func (q *Query) SetParameterDedup(enabled bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.noDedup = !enabled
+}
+
+// SetAdapter makes BuildCypher compile for the named database adapter, as
+// given by the connection URL's Adapter. See WithAdapter for what differs.
+func (q *Query) SetAdapter(adapter string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.adapter = adapter
+	q.compiled = nil
+}
+
+// Adapter returns the adapter set with SetAdapter, or "" for the default.
+func (q *Query) Adapter() string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.adapter
+}
+
+// compiledClause returns the output stored for c by storeCompiledClause.
+func (q *Query) compiledClause(c *ClauseAdapter) (string, bool) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	out, ok := q.compiled[c]
+	return out, ok
+}
+
+// storeCompiledClause records c's output so building q again reuses it.
+func (q *Query) storeCompiledClause(c *ClauseAdapter, out string) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
+	if q.compiled == nil {
+		q.compiled = make(map[*ClauseAdapter]string)
+	}
+	q.compiled[c] = out
+}
+
+// orderedClauses returns a copy of the clauses in the order they are emitted.
+func (q *Query) orderedClauses() []Clause {
+	q.mu.RLock()
+	clauses := make([]Clause, len(q.clauses))
+	copy(clauses, q.clauses)
+	addOrder := q.addOrder
+	q.mu.RUnlock()
 
-	sort.SliceStable(q.clauses, func(i, j int) bool {
-		return ClauseOrder(q.clauses[i]) < ClauseOrder(q.clauses[j])
-	})
+	if !addOrder {
+		sort.SliceStable(clauses, func(i, j int) bool {
+			return ClauseOrder(clauses[i]) < ClauseOrder(clauses[j])
+		})
+	}
+	return clauses
+}
 
+// BuildCypher assembles the full query string from its clauses.
+func (q *Query) BuildCypher() (string, map[string]interface{}) {
+	clauses := q.orderedClauses()
+
+	// Clauses register their parameters on q while building, so the lock
+	// must not be held here.
 	var b strings.Builder
-	for i, c := range q.clauses {
+	for i, c := range clauses {
 		if i > 0 {
 			b.WriteByte('\n') // Use newline for better readability between clauses
 		}
 		b.WriteString(c.BuildCypher(q))
 	}
-	return b.String(), q.parameters
+	return b.String(), q.Parameters()
+}
+
+// Parameters returns a copy of the parameters registered so far.
+func (q *Query) Parameters() map[string]interface{} {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	params := make(map[string]interface{}, len(q.parameters))
+	for k, v := range q.parameters {
+		params[k] = v
+	}
+	return params
 }
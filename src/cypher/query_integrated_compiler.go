@@ -9,10 +9,10 @@ type QueryIntegratedCompiler struct {
 
 // NewQueryIntegratedCompiler creates a compiler bound to a Query.
 func NewQueryIntegratedCompiler(q *Query) *QueryIntegratedCompiler {
-	return &QueryIntegratedCompiler{Compiler: NewCompiler(), query: q}
-}
-
-// override registerParameter to use the query
-func (c *QueryIntegratedCompiler) registerParameter(val interface{}) string {
-	return c.query.RegisterParameter(val)
+	c := &QueryIntegratedCompiler{Compiler: NewCompiler(WithAdapter(q.Adapter())), query: q}
+	// Method overriding does not reach the embedded Compiler's visitors, so
+	// point the embedded Compiler at the query directly.
+	c.Compiler.query = q
+	c.Compiler.inlineLiterals = q.inlineLiterals
+	return c
 }
@@ -1,9 +1,15 @@
 package cypher
 
-// ReturnNode represents a RETURN clause.
+// ReturnNode represents a RETURN clause. All renders RETURN *, followed by
+// any Items. OrderBy, Skip and Limit are optional modifiers rendered right
+// after the items, in that order.
 type ReturnNode struct {
 	Items    []interface{}
+	All      bool
 	Distinct bool
+	OrderBy  *OrderByNode
+	Skip     *SkipNode
+	Limit    *LimitNode
 }
 
 func (n *ReturnNode) Accept(v Visitor) error {
@@ -0,0 +1,412 @@
+package cypher
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ScopeDiagnostic reports a variable referenced where it is not in scope,
+// typically because a preceding WITH did not carry it forward.
+type ScopeDiagnostic struct {
+	// Clause is the position of the offending node among the compiled nodes.
+	Clause   int
+	Variable string
+	Message  string
+}
+
+func (d ScopeDiagnostic) String() string {
+	return fmt.Sprintf("clause %d: %s", d.Clause+1, d.Message)
+}
+
+// WithScopeValidation makes Compile track the variables each clause
+// introduces and references, recording a ScopeDiagnostic for every reference
+// to a variable that the last WITH did not project. Clauses before the first
+// WITH are not checked, since fragments may use variables bound elsewhere.
+func WithScopeValidation() CompilerOption {
+	return func(c *Compiler) { c.scope = newScopeTracker() }
+}
+
+// ScopeDiagnostics returns the problems found while compiling with
+// WithScopeValidation, in clause order.
+func (c *Compiler) ScopeDiagnostics() []ScopeDiagnostic {
+	if c.scope == nil {
+		return nil
+	}
+	return c.scope.diagnostics
+}
+
+// scopeTracker is a best-effort analysis over the loosely typed AST: raw
+// strings are scanned for identifiers, and anything it cannot see into (a
+// CALL subquery, a non-string pattern) opens the scope so nothing is
+// reported until the next WITH closes it again.
+type scopeTracker struct {
+	vars        map[string]bool
+	closed      bool // a WITH has fixed the set of visible variables
+	projected   bool // closed by a WITH rather than by starting out closed
+	clause      int
+	diagnostics []ScopeDiagnostic
+}
+
+func newScopeTracker() *scopeTracker {
+	return &scopeTracker{vars: make(map[string]bool)}
+}
+
+func (s *scopeTracker) visit(n Node) {
+	switch v := n.(type) {
+	case *MatchNode:
+		s.introducePattern(v.Pattern)
+	case *CreateNode:
+		s.introducePattern(v.Pattern)
+	case *MergeNode:
+		s.introducePattern(v.Pattern)
+		if v.OnCreate != nil {
+			s.visit(v.OnCreate)
+		}
+		if v.OnMatch != nil {
+			s.visit(v.OnMatch)
+		}
+	case *UnwindNode:
+		s.check(referencedVars(v.Expression)...)
+		s.introduce(v.AliasName)
+	case *LoadCSVNode:
+		s.check(referencedVars(v.From)...)
+		s.introduce(v.As)
+	case *ProcedureCallNode:
+		for _, arg := range v.Arguments {
+			if _, ok := arg.(Expression); ok {
+				s.check(referencedVars(arg)...)
+			}
+		}
+		for _, item := range v.YieldItems {
+			if name, ok := projectedName(item); ok {
+				s.introduce(name)
+			}
+		}
+		for _, cond := range v.WhereConditions {
+			s.check(referencedVars(cond)...)
+		}
+	case *CallSubqueryNode:
+		s.closed = false
+	case *ForeachNode:
+		s.check(referencedVars(v.Expression)...)
+	case *WhereNode:
+		for _, cond := range v.Conditions {
+			s.check(referencedVars(cond)...)
+		}
+	case *SetNode:
+		for _, a := range v.Assignments {
+			switch asn := a.(type) {
+			case PropertyAssignment:
+				s.check(referencedVars(asn.Property)...)
+				s.check(referencedVars(asn.Value)...)
+			case VariablePropertiesAssignment:
+				s.check(asn.Variable)
+				s.check(referencedVars(asn.Value)...)
+			case LabelAssignment:
+				s.check(asn.Variable)
+			}
+		}
+	case *RemoveNode:
+		for _, item := range v.Items {
+			switch r := item.(type) {
+			case PropertyRemoval:
+				s.check(referencedVars(r.Property)...)
+			case LabelRemoval:
+				s.check(r.Variable)
+			case string:
+				s.check(referencedVars(r)...)
+			}
+		}
+	case *DeleteNode:
+		for _, expr := range v.Expressions {
+			s.check(referencedVars(expr)...)
+		}
+	case *OrderByNode:
+		s.checkOrderBy(v)
+	case *ReturnNode:
+		for _, item := range v.Items {
+			s.check(referencedVars(item)...)
+		}
+		// ORDER BY and a trailing ORDER BY clause also see RETURN aliases.
+		for _, item := range v.Items {
+			if name, ok := projectedName(item); ok {
+				s.introduce(name)
+			}
+		}
+		s.checkOrderBy(v.OrderBy)
+	case *WithNode:
+		s.visitWith(v)
+	}
+	s.clause++
+}
+
+func (s *scopeTracker) visitWith(n *WithNode) {
+	projected := make(map[string]bool)
+	star := n.All
+	for _, item := range n.Items {
+		s.check(referencedVars(item)...)
+		if str, ok := item.(string); ok && strings.TrimSpace(str) == "*" {
+			star = true
+			continue
+		}
+		if name, ok := projectedName(item); ok {
+			projected[name] = true
+		}
+	}
+
+	// ORDER BY on a WITH sees both the incoming and the projected variables.
+	for name := range projected {
+		s.introduce(name)
+	}
+	s.checkOrderBy(n.OrderBy)
+
+	if !star {
+		s.vars = projected
+		s.closed = true
+		s.projected = true
+	}
+	for _, cond := range n.WhereConditions {
+		s.check(referencedVars(cond)...)
+	}
+}
+
+func (s *scopeTracker) checkOrderBy(n *OrderByNode) {
+	if n == nil {
+		return
+	}
+	for _, item := range n.Items {
+		s.check(referencedVars(item.Expression)...)
+	}
+}
+
+func (s *scopeTracker) introduce(name string) {
+	if name != "" {
+		s.vars[name] = true
+	}
+}
+
+func (s *scopeTracker) introducePattern(pattern interface{}) {
+	str, ok := pattern.(string)
+	if !ok {
+		s.closed = false
+		return
+	}
+	for _, name := range patternVars(str) {
+		s.introduce(name)
+	}
+}
+
+func (s *scopeTracker) check(names ...string) {
+	if !s.closed {
+		return
+	}
+	for _, name := range names {
+		if name == "" || s.vars[name] {
+			continue
+		}
+		msg := fmt.Sprintf("variable %s is not in scope; it is not projected by the preceding WITH", name)
+		if !s.projected {
+			msg = fmt.Sprintf("variable %s is not defined by any preceding clause", name)
+		}
+		s.diagnostics = append(s.diagnostics, ScopeDiagnostic{
+			Clause:   s.clause,
+			Variable: name,
+			Message:  msg,
+		})
+	}
+}
+
+// referencedVars returns the variables an item reads. Only VariableExpr (and
+// raw Cypher strings) name variables; a LiteralExpr is a value that compiles
+// to a parameter, whatever it holds.
+func referencedVars(item interface{}) []string {
+	switch v := item.(type) {
+	case string:
+		return rawReferences(v)
+	case *PropertyAccessExpr:
+		return referencedVars(v.Variable)
+	case *VariableExpr:
+		return []string{v.Name}
+	case *AliasExpr:
+		return referencedVars(v.Expression)
+	case *FunctionCallExpr:
+		var names []string
+		for _, arg := range v.Arguments {
+			names = append(names, referencedVars(arg)...)
+		}
+		return names
+	case *ComparisonExpr:
+		return append(referencedVars(v.LHS), referencedVars(v.RHS)...)
+	case *NullCheckExpr:
+		return referencedVars(v.Operand)
+	}
+	return nil
+}
+
+// projectedName returns the variable name a projection item binds, if any:
+// its alias, or the bare variable it passes through.
+func projectedName(item interface{}) (string, bool) {
+	switch v := item.(type) {
+	case string:
+		tokens := scanIdentifiers(v)
+		for i := len(tokens) - 1; i > 0; i-- {
+			if tokens[i-1].keyword == "AS" {
+				return tokens[i].name, true
+			}
+		}
+		if trimmed := strings.TrimSpace(v); isIdentifier(trimmed) {
+			return trimmed, true
+		}
+	case *AliasExpr:
+		return v.Alias, v.Alias != ""
+	case *VariableExpr:
+		return v.Name, v.Name != ""
+	}
+	return "", false
+}
+
+// patternVars returns the node, relationship and path variables bound by a
+// pattern string such as "p = (a:Person)-[r:KNOWS]->(b)".
+func patternVars(pattern string) []string {
+	var names []string
+	for i, tok := range scanIdentifiers(pattern) {
+		if tok.keyword != "" {
+			continue
+		}
+		bindsElement := tok.prev == '(' || tok.prev == '['
+		bindsPath := i == 0 && tok.next == '='
+		if bindsElement || bindsPath {
+			names = append(names, tok.name)
+		}
+	}
+	return names
+}
+
+// rawReferences returns the variables read by verbatim Cypher text. Property
+// keys, labels, parameters, function names, map keys, aliases and variables
+// bound locally by "x IN list" are skipped.
+func rawReferences(s string) []string {
+	tokens := scanIdentifiers(s)
+	local := make(map[string]bool)
+	for i := 0; i+1 < len(tokens); i++ {
+		if tokens[i].keyword == "" && tokens[i+1].keyword == "IN" {
+			local[tokens[i].name] = true
+		}
+	}
+
+	var names []string
+	for i, tok := range tokens {
+		switch {
+		case tok.keyword != "", local[tok.name]:
+		case tok.prev == '.' || tok.prev == ':' || tok.prev == '$':
+		case tok.next == '(':
+		case tok.next == ':' && tok.inMap:
+		case i > 0 && tokens[i-1].keyword == "AS":
+		default:
+			names = append(names, tok.name)
+		}
+	}
+	return names
+}
+
+type identToken struct {
+	name    string
+	keyword string // upper-cased keyword, empty for other identifiers
+	prev    rune   // nearest non-space rune before the token
+	next    rune   // nearest non-space rune after the token
+	inMap   bool   // inside a {...} map literal
+}
+
+// scanIdentifiers tokenizes Cypher text into identifiers, skipping string
+// literals. Backtick-quoted names are returned without their backticks.
+func scanIdentifiers(s string) []identToken {
+	runes := []rune(s)
+	var tokens []identToken
+	depth := 0
+
+	prevRune := func(i int) rune {
+		for i--; i >= 0; i-- {
+			if !unicode.IsSpace(runes[i]) {
+				return runes[i]
+			}
+		}
+		return 0
+	}
+	nextRune := func(i int) rune {
+		for ; i < len(runes); i++ {
+			if !unicode.IsSpace(runes[i]) {
+				return runes[i]
+			}
+		}
+		return 0
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '\'' || r == '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != r {
+				if runes[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			i = j + 1
+		case r == '`':
+			j := i + 1
+			for j < len(runes) && runes[j] != '`' {
+				j++
+			}
+			end := j
+			if end > len(runes) {
+				end = len(runes)
+			}
+			tokens = append(tokens, identToken{
+				name:  string(runes[i+1 : end]),
+				prev:  prevRune(i),
+				next:  nextRune(j + 1),
+				inMap: depth > 0,
+			})
+			i = j + 1
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			word := string(runes[i:j])
+			tok := identToken{name: word, prev: prevRune(i), next: nextRune(j), inMap: depth > 0}
+			if upper := strings.ToUpper(word); cypherKeywords[upper] {
+				tok.keyword = upper
+			}
+			tokens = append(tokens, tok)
+			i = j
+		case unicode.IsDigit(r):
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || unicode.IsLetter(runes[i])) {
+				i++
+			}
+		default:
+			if r == '{' {
+				depth++
+			} else if r == '}' && depth > 0 {
+				depth--
+			}
+			i++
+		}
+	}
+	return tokens
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if r == '_' || unicode.IsLetter(r) || i > 0 && unicode.IsDigit(r) {
+			continue
+		}
+		return false
+	}
+	return !cypherKeywords[strings.ToUpper(s)]
+}
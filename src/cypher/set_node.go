@@ -8,6 +8,10 @@ type PropertyAssignment struct {
 	Value    interface{}
 }
 
+// VariablePropertiesAssignment sets all properties of Variable at once:
+// `n = value` replaces them, `n += value` (Merge) adds to them. A string
+// Value is rendered verbatim, so it can name a variable (`SET n = row`) or
+// spell out a map; any other value is passed as a parameter.
 type VariablePropertiesAssignment struct {
 	Variable string
 	Value    interface{}
@@ -2,9 +2,19 @@ package cypher
 
 // SkipNode represents a SKIP clause.
 type SkipNode struct {
+	// Amount is the number of rows to skip. A string is written as raw
+	// Cypher (e.g. "$offset"), so it must not carry untrusted input; any
+	// other value is rendered as a literal: a parameter, or inline under
+	// WithInlineLiterals. Prefer Skip for an amount known in Go.
 	Amount interface{}
 }
 
+// Skip returns a SKIP clause whose amount is rendered as a literal, never as
+// raw Cypher: a parameter by default, or inline under WithInlineLiterals.
+func Skip(n int) *SkipNode {
+	return &SkipNode{Amount: n}
+}
+
 func (n *SkipNode) Accept(v Visitor) error {
 	if vv, ok := v.(interface{ VisitSkipNode(*SkipNode) error }); ok {
 		return vv.VisitSkipNode(n)
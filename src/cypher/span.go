@@ -0,0 +1,64 @@
+package cypher
+
+import "reflect"
+
+// SourceSpan locates a piece of the text a query was parsed from. Offsets
+// are in bytes with EndOffset exclusive; lines and columns are 1-based and
+// EndColumn points just past the last character.
+type SourceSpan struct {
+	Offset, EndOffset  int
+	Line, Column       int
+	EndLine, EndColumn int
+}
+
+// RecordSpan notes that value was written at span in the source text. A
+// parser calls it for each literal it converts, in source order, so
+// ParameterSpans can map the parameters those literals become back to where
+// they came from. Values that can't be parameter keys (maps, slices) are
+// ignored.
+func (q *Query) RecordSpan(value interface{}, span SourceSpan) {
+	if value == nil || !reflect.TypeOf(value).Comparable() {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.spans == nil {
+		q.spans = make(map[interface{}][]SourceSpan)
+	}
+	q.spans[value] = append(q.spans[value], span)
+}
+
+// assignSpan gives key, just registered for value, the next recorded span of
+// value not yet taken. Literals are compiled in the order they were written,
+// so each registration takes the span of the literal it came from. q.mu must
+// be held.
+func (q *Query) assignSpan(key string, value interface{}) {
+	if value == nil || !reflect.TypeOf(value).Comparable() {
+		return
+	}
+	recorded := q.spans[value]
+	next := q.spansTaken[value]
+	if next >= len(recorded) {
+		return
+	}
+	if q.spansTaken == nil {
+		q.spansTaken = make(map[interface{}]int)
+		q.paramSpans = make(map[string][]SourceSpan)
+	}
+	q.spansTaken[value] = next + 1
+	q.paramSpans[key] = append(q.paramSpans[key], recorded[next])
+}
+
+// ParameterSpans returns the source spans of each registered parameter that
+// came from a recorded literal. A parameter has a span for every literal it
+// stands for: one, or several when dedup shares it between equal literals.
+// Parameters are registered while building, so call this after BuildCypher.
+func (q *Query) ParameterSpans() map[string][]SourceSpan {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	out := make(map[string][]SourceSpan, len(q.paramSpans))
+	for key, spans := range q.paramSpans {
+		out[key] = append([]SourceSpan(nil), spans...)
+	}
+	return out
+}
@@ -0,0 +1,68 @@
+package cypher
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ValidationError is a structural problem found by Query.Validate.
+type ValidationError struct {
+	// Clause is the position of the offending clause in build order.
+	Clause  int
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("clause %d: %s", e.Clause+1, e.Message)
+}
+
+// Validate checks the query's clauses for structural problems before it is
+// sent: variables referenced but never introduced (or dropped by a WITH) and
+// FOREACH bodies containing clauses other than updates. Clauses are checked
+// in the order BuildCypher emits them. Only ClauseAdapter clauses can be
+// inspected; the checks are best effort and a nil result does not guarantee
+// the server will accept the query.
+func (q *Query) Validate() []error {
+	clauses := q.orderedClauses()
+
+	// The query is complete, so every reference must resolve from the start.
+	scope := newScopeTracker()
+	scope.closed = true
+
+	var errs []error
+	for i, c := range clauses {
+		adapter, ok := c.(*ClauseAdapter)
+		if !ok || adapter.Node == nil {
+			scope.clause++
+			continue
+		}
+		if foreach, ok := adapter.Node.(*ForeachNode); ok {
+			for _, n := range foreach.UpdateClauses {
+				if !isUpdateNode(n) {
+					errs = append(errs, &ValidationError{
+						Clause:  i,
+						Message: fmt.Sprintf("FOREACH may only contain update clauses, got %T", n),
+					})
+				}
+			}
+		}
+		scope.visit(adapter.Node)
+	}
+	for _, d := range scope.diagnostics {
+		errs = append(errs, &ValidationError{Clause: d.Clause, Message: d.Message})
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].(*ValidationError).Clause < errs[j].(*ValidationError).Clause
+	})
+	return errs
+}
+
+// isUpdateNode reports whether n is a clause allowed inside FOREACH.
+func isUpdateNode(n Node) bool {
+	switch n.(type) {
+	case *SetNode, *RemoveNode, *DeleteNode, *CreateNode, *MergeNode, *ForeachNode:
+		return true
+	}
+	return false
+}
@@ -1,9 +1,15 @@
 package cypher
 
-// WithNode represents a WITH clause.
+// WithNode represents a WITH clause. All renders WITH *, carrying every
+// variable in scope, followed by any Items. OrderBy, Skip and Limit are
+// optional modifiers rendered after the items and before any WHERE.
 type WithNode struct {
 	Items           []interface{}
+	All             bool
 	Distinct        bool
+	OrderBy         *OrderByNode
+	Skip            *SkipNode
+	Limit           *LimitNode
 	WhereConditions []interface{}
 }
 
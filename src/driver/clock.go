@@ -0,0 +1,167 @@
+package driver
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the source of time for the time-based reactive operators
+// (BatchByTime, WindowByTime, Throttle). Set ReactiveConfig.Clock to a
+// FakeClock to drive them deterministically in tests.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is the subset of *time.Timer used by the reactive operators.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Ticker is the subset of *time.Ticker used by the reactive operators.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// RealClock is a Clock backed by the time package.
+type RealClock struct{}
+
+func (RealClock) Now() time.Time { return time.Now() }
+
+func (RealClock) NewTimer(d time.Duration) Timer { return realTimer{time.NewTimer(d)} }
+
+func (RealClock) NewTicker(d time.Duration) Ticker { return realTicker{time.NewTicker(d)} }
+
+type realTimer struct{ *time.Timer }
+
+func (t realTimer) C() <-chan time.Time { return t.Timer.C }
+
+type realTicker struct{ *time.Ticker }
+
+func (t realTicker) C() <-chan time.Time { return t.Ticker.C }
+
+// FakeClock is a Clock whose time only moves when Advance is called. Timers
+// and tickers fire during Advance once their deadline is reached; like the
+// real ones, their channels hold a single pending tick and drop the rest.
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+// NewFakeClock returns a FakeClock set to start.
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	return c.schedule(d, 0)
+}
+
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("driver: non-positive interval for FakeClock.NewTicker")
+	}
+	return fakeTicker{c.schedule(d, d)}
+}
+
+// Advance moves the clock forward by d, firing every timer and ticker that
+// falls due on the way.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	end := c.now.Add(d)
+	for {
+		next := c.nextDue(end)
+		if next == nil {
+			break
+		}
+		c.now = next.deadline
+		next.fire()
+	}
+	c.now = end
+}
+
+// nextDue returns the active waiter with the earliest deadline not after
+// end, or nil.
+func (c *FakeClock) nextDue(end time.Time) *fakeWaiter {
+	var next *fakeWaiter
+	for _, w := range c.waiters {
+		if w.active && !w.deadline.After(end) && (next == nil || w.deadline.Before(next.deadline)) {
+			next = w
+		}
+	}
+	return next
+}
+
+func (c *FakeClock) schedule(d, period time.Duration) *fakeWaiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w := &fakeWaiter{
+		clock:    c,
+		c:        make(chan time.Time, 1),
+		deadline: c.now.Add(d),
+		period:   period,
+		active:   true,
+	}
+	c.waiters = append(c.waiters, w)
+	return w
+}
+
+// fakeWaiter is a FakeClock timer, and with a period the core of a ticker.
+// Its fields are guarded by the clock's mutex.
+type fakeWaiter struct {
+	clock    *FakeClock
+	c        chan time.Time
+	deadline time.Time
+	period   time.Duration // zero for a timer
+	active   bool
+}
+
+func (w *fakeWaiter) fire() {
+	select {
+	case w.c <- w.deadline:
+	default:
+	}
+	if w.period > 0 {
+		w.deadline = w.deadline.Add(w.period)
+	} else {
+		w.active = false
+	}
+}
+
+func (w *fakeWaiter) C() <-chan time.Time { return w.c }
+
+// Stop deactivates the waiter, reporting whether it was still pending.
+func (w *fakeWaiter) Stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	wasActive := w.active
+	w.active = false
+	return wasActive
+}
+
+func (w *fakeWaiter) Reset(d time.Duration) bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	wasActive := w.active
+	w.deadline = w.clock.now.Add(d)
+	w.active = true
+	return wasActive
+}
+
+type fakeTicker struct{ *fakeWaiter }
+
+func (t fakeTicker) Stop() { t.fakeWaiter.Stop() }
@@ -0,0 +1,178 @@
+package driver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/seuros/gopher-cypher/src/bolt/messaging"
+)
+
+// compressionGzip is the only codec the driver offers in HELLO.
+const compressionGzip = "gzip"
+
+// compressionMetadata returns the HELLO entries advertising the codecs the
+// driver can speak.
+func compressionMetadata() map[string]interface{} {
+	return map[string]interface{}{"compression": []interface{}{compressionGzip}}
+}
+
+// negotiatedCompression returns the codec the server picked in its HELLO
+// SUCCESS, or "" when it did not agree to one the driver offered.
+func negotiatedCompression(metadata map[string]interface{}) string {
+	if codec, _ := metadata["compression"].(string); codec == compressionGzip {
+		return codec
+	}
+	return ""
+}
+
+// compressedConn gzips each Bolt message on top of the chunk framing. The
+// caller keeps writing and reading ordinary chunked messages: writes are
+// collected until a message's end marker, then sent as one chunked message
+// whose payload is the compressed original; reads undo that before handing
+// the re-chunked message back. NOOP chunks pass through untouched. Partial
+// reads are buffered, so a read deadline expiring mid-message (as in the
+// liveness check) does not lose data. A read message larger than maxSize,
+// compressed or once decompressed, fails with messaging.ErrMessageTooLarge
+// so compression can't be used to slip past Config.MaxMessageSize.
+type compressedConn struct {
+	net.Conn
+	maxSize int
+
+	wframe []byte       // framed bytes written that don't yet form a chunk
+	wmsg   bytes.Buffer // payload of the message being written
+
+	rframe []byte       // framed bytes read that don't yet form a chunk
+	rmsg   bytes.Buffer // compressed payload of the message being read
+	rout   bytes.Buffer // decompressed, re-chunked bytes for the caller
+	rbuf   [4096]byte
+}
+
+// newCompressedConn wraps conn, limiting read messages to maxSize bytes. A
+// maxSize <= 0 uses messaging.DefaultMaxMessageSize.
+func newCompressedConn(conn net.Conn, maxSize int) *compressedConn {
+	if maxSize <= 0 {
+		maxSize = messaging.DefaultMaxMessageSize
+	}
+	return &compressedConn{Conn: conn, maxSize: maxSize}
+}
+
+func (c *compressedConn) Write(b []byte) (int, error) {
+	c.wframe = append(c.wframe, b...)
+	for {
+		payload, rest, ok := nextChunk(c.wframe)
+		if !ok {
+			break
+		}
+		c.wframe = rest
+		if len(payload) > 0 {
+			c.wmsg.Write(payload)
+			continue
+		}
+
+		var out []byte
+		if c.wmsg.Len() == 0 {
+			out = []byte{0x00, 0x00} // NOOP
+		} else {
+			var compressed bytes.Buffer
+			zw := gzip.NewWriter(&compressed)
+			_, _ = zw.Write(c.wmsg.Bytes())
+			if err := zw.Close(); err != nil {
+				return 0, err
+			}
+			c.wmsg.Reset()
+			out = appendChunked(nil, compressed.Bytes())
+		}
+		if _, err := c.Conn.Write(out); err != nil {
+			return 0, err
+		}
+	}
+	// Compact so a long session doesn't keep growing the backing array.
+	c.wframe = append([]byte(nil), c.wframe...)
+	return len(b), nil
+}
+
+func (c *compressedConn) Read(p []byte) (int, error) {
+	for c.rout.Len() == 0 {
+		n, err := c.Conn.Read(c.rbuf[:])
+		c.rframe = append(c.rframe, c.rbuf[:n]...)
+		if perr := c.drainFrames(); perr != nil {
+			return 0, perr
+		}
+		if err != nil && c.rout.Len() == 0 {
+			return 0, err
+		}
+	}
+	return c.rout.Read(p)
+}
+
+// drainFrames moves every complete chunk of rframe into rmsg, decompressing
+// finished messages into rout.
+func (c *compressedConn) drainFrames() error {
+	for {
+		payload, rest, ok := nextChunk(c.rframe)
+		if !ok {
+			break
+		}
+		c.rframe = rest
+		if len(payload) > 0 {
+			if c.rmsg.Len()+len(payload) > c.maxSize {
+				return fmt.Errorf("%w of %d bytes", messaging.ErrMessageTooLarge, c.maxSize)
+			}
+			c.rmsg.Write(payload)
+			continue
+		}
+		if c.rmsg.Len() == 0 {
+			c.rout.Write([]byte{0x00, 0x00}) // NOOP
+			continue
+		}
+
+		zr, err := gzip.NewReader(&c.rmsg)
+		if err != nil {
+			return err
+		}
+		// Read one byte past the limit to tell a message that fits exactly
+		// from one that does not.
+		message, err := io.ReadAll(io.LimitReader(zr, int64(c.maxSize)+1))
+		if err != nil {
+			return err
+		}
+		if len(message) > c.maxSize {
+			return fmt.Errorf("%w of %d bytes once decompressed", messaging.ErrMessageTooLarge, c.maxSize)
+		}
+		c.rmsg.Reset()
+		c.rout.Write(appendChunked(nil, message))
+	}
+	c.rframe = append([]byte(nil), c.rframe...)
+	return nil
+}
+
+// nextChunk splits the first complete chunk off framed data.
+func nextChunk(data []byte) (payload, rest []byte, ok bool) {
+	if len(data) < 2 {
+		return nil, data, false
+	}
+	size := int(binary.BigEndian.Uint16(data))
+	if len(data) < 2+size {
+		return nil, data, false
+	}
+	return data[2 : 2+size], data[2+size:], true
+}
+
+// appendChunked appends message to dst as chunks of at most 0xFFFF bytes
+// followed by the end marker.
+func appendChunked(dst, message []byte) []byte {
+	for len(message) > 0 {
+		size := len(message)
+		if size > 0xFFFF {
+			size = 0xFFFF
+		}
+		dst = binary.BigEndian.AppendUint16(dst, uint16(size))
+		dst = append(dst, message[:size]...)
+		message = message[size:]
+	}
+	return append(dst, 0x00, 0x00)
+}
@@ -0,0 +1,153 @@
+package driver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/seuros/gopher-cypher/src/bolt/messaging"
+	"github.com/seuros/gopher-cypher/src/driver/testserver"
+)
+
+func TestCompressedConn_RoundTrip(t *testing.T) {
+	client, wire := net.Pipe()
+	defer client.Close()
+	defer wire.Close()
+
+	data, err := messaging.PackMessage(messaging.RunSignature, []interface{}{
+		"RETURN $s", map[string]interface{}{"s": strings.Repeat("compress me ", 200)}, map[string]interface{}{},
+	})
+	if err != nil {
+		t.Fatalf("PackMessage failed: %v", err)
+	}
+	framed := appendChunked(nil, data)
+	framed = append(framed, 0x00, 0x00) // a NOOP after the message
+
+	// Capture what actually crosses the wire.
+	raw := make(chan []byte, 1)
+	go func() {
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, wire)
+		raw <- buf.Bytes()
+	}()
+
+	cc := newCompressedConn(client, 0)
+	// Write in awkward pieces to exercise the framing buffer.
+	for i := 0; i < len(framed); i += 7 {
+		end := i + 7
+		if end > len(framed) {
+			end = len(framed)
+		}
+		if _, err := cc.Write(framed[i:end]); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	client.Close()
+	sent := <-raw
+
+	if len(sent) >= len(framed) {
+		t.Errorf("expected the message to shrink, sent %d bytes for %d", len(sent), len(framed))
+	}
+	if sent[2] != 0x1f || sent[3] != 0x8b {
+		t.Errorf("expected a gzip payload, got % X", sent[:4])
+	}
+	if !bytes.HasSuffix(sent, []byte{0x00, 0x00, 0x00, 0x00}) {
+		t.Errorf("expected the end marker followed by the NOOP, got % X", sent[len(sent)-4:])
+	}
+
+	// Reading the compressed stream back yields the original framing.
+	server, peer := net.Pipe()
+	defer server.Close()
+	go func() {
+		_, _ = peer.Write(sent)
+		peer.Close()
+	}()
+	got, err := io.ReadAll(newCompressedConn(server, 0))
+	if err != nil && err != io.EOF {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if !bytes.Equal(got, framed) {
+		t.Errorf("round trip differs: got %d bytes, want %d", len(got), len(framed))
+	}
+}
+
+func TestCompressedConn_MessageSizeLimit(t *testing.T) {
+	gzipped := func(data []byte) []byte {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, _ = zw.Write(data)
+		_ = zw.Close()
+		return buf.Bytes()
+	}
+	read := func(framed []byte, maxSize int) error {
+		conn, peer := net.Pipe()
+		defer conn.Close()
+		go func() {
+			_, _ = peer.Write(framed)
+			peer.Close()
+		}()
+		_, err := io.ReadAll(newCompressedConn(conn, maxSize))
+		return err
+	}
+
+	// 1 MiB of zeros compresses to about a kilobyte, well under the limit.
+	bomb := gzipped(make([]byte, 1<<20))
+	if len(bomb) >= 64<<10 {
+		t.Fatalf("expected the payload to compress below the limit, got %d bytes", len(bomb))
+	}
+	if err := read(appendChunked(nil, bomb), 64<<10); !errors.Is(err, messaging.ErrMessageTooLarge) {
+		t.Errorf("expected ErrMessageTooLarge once decompressed, got %v", err)
+	}
+
+	// A compressed message is capped before it is decompressed.
+	if err := read(appendChunked(nil, gzipped([]byte("hi"))), 16); !errors.Is(err, messaging.ErrMessageTooLarge) {
+		t.Errorf("expected ErrMessageTooLarge for the compressed frame, got %v", err)
+	}
+
+	// A message that fits is passed through.
+	small := gzipped(make([]byte, 1024))
+	if err := read(appendChunked(nil, small), 64<<10); err != nil && err != io.EOF {
+		t.Errorf("expected a message within the limit to be read, got %v", err)
+	}
+}
+
+func TestDriver_CompressionDeclined(t *testing.T) {
+	srv, err := testserver.New()
+	if err != nil {
+		t.Fatalf("failed to start test server: %v", err)
+	}
+	defer srv.Close()
+	srv.Handle("RETURN 1 AS n", testserver.Result{Fields: []string{"n"}, Records: [][]interface{}{{1}}})
+
+	config := DefaultConfig()
+	config.EnableCompression = true
+	d, err := NewDriverWithConfig(srv.URL(), config)
+	if err != nil {
+		t.Fatalf("failed to create driver: %v", err)
+	}
+	defer d.Close()
+
+	_, rows, err := d.Run(context.Background(), "RETURN 1 AS n", nil, nil)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(rows) != 1 || rows[0]["n"] != int64(1) {
+		t.Errorf("unexpected rows %v", rows)
+	}
+
+	var hello map[string]interface{}
+	for _, msg := range srv.Requests() {
+		if msg.Signature() == messaging.HelloSignature {
+			hello, _ = msg.Fields()[0].(map[string]interface{})
+		}
+	}
+	if want := []interface{}{"gzip"}; !reflect.DeepEqual(hello["compression"], want) {
+		t.Errorf("expected HELLO to offer %v, got %v", want, hello["compression"])
+	}
+}
@@ -1,8 +1,11 @@
 package driver
 
 import (
+	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -13,6 +16,13 @@ type Config struct {
 	// TLS holds TLS-specific configuration
 	TLS *TLSConfig
 
+	// TLSPinnedFingerprints lists the SHA-256 digests of the DER-encoded
+	// server certificates to accept. When set, the server's leaf certificate
+	// must match one of them and CA verification is skipped; this also
+	// applies to TLSConfig.Config and to +ssc URLs. NewDriverWithConfig
+	// fails when pins are set for a URL without +ssl or +ssc.
+	TLSPinnedFingerprints [][32]byte
+
 	// ConnectionPool holds connection pool configuration
 	ConnectionPool *PoolConfig
 
@@ -21,8 +31,102 @@ type Config struct {
 
 	// Logging holds logging configuration
 	Logging *LoggingConfig
+
+	// FetchSize is the number of records requested per PULL when streaming.
+	// Default: 1000. Values <= 0 fall back to the default.
+	FetchSize int
+
+	// StreamIdleTimeout closes a streaming result that has gone this long
+	// without a PULL, so a result abandoned without Close or Consume does not
+	// hold its connection forever. Reading an unfinished result after that
+	// fails with a UsageError; records already buffered stay readable.
+	// Default: 0, which disables it.
+	StreamIdleTimeout time.Duration
+
+	// EnableCompression offers gzip message compression in HELLO. When the
+	// server agrees, every message on the connection is compressed; when it
+	// does not, the connection stays uncompressed. Default: false.
+	EnableCompression bool
+
+	// CoerceTemporalStrings converts parameter strings holding ISO-8601
+	// dates, date-times and durations (e.g. "2023-01-02T03:04:05Z" or
+	// "P1DT2H") into the matching Bolt temporal types, so they compare
+	// equal to stored temporal properties. Default: false.
+	CoerceTemporalStrings bool
+
+	// QueryTimeout is sent to the server as tx_timeout so it aborts queries
+	// that outlive the client. Zero leaves the server default in place.
+	QueryTimeout time.Duration
+
+	// NotificationFilter controls which server notifications are reported.
+	// Default: minimum severity WARNING. Empty fields are not sent, leaving
+	// the server default in place.
+	NotificationFilter *NotificationFilter
+
+	// KeepAliveInterval sends a NOOP chunk on pooled connections that have been
+	// idle this long, so load balancers don't drop them. Zero disables it.
+	KeepAliveInterval time.Duration
+
+	// QueryGuard rejects oversized queries before they are sent. Nil disables it.
+	QueryGuard *QueryGuard
+
+	// ConnectRetry retries transient connection failures with exponential
+	// backoff. Nil disables retries.
+	ConnectRetry *ConnectRetry
+
+	// MaxMessageSize caps the size in bytes of any message read from the
+	// server, by Run and RunStream alike; a larger one fails the query with
+	// messaging.ErrMessageTooLarge. With EnableCompression it bounds each
+	// message both compressed and once decompressed. Default:
+	// messaging.DefaultMaxMessageSize (16 MiB). Values <= 0 fall back to the
+	// default.
+	MaxMessageSize int
+
+	// AutoReconnectReads re-runs a streaming read query on a fresh
+	// connection when its connection breaks before any record was
+	// delivered. Only queries inferred as READ are re-run, and only once.
+	// Default: false.
+	AutoReconnectReads bool
+
+	// QueryInterceptor, when set, sees every query before it is sent by Run,
+	// RunWithContext and RunStream. It returns the query and parameters to
+	// send instead, or an error to reject the query. QueryGuard checks the
+	// rewritten query.
+	QueryInterceptor func(ctx context.Context, query string, params map[string]interface{}) (string, map[string]interface{}, error)
+}
+
+// NotificationFilter maps to the Bolt 5.2 notification settings sent in HELLO
+// and transaction metadata.
+type NotificationFilter struct {
+	// MinSeverity is the lowest severity reported: "WARNING", "INFORMATION" or "OFF"
+	MinSeverity string
+
+	// DisabledCategories lists notification categories to suppress, e.g. "UNRECOGNIZED"
+	DisabledCategories []string
 }
 
+// metadata returns the Bolt fields for the filter, omitting unset values.
+func (f *NotificationFilter) metadata() map[string]interface{} {
+	out := make(map[string]interface{})
+	if f == nil {
+		return out
+	}
+	if f.MinSeverity != "" {
+		out["notifications_minimum_severity"] = f.MinSeverity
+	}
+	if len(f.DisabledCategories) > 0 {
+		categories := make([]interface{}, len(f.DisabledCategories))
+		for i, category := range f.DisabledCategories {
+			categories[i] = category
+		}
+		out["notifications_disabled_categories"] = categories
+	}
+	return out
+}
+
+// DefaultFetchSize is the number of records requested per PULL round-trip.
+const DefaultFetchSize = 1000
+
 // TLSConfig provides advanced TLS configuration options
 type TLSConfig struct {
 	// Config allows passing a custom tls.Config directly
@@ -96,6 +200,10 @@ func DefaultConfig() *Config {
 		},
 		Observability: DefaultObservabilityConfig(),
 		Logging:       DefaultLoggingConfig(),
+		FetchSize:     DefaultFetchSize,
+		NotificationFilter: &NotificationFilter{
+			MinSeverity: "WARNING",
+		},
 	}
 }
 
@@ -157,3 +265,33 @@ func (tc *TLSConfig) buildTLSConfig(serverName string) *tls.Config {
 
 	return config
 }
+
+// pinTLSConfig makes config accept only servers whose leaf certificate
+// matches one of pins. The pin replaces chain verification, which would
+// otherwise reject the self-signed certificates pinning is typically used
+// with.
+func pinTLSConfig(config *tls.Config, pins [][32]byte) *tls.Config {
+	if len(pins) == 0 {
+		return config
+	}
+	pins = append([][32]byte(nil), pins...)
+	next := config.VerifyConnection
+
+	config.InsecureSkipVerify = true
+	config.VerifyConnection = func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("tls: server presented no certificate to check against the pinned fingerprints")
+		}
+		fingerprint := sha256.Sum256(cs.PeerCertificates[0].Raw)
+		for _, pin := range pins {
+			if pin == fingerprint {
+				if next != nil {
+					return next(cs)
+				}
+				return nil
+			}
+		}
+		return fmt.Errorf("tls: server certificate fingerprint %x is not pinned", fingerprint)
+	}
+	return config
+}
@@ -1,10 +1,19 @@
 package driver
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/seuros/gopher-cypher/src/driver/testserver"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -31,6 +40,10 @@ func TestDefaultConfig(t *testing.T) {
 	if config.ConnectionPool.ConnectionLifetime != 1*time.Hour {
 		t.Errorf("Expected 1 hour lifetime, got %v", config.ConnectionPool.ConnectionLifetime)
 	}
+
+	if config.StreamIdleTimeout != 0 {
+		t.Errorf("Expected the stream idle timeout disabled, got %v", config.StreamIdleTimeout)
+	}
 }
 
 func TestTLSConfigBuild(t *testing.T) {
@@ -173,3 +186,85 @@ func TestNewDriverWithConfig(t *testing.T) {
 		t.Logf("Expected connection error with custom config: %v", err)
 	}
 }
+
+// newPinningServer serves Bolt over TLS with a freshly generated self-signed
+// certificate and returns its address and the certificate's fingerprint.
+func newPinningServer(t *testing.T) (string, [32]byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "pinned.test"},
+		DNSNames:     []string{"pinned.test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	})
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	srv, err := testserver.New()
+	if err != nil {
+		t.Fatalf("failed to start test server: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = listener.Close()
+		_ = srv.Close()
+	})
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() { _ = srv.ServeConn(conn) }()
+		}
+	}()
+
+	return listener.Addr().String(), sha256.Sum256(der)
+}
+
+func TestTLSPinnedFingerprints(t *testing.T) {
+	addr, fingerprint := newPinningServer(t)
+	url := "neo4j+ssl://neo4j:password@" + addr
+
+	newConfig := func(pin [32]byte) *Config {
+		config := DefaultConfig()
+		config.TLSPinnedFingerprints = [][32]byte{{0x01}, pin}
+		return config
+	}
+
+	dr, err := NewDriverWithConfig(url, newConfig(fingerprint))
+	if err != nil {
+		t.Fatalf("expected the pinned certificate to be accepted: %v", err)
+	}
+	_ = dr.Close()
+
+	wrong := fingerprint
+	wrong[0] ^= 0xFF
+	if _, err := NewDriverWithConfig(url, newConfig(wrong)); err == nil || !strings.Contains(err.Error(), "is not pinned") {
+		t.Fatalf("expected an unpinned certificate to be rejected, got %v", err)
+	}
+
+	// Without a pin the self-signed certificate fails CA verification.
+	if _, err := NewDriverWithConfig(url, DefaultConfig()); err == nil {
+		t.Fatal("expected the self-signed certificate to be rejected without a pin")
+	}
+
+	// Pins only apply during a TLS handshake, so a plain URL is refused
+	// rather than connecting unpinned.
+	if _, err := NewDriverWithConfig("neo4j://neo4j:password@"+addr, newConfig(fingerprint)); err == nil || !strings.Contains(err.Error(), "requires a +ssl or +ssc") {
+		t.Fatalf("expected pins without TLS to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"syscall"
+	"time"
+)
+
+// ConnectRetry retries transient dial and handshake failures when a query
+// acquires a connection, backing off exponentially between attempts.
+type ConnectRetry struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values <= 1 disable retries.
+	MaxAttempts int
+
+	// InitialBackoff is the wait before the second attempt; it doubles after
+	// each failure. Default: 100ms.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the wait between attempts. Default: 5s.
+	MaxBackoff time.Duration
+}
+
+func (r *ConnectRetry) attempts() int {
+	if r == nil || r.MaxAttempts <= 1 {
+		return 1
+	}
+	return r.MaxAttempts
+}
+
+func (r *ConnectRetry) initialBackoff() time.Duration {
+	if r == nil || r.InitialBackoff <= 0 {
+		return 100 * time.Millisecond
+	}
+	return r.InitialBackoff
+}
+
+func (r *ConnectRetry) maxBackoff() time.Duration {
+	if r == nil || r.MaxBackoff <= 0 {
+		return 5 * time.Second
+	}
+	return r.MaxBackoff
+}
+
+// isTransientConnectError reports whether err is a network failure worth
+// retrying: a refused or reset connection, or a timeout. Failures that another
+// attempt won't fix, such as an unknown host, a rejected certificate or
+// rejected credentials, are not.
+func isTransientConnectError(err error) bool {
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// acquireConn takes a connection from the pool and makes sure it is
+// authenticated, retrying transient failures per Config.ConnectRetry. On
+// error, stage names the step that failed: "connect" or "authenticate".
+func (d *driver) acquireConn(ctx context.Context) (pc *pooledConn, stage string, err error) {
+	policy := d.config.ConnectRetry
+	backoff := policy.initialBackoff()
+
+	for attempt := 1; ; attempt++ {
+		pc, stage, err = d.acquireConnOnce()
+		if err == nil {
+			return pc, "", nil
+		}
+		if attempt >= policy.attempts() || !isTransientConnectError(err) {
+			return nil, stage, err
+		}
+
+		logEvent(d.logger, d.config.Logging, LogLevelWarn, LogCategoryConnection, "Connection attempt failed, retrying", "attempt", attempt, "backoff", backoff, "error", err)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, stage, errors.Join(ctx.Err(), err)
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if limit := policy.maxBackoff(); backoff > limit {
+			backoff = limit
+		}
+	}
+}
+
+func (d *driver) acquireConnOnce() (*pooledConn, string, error) {
+	logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryConnection, "Acquiring connection from pool")
+
+	conn, err := d.netPool.Get()
+	if err != nil {
+		logEvent(d.logger, d.config.Logging, LogLevelError, LogCategoryConnection, "Failed to acquire connection from pool", "error", err)
+		return nil, "connect", err
+	}
+
+	logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryConnection, "Connection acquired from pool")
+
+	// Ensure connection is authenticated (with liveness check and conditional handshake)
+	pc, err := d.ensureAuthenticated(conn)
+	if err != nil {
+		d.netPool.Put(conn, err)
+		return nil, "authenticate", err
+	}
+	return pc, "", nil
+}
@@ -0,0 +1,106 @@
+package driver
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/seuros/gopher-cypher/src/bolt/messaging"
+	"github.com/yudhasubki/netpool"
+)
+
+// newFlakyDriver returns a scripted driver whose dialer fails failures times
+// with a refused connection before handing out conn.
+func newFlakyDriver(t *testing.T, conn net.Conn, failures int, dials *int) *driver {
+	t.Helper()
+	d := newScriptedDriver(t, conn)
+	pc := newPooledConn(conn)
+	pc.markAuthenticated(5, 4)
+	pool, err := netpool.New(func() (net.Conn, error) {
+		*dials++
+		if *dials <= failures {
+			return nil, &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+		}
+		return pc, nil
+	}, netpool.WithMinPool(0), netpool.WithMaxPool(1))
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	d.netPool = newConnPool(pool, 1)
+	return d
+}
+
+func TestConnectRetry_SucceedsOnThirdAttempt(t *testing.T) {
+	conn := &boltScriptConn{}
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"fields": []interface{}{"n"}})
+	conn.queue(t, messaging.RecordSignature, []interface{}{1})
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{})
+
+	dials := 0
+	d := newFlakyDriver(t, conn, 2, &dials)
+	d.config.ConnectRetry = &ConnectRetry{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+
+	_, rows, _, err := d.RunWithContext(context.Background(), "RETURN 1 AS n", nil, nil)
+	if err != nil {
+		t.Fatalf("RunWithContext failed: %v", err)
+	}
+	if dials != 3 {
+		t.Errorf("expected 3 dial attempts, got %d", dials)
+	}
+	if len(rows) != 1 {
+		t.Errorf("expected 1 row, got %d", len(rows))
+	}
+}
+
+func TestConnectRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	dials := 0
+	d := newFlakyDriver(t, &boltScriptConn{}, 5, &dials)
+	d.config.ConnectRetry = &ConnectRetry{MaxAttempts: 2, InitialBackoff: time.Millisecond}
+
+	_, err := d.RunStream(context.Background(), "RETURN 1", nil, nil)
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		t.Fatalf("expected connection refused, got %v", err)
+	}
+	if dials != 2 {
+		t.Errorf("expected 2 dial attempts, got %d", dials)
+	}
+}
+
+func TestIsTransientConnectError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"refused", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, true},
+		{"reset", &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, true},
+		{"timeout", &net.OpError{Op: "dial", Net: "tcp", Err: os.ErrDeadlineExceeded}, true},
+		{"unknown host", &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "nowhere.invalid", IsNotFound: true}}, false},
+		{"bad certificate", &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}, false},
+		{"tls handshake", tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}, false},
+		{"credentials", errors.New("The client is unauthorized due to authentication failure."), false},
+	}
+	for _, tt := range tests {
+		if got := isTransientConnectError(tt.err); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestConnectRetry_DisabledByDefault(t *testing.T) {
+	dials := 0
+	d := newFlakyDriver(t, &boltScriptConn{}, 1, &dials)
+
+	if _, _, _, err := d.RunWithContext(context.Background(), "RETURN 1", nil, nil); err == nil {
+		t.Fatal("expected the dial failure to be returned")
+	}
+	if dials != 1 {
+		t.Errorf("expected a single dial attempt, got %d", dials)
+	}
+}
@@ -28,6 +28,8 @@ type Driver interface {
 	// RunWithContext executes a Cypher query with context support and returns detailed summary.
 	// This is the recommended method for production use with observability.
 	RunWithContext(ctx context.Context, query string, params map[string]interface{}, metaData map[string]interface{}) ([]string, []map[string]interface{}, *ResultSummary, error)
+	// PoolStats returns a snapshot of the connection pool for monitoring.
+	PoolStats() PoolStats
 }
 
 // StreamingDriver extends Driver with streaming query capabilities for memory-efficient
@@ -42,10 +44,12 @@ type StreamingDriver interface {
 // driver implements the Driver interface using a pool of TCP connections.
 type driver struct {
 	urlResolver   *connection_url_resolver.ConnectionUrlResolver
-	netPool       *netpool.Netpool
+	netPool       *connPool
 	config        *Config
 	observability *observabilityInstruments
 	logger        Logger
+	keepAliveStop chan struct{}
+	router        *router
 }
 
 // NewDriver initializes a new Driver based on the provided connection URL.
@@ -75,9 +79,16 @@ func NewDriverWithConfig(urlString string, config *Config) (Driver, error) {
 	if config.Logging == nil {
 		config.Logging = defaults.Logging
 	}
+	if config.FetchSize <= 0 {
+		config.FetchSize = defaults.FetchSize
+	}
+	if config.NotificationFilter == nil {
+		config.NotificationFilter = defaults.NotificationFilter
+	}
 	d := driver{
 		config: config,
 	}
+	d.router = newRouter(d.route)
 
 	// Initialize logger
 	if config.Logging != nil && config.Logging.Logger != nil {
@@ -86,31 +97,35 @@ func NewDriverWithConfig(urlString string, config *Config) (Driver, error) {
 		d.logger = &NoOpLogger{}
 	}
 
-	d.logger.Info("Initializing gopher-cypher driver", "url", urlString)
+	logEvent(d.logger, d.config.Logging, LogLevelInfo, LogCategoryGeneral, "Initializing gopher-cypher driver", "url", urlString)
 
 	// Initialize observability
 	if config.Observability != nil && (config.Observability.EnableTracing || config.Observability.EnableMetrics) {
-		d.observability = initObservability()
-		d.logger.Debug("Observability enabled", "tracing", config.Observability.EnableTracing, "metrics", config.Observability.EnableMetrics)
+		d.observability = initObservability(config.Observability)
+		logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryGeneral, "Observability enabled", "tracing", config.Observability.EnableTracing, "metrics", config.Observability.EnableMetrics)
 	}
 
 	d.urlResolver = connection_url_resolver.NewConnectionUrlResolver(urlString)
 	if d.urlResolver.ToHash() == nil {
-		d.logger.Error("Failed to resolve connection URL", "url", urlString)
+		logEvent(d.logger, d.config.Logging, LogLevelError, LogCategoryGeneral, "Failed to resolve connection URL", "url", urlString)
 		return nil, fmt.Errorf("unable to resolve connection url: %s", urlString)
 	}
 
 	urlCfg := d.urlResolver.ToHash()
-	d.logger.Debug("Connection URL resolved", "host", urlCfg.Host, "port", urlCfg.Port, "ssl", urlCfg.SSL, "database", urlCfg.Database)
+	logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryConnection, "Connection URL resolved", "host", urlCfg.Host, "port", urlCfg.Port, "ssl", urlCfg.SSL, "database", urlCfg.Database)
+	if len(config.TLSPinnedFingerprints) > 0 && !urlCfg.SSL && !urlCfg.SSC {
+		// Pins are checked during the TLS handshake, so over plain TCP they
+		// would silently never apply.
+		logEvent(d.logger, d.config.Logging, LogLevelError, LogCategoryTLS, "TLS pins set for a plain TCP connection URL", "host", urlCfg.Host)
+		return nil, errors.New("TLSPinnedFingerprints requires a +ssl or +ssc connection url")
+	}
 
 	var err error
 	dialFn := func() (net.Conn, error) {
 		urlCfg := d.urlResolver.ToHash()
 		address := d.urlResolver.Address()
 
-		if config.Logging != nil && config.Logging.LogConnectionPool {
-			d.logger.Debug("Opening connection", "address", address, "ssl", urlCfg.SSL, "ssc", urlCfg.SSC)
-		}
+		logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryConnection, "Opening connection", "address", address, "ssl", urlCfg.SSL, "ssc", urlCfg.SSC)
 
 		var rawConn net.Conn
 		if urlCfg.SSL || urlCfg.SSC {
@@ -120,13 +135,14 @@ func NewDriverWithConfig(urlString string, config *Config) (Driver, error) {
 			// Override with URL-specific settings if needed
 			if urlCfg.SSC {
 				tlsCfg.InsecureSkipVerify = true
-				d.logger.Warn("TLS certificate verification disabled (SSC mode)", "address", address)
+				logEvent(d.logger, d.config.Logging, LogLevelWarn, LogCategoryTLS, "TLS certificate verification disabled (SSC mode)", "address", address)
 			}
+			tlsCfg = pinTLSConfig(tlsCfg, config.TLSPinnedFingerprints)
 
-			d.logger.Debug("Establishing TLS connection", "address", address, "server_name", tlsCfg.ServerName)
+			logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryTLS, "Establishing TLS connection", "address", address, "server_name", tlsCfg.ServerName)
 			rawConn, err = tls.Dial("tcp", address, tlsCfg)
 		} else {
-			d.logger.Debug("Establishing plain TCP connection", "address", address)
+			logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryConnection, "Establishing plain TCP connection", "address", address)
 			rawConn, err = net.Dial("tcp", address)
 		}
 
@@ -139,6 +155,7 @@ func NewDriverWithConfig(urlString string, config *Config) (Driver, error) {
 	}
 
 	var poolOpts []netpool.Opt
+	poolMax := defaultPoolMax
 	if d.config != nil && d.config.ConnectionPool != nil {
 		maxConnections := int32(d.config.ConnectionPool.MaxConnections)
 		if maxConnections <= 0 {
@@ -153,35 +170,46 @@ func NewDriverWithConfig(urlString string, config *Config) (Driver, error) {
 			minConnections = maxConnections
 		}
 
+		poolMax = int(maxConnections)
 		poolOpts = append(poolOpts,
 			netpool.WithMaxPool(maxConnections),
 			netpool.WithMinPool(minConnections),
 		)
 	}
 
-	d.netPool, err = netpool.New(dialFn, poolOpts...)
+	pool, err := netpool.New(dialFn, poolOpts...)
 	if err != nil {
-		d.logger.Error("Failed to create connection pool", "error", err)
+		logEvent(d.logger, d.config.Logging, LogLevelError, LogCategoryConnection, "Failed to create connection pool", "error", err)
 		return nil, err
 	}
 
-	d.logger.Debug("Connection pool created successfully")
+	d.netPool = newConnPool(pool, poolMax)
+
+	logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryConnection, "Connection pool created successfully")
 
 	err = d.Ping()
 	if err != nil {
-		d.logger.Error("Initial ping failed", "error", err)
+		logEvent(d.logger, d.config.Logging, LogLevelError, LogCategoryConnection, "Initial ping failed", "error", err)
 		return nil, err
 	}
 
-	d.logger.Info("Driver initialized successfully", "address", d.urlResolver.Address())
+	if config.KeepAliveInterval > 0 {
+		d.startKeepAlive(config.KeepAliveInterval)
+	}
+
+	logEvent(d.logger, d.config.Logging, LogLevelInfo, LogCategoryGeneral, "Driver initialized successfully", "address", d.urlResolver.Address())
 	return &d, nil
 }
 
 // Close shuts down the driver's connection pool.
 func (d *driver) Close() error {
-	d.logger.Info("Closing driver")
+	logEvent(d.logger, d.config.Logging, LogLevelInfo, LogCategoryGeneral, "Closing driver")
+	if d.keepAliveStop != nil {
+		close(d.keepAliveStop)
+		d.keepAliveStop = nil
+	}
 	if d.netPool == nil {
-		d.logger.Debug("Connection pool closed")
+		logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryConnection, "Connection pool closed")
 		return nil
 	}
 
@@ -194,6 +222,6 @@ func (d *driver) Close() error {
 		}
 		d.netPool.Put(conn, errors.New("driver closed"))
 	}
-	d.logger.Debug("Connection pool closed")
+	logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryConnection, "Connection pool closed")
 	return nil
 }
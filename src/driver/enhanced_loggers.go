@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 	"runtime"
 	"strings"
 	"sync"
@@ -17,9 +18,16 @@ type EnhancedConsoleLogger struct {
 	Output           io.Writer
 	IncludeTimestamp bool
 	IncludeSource    bool
-	ColorEnabled     bool
-	CategoryLevels   map[LogCategory]LogLevel
-	mu               sync.RWMutex
+	// ColorEnabled requests ANSI colors. They are only emitted when Output is
+	// a terminal, so redirected logs stay clean, unless ForceColor is set.
+	ColorEnabled   bool
+	ForceColor     bool
+	CategoryLevels map[LogCategory]LogLevel
+	mu             sync.RWMutex
+	// ttyFile is the Output file last checked by isTerminal and ttyResult
+	// its answer, so the file is only stat'ed again when Output changes.
+	ttyFile   *os.File
+	ttyResult bool
 }
 
 // Color codes for different log levels
@@ -32,8 +40,49 @@ const (
 	ColorGreen  = "\033[32m"
 )
 
-func (l *EnhancedConsoleLogger) colorForLevel(level LogLevel) string {
+// useColor reports whether ANSI colors should be written to Output.
+func (l *EnhancedConsoleLogger) useColor() bool {
 	if !l.ColorEnabled {
+		return false
+	}
+	return l.ForceColor || l.outputIsTerminal()
+}
+
+// outputIsTerminal reports whether Output is a terminal, reusing the last
+// answer while Output is the same file.
+func (l *EnhancedConsoleLogger) outputIsTerminal() bool {
+	f, ok := l.Output.(*os.File)
+	if !ok {
+		return false
+	}
+
+	l.mu.RLock()
+	if l.ttyFile == f {
+		result := l.ttyResult
+		l.mu.RUnlock()
+		return result
+	}
+	l.mu.RUnlock()
+
+	result := isTerminal(f)
+	l.mu.Lock()
+	l.ttyFile, l.ttyResult = f, result
+	l.mu.Unlock()
+	return result
+}
+
+// isTerminal reports whether w is an *os.File attached to a character device.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func (l *EnhancedConsoleLogger) colorForLevel(level LogLevel, useColor bool) string {
+	if !useColor {
 		return ""
 	}
 	switch level {
@@ -76,9 +125,10 @@ func (l *EnhancedConsoleLogger) log(level LogLevel, category LogCategory, msg st
 	}
 
 	// Level with color
-	color := l.colorForLevel(level)
+	useColor := l.useColor()
+	color := l.colorForLevel(level, useColor)
 	reset := ColorReset
-	if !l.ColorEnabled {
+	if !useColor {
 		reset = ""
 	}
 	parts = append(parts, fmt.Sprintf("%s%-5s%s", color, level.String(), reset))
@@ -2,10 +2,14 @@ package driver
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"os"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/seuros/gopher-cypher/src/driver/testserver"
 )
 
 func TestEnhancedConsoleLogger_LogLevels(t *testing.T) {
@@ -364,3 +368,163 @@ func TestLogCategory_Constants(t *testing.T) {
 		}
 	}
 }
+
+func TestLoggingFromEnv(t *testing.T) {
+	t.Setenv("CYQ_LOG_LEVEL", "warn")
+	t.Setenv("CYQ_LOG_FORMAT", "json")
+	t.Setenv("CYQ_LOG_CATEGORIES", "bolt=debug, query=error,connection")
+
+	config := LoggingFromEnv()
+	logger, ok := config.Logger.(*EnhancedStructuredLogger)
+	if !ok {
+		t.Fatalf("Expected EnhancedStructuredLogger, got %T", config.Logger)
+	}
+	if config.Level != LogLevelWarn || logger.Level != LogLevelWarn {
+		t.Errorf("Expected WARN level, got config=%v logger=%v", config.Level, logger.Level)
+	}
+	if config.CategoryLevels[LogCategoryBolt] != LogLevelDebug || logger.CategoryLevels[LogCategoryBolt] != LogLevelDebug {
+		t.Errorf("Expected bolt=DEBUG override, got %v", config.CategoryLevels)
+	}
+	if config.CategoryLevels[LogCategoryQuery] != LogLevelError {
+		t.Errorf("Expected query=ERROR override, got %v", config.CategoryLevels[LogCategoryQuery])
+	}
+	if _, exists := config.CategoryLevels[LogCategoryConnection]; exists {
+		t.Error("A category without a level should not get an override")
+	}
+	if !config.EnabledCategories[LogCategoryConnection] {
+		t.Error("Expected connection category to be enabled")
+	}
+	if !config.LogBoltMessages || config.LogQueryTiming {
+		t.Errorf("Expected Bolt message logging on and query timing off, got %v/%v", config.LogBoltMessages, config.LogQueryTiming)
+	}
+}
+
+func TestLoggingFromEnv_ConsoleAndDefaults(t *testing.T) {
+	t.Setenv("CYQ_LOG_LEVEL", "debug")
+
+	config := LoggingFromEnv()
+	if logger, ok := config.Logger.(*EnhancedConsoleLogger); !ok || logger.Level != LogLevelDebug {
+		t.Fatalf("Expected debug EnhancedConsoleLogger, got %T", config.Logger)
+	}
+}
+
+func TestLoggingFromEnv_Unset(t *testing.T) {
+	for _, name := range []string{"CYQ_LOG_LEVEL", "CYQ_LOG_FORMAT", "CYQ_LOG_CATEGORIES"} {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+
+	if _, ok := LoggingFromEnv().Logger.(*NoOpLogger); !ok {
+		t.Error("Expected the silent default logger when no variables are set")
+	}
+}
+
+func TestEnhancedConsoleLogger_ColorAutoDetection(t *testing.T) {
+	var buf bytes.Buffer
+	logger := &EnhancedConsoleLogger{Level: LogLevelDebug, Output: &buf, ColorEnabled: true}
+
+	logger.Warn("redirected")
+	if strings.Contains(buf.String(), "\033[") {
+		t.Errorf("Expected no ANSI codes for non-terminal output, got %q", buf.String())
+	}
+
+	buf.Reset()
+	logger.ForceColor = true
+	logger.Warn("forced")
+	if !strings.Contains(buf.String(), ColorYellow+"WARN") {
+		t.Errorf("Expected ANSI codes with ForceColor, got %q", buf.String())
+	}
+
+	file, err := os.CreateTemp(t.TempDir(), "log")
+	if err != nil {
+		t.Fatalf("CreateTemp failed: %v", err)
+	}
+	defer file.Close()
+	if isTerminal(file) {
+		t.Error("A regular file should not be detected as a terminal")
+	}
+
+	// The check is made once per Output file, not on every line.
+	readFile := func(name string) string {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("ReadFile failed: %v", err)
+		}
+		return string(data)
+	}
+	logger = &EnhancedConsoleLogger{Level: LogLevelDebug, Output: file, ColorEnabled: true}
+	logger.Warn("first")
+	if logger.ttyFile != file || logger.ttyResult {
+		t.Fatalf("Expected the terminal check cached for the file, got %v, %v", logger.ttyFile, logger.ttyResult)
+	}
+	logger.ttyResult = true
+	logger.Warn("second")
+	if !strings.Contains(readFile(file.Name()), ColorYellow+"WARN") {
+		t.Error("Expected the cached answer to be reused for the same file")
+	}
+
+	other, err := os.CreateTemp(t.TempDir(), "log")
+	if err != nil {
+		t.Fatalf("CreateTemp failed: %v", err)
+	}
+	defer other.Close()
+	logger.Output = other
+	logger.Warn("third")
+	if logger.ttyFile != other || strings.Contains(readFile(other.Name()), "\033[") {
+		t.Error("Expected a new Output file to be checked again")
+	}
+}
+
+func TestDriverLogging_CategoryFilter(t *testing.T) {
+	srv, err := testserver.New()
+	if err != nil {
+		t.Fatalf("failed to start test server: %v", err)
+	}
+	defer srv.Close()
+	srv.Handle("RETURN 1 AS n", testserver.Result{Fields: []string{"n"}, Records: [][]interface{}{{1}}})
+
+	var buf bytes.Buffer
+	logger := &EnhancedConsoleLogger{Level: LogLevelOff, Output: &buf}
+	logger.SetCategoryLevel(LogCategoryBolt, LogLevelDebug)
+
+	config := DefaultConfig()
+	config.Logging = NewConsoleLoggingConfig(LogLevelDebug)
+	config.Logging.Logger = logger
+
+	d, err := NewDriverWithConfig(srv.URL(), config)
+	if err != nil {
+		t.Fatalf("failed to create driver: %v", err)
+	}
+	defer d.Close()
+	if _, _, err := d.Run(context.Background(), "RETURN 1 AS n", nil, nil); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	output := strings.TrimSpace(buf.String())
+	if !strings.Contains(output, "Performing Bolt handshake") || !strings.Contains(output, "Sending RUN message") {
+		t.Fatalf("expected bolt messages, got:\n%s", output)
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.Contains(line, "[bolt]") {
+			t.Errorf("unexpected non-bolt log line: %s", line)
+		}
+	}
+}
+
+func TestLoggingConfig_CategoryEnabled(t *testing.T) {
+	config := DefaultLoggingConfig()
+	if config.categoryEnabled(LogCategoryBolt, LogLevelDebug) {
+		t.Error("bolt debug should follow LogBoltMessages")
+	}
+	if !config.categoryEnabled(LogCategoryBolt, LogLevelError) {
+		t.Error("errors should pass without a feature flag")
+	}
+	config.EnabledCategories[LogCategoryBolt] = true
+	if !config.categoryEnabled(LogCategoryBolt, LogLevelDebug) {
+		t.Error("an explicitly enabled category should pass")
+	}
+	config.EnabledCategories[LogCategoryConnection] = false
+	if config.categoryEnabled(LogCategoryConnection, LogLevelError) {
+		t.Error("an explicitly disabled category should be dropped")
+	}
+}
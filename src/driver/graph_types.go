@@ -0,0 +1,301 @@
+package driver
+
+import "encoding/json"
+
+// Bolt structure signatures for graph values.
+const (
+	nodeSignature                = 0x4E
+	relationshipSignature        = 0x52
+	unboundRelationshipSignature = 0x72
+	pathSignature                = 0x50
+)
+
+// Node is a decoded graph node.
+type Node struct {
+	ID         int64
+	ElementID  string
+	Labels     []string
+	Properties map[string]interface{}
+}
+
+type nodeJSON struct {
+	ID         int64                  `json:"id"`
+	ElementID  string                 `json:"elementId,omitempty"`
+	Labels     []string               `json:"labels"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// MarshalJSON encodes the node as {id, elementId, labels, properties}.
+func (n *Node) MarshalJSON() ([]byte, error) {
+	return json.Marshal(nodeJSON{
+		ID:         n.ID,
+		ElementID:  n.ElementID,
+		Labels:     nonNilStrings(n.Labels),
+		Properties: jsonSafeMap(n.Properties),
+	})
+}
+
+// Relationship is a decoded graph relationship.
+type Relationship struct {
+	ID             int64
+	ElementID      string
+	StartID        int64
+	StartElementID string
+	EndID          int64
+	EndElementID   string
+	Type           string
+	Properties     map[string]interface{}
+}
+
+type relationshipJSON struct {
+	ID             int64                  `json:"id"`
+	ElementID      string                 `json:"elementId,omitempty"`
+	Type           string                 `json:"type"`
+	StartID        int64                  `json:"startId"`
+	StartElementID string                 `json:"startElementId,omitempty"`
+	EndID          int64                  `json:"endId"`
+	EndElementID   string                 `json:"endElementId,omitempty"`
+	Properties     map[string]interface{} `json:"properties"`
+}
+
+// MarshalJSON encodes the relationship as {id, elementId, type, startId,
+// endId, properties}.
+func (r *Relationship) MarshalJSON() ([]byte, error) {
+	return json.Marshal(relationshipJSON{
+		ID:             r.ID,
+		ElementID:      r.ElementID,
+		Type:           r.Type,
+		StartID:        r.StartID,
+		StartElementID: r.StartElementID,
+		EndID:          r.EndID,
+		EndElementID:   r.EndElementID,
+		Properties:     jsonSafeMap(r.Properties),
+	})
+}
+
+// Path is a decoded graph path: Nodes[i] and Nodes[i+1] are joined by
+// Relationships[i].
+type Path struct {
+	Nodes         []*Node
+	Relationships []*Relationship
+}
+
+type pathJSON struct {
+	Nodes         []*Node         `json:"nodes"`
+	Relationships []*Relationship `json:"relationships"`
+}
+
+// MarshalJSON encodes the path as {nodes, relationships}.
+func (p *Path) MarshalJSON() ([]byte, error) {
+	out := pathJSON{Nodes: p.Nodes, Relationships: p.Relationships}
+	if out.Nodes == nil {
+		out.Nodes = []*Node{}
+	}
+	if out.Relationships == nil {
+		out.Relationships = []*Relationship{}
+	}
+	return json.Marshal(out)
+}
+
+// decodeGraphValue converts the raw [signature, fields] structures produced by
+// the packstream decoder into Node, Relationship and Path values, recursing
+// into lists and maps. Unknown structures are returned unchanged.
+func decodeGraphValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case []interface{}:
+		if sig, fields, ok := rawStructure(x); ok {
+			if decoded := decodeGraphStructure(sig, fields); decoded != nil {
+				return decoded
+			}
+			return x
+		}
+		out := make([]interface{}, len(x))
+		for i, item := range x {
+			out[i] = decodeGraphValue(item)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(x))
+		for k, item := range x {
+			out[k] = decodeGraphValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func rawStructure(v []interface{}) (byte, []interface{}, bool) {
+	if len(v) != 2 {
+		return 0, nil, false
+	}
+	sig, ok := v[0].(byte)
+	if !ok {
+		return 0, nil, false
+	}
+	fields, ok := v[1].([]interface{})
+	return sig, fields, ok
+}
+
+func decodeGraphStructure(sig byte, fields []interface{}) interface{} {
+	switch sig {
+	case nodeSignature:
+		if n := decodeNode(fields); n != nil {
+			return n
+		}
+	case relationshipSignature:
+		if r := decodeRelationship(fields); r != nil {
+			return r
+		}
+	case pathSignature:
+		if p := decodePath(fields); p != nil {
+			return p
+		}
+	}
+	return nil
+}
+
+// decodeNode reads Node::Structure(id, labels, properties[, element_id]).
+func decodeNode(fields []interface{}) *Node {
+	if len(fields) < 3 {
+		return nil
+	}
+	n := &Node{
+		ID:         toInt64(fields[0]),
+		Labels:     toStrings(fields[1]),
+		Properties: toProperties(fields[2]),
+	}
+	if len(fields) > 3 {
+		n.ElementID, _ = fields[3].(string)
+	}
+	return n
+}
+
+// decodeRelationship reads Relationship::Structure(id, startNodeId, endNodeId,
+// type, properties[, element_id, start_node_element_id, end_node_element_id]).
+func decodeRelationship(fields []interface{}) *Relationship {
+	if len(fields) < 5 {
+		return nil
+	}
+	r := &Relationship{
+		ID:         toInt64(fields[0]),
+		StartID:    toInt64(fields[1]),
+		EndID:      toInt64(fields[2]),
+		Properties: toProperties(fields[4]),
+	}
+	r.Type, _ = fields[3].(string)
+	if len(fields) > 7 {
+		r.ElementID, _ = fields[5].(string)
+		r.StartElementID, _ = fields[6].(string)
+		r.EndElementID, _ = fields[7].(string)
+	}
+	return r
+}
+
+// decodePath reads Path::Structure(nodes, rels, indices). rels holds unbound
+// relationships; each pair of indices names a relationship (1-based, negative
+// when traversed against its direction) and the index of the next node.
+func decodePath(fields []interface{}) *Path {
+	if len(fields) < 3 {
+		return nil
+	}
+	rawNodes, _ := fields[0].([]interface{})
+	rawRels, _ := fields[1].([]interface{})
+	indices, _ := fields[2].([]interface{})
+
+	nodes := make([]*Node, 0, len(rawNodes))
+	for _, raw := range rawNodes {
+		list, _ := raw.([]interface{})
+		sig, nodeFields, ok := rawStructure(list)
+		if !ok || sig != nodeSignature {
+			return nil
+		}
+		n := decodeNode(nodeFields)
+		if n == nil {
+			return nil
+		}
+		nodes = append(nodes, n)
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	unbound := make([]*Relationship, 0, len(rawRels))
+	for _, raw := range rawRels {
+		list, _ := raw.([]interface{})
+		sig, relFields, ok := rawStructure(list)
+		if !ok || sig != unboundRelationshipSignature || len(relFields) < 3 {
+			return nil
+		}
+		r := &Relationship{ID: toInt64(relFields[0]), Properties: toProperties(relFields[2])}
+		r.Type, _ = relFields[1].(string)
+		if len(relFields) > 3 {
+			r.ElementID, _ = relFields[3].(string)
+		}
+		unbound = append(unbound, r)
+	}
+
+	path := &Path{Nodes: []*Node{nodes[0]}}
+	prev := nodes[0]
+	for i := 0; i+1 < len(indices); i += 2 {
+		relIndex := toInt64(indices[i])
+		nodeIndex := toInt64(indices[i+1])
+		if relIndex == 0 || nodeIndex < 0 || nodeIndex >= int64(len(nodes)) {
+			return nil
+		}
+		next := nodes[nodeIndex]
+
+		forward := relIndex > 0
+		if !forward {
+			relIndex = -relIndex
+		}
+		if relIndex > int64(len(unbound)) {
+			return nil
+		}
+		rel := *unbound[relIndex-1]
+		start, end := prev, next
+		if !forward {
+			start, end = next, prev
+		}
+		rel.StartID, rel.StartElementID = start.ID, start.ElementID
+		rel.EndID, rel.EndElementID = end.ID, end.ElementID
+
+		path.Relationships = append(path.Relationships, &rel)
+		path.Nodes = append(path.Nodes, next)
+		prev = next
+	}
+	return path
+}
+
+func toInt64(v interface{}) int64 {
+	switch x := v.(type) {
+	case int64:
+		return x
+	case int:
+		return int64(x)
+	}
+	return 0
+}
+
+func toStrings(v interface{}) []string {
+	list, _ := v.([]interface{})
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func toProperties(v interface{}) map[string]interface{} {
+	props, ok := v.(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}
+	}
+	out := make(map[string]interface{}, len(props))
+	for k, item := range props {
+		out[k] = decodeGraphValue(item)
+	}
+	return out
+}
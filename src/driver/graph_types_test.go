@@ -0,0 +1,82 @@
+package driver
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/seuros/gopher-cypher/src/bolt/packstream"
+)
+
+// packStructure encodes a packstream structure the way a server would.
+func packStructure(t *testing.T, signature byte, fields ...interface{}) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	buf.WriteByte(0xB0 | byte(len(fields)))
+	buf.WriteByte(signature)
+	packer := packstream.NewPacker(&buf)
+	for _, field := range fields {
+		if err := packer.Pack(field); err != nil {
+			t.Fatalf("failed to pack field: %v", err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestRecordMarshalJSON_Node(t *testing.T) {
+	raw, err := packstream.Unpack(packStructure(t, nodeSignature,
+		int64(7),
+		[]interface{}{"Person"},
+		map[string]interface{}{"name": "Alice", "age": int64(30)},
+		"4:db:7",
+	))
+	if err != nil {
+		t.Fatalf("failed to unpack: %v", err)
+	}
+
+	record := Record{"n": decodeGraphValue(raw)}
+	data, err := json.Marshal(record)
+	if err != nil {
+		t.Fatalf("failed to marshal record: %v", err)
+	}
+
+	want := `{"n":{"id":7,"elementId":"4:db:7","labels":["Person"],"properties":{"age":30,"name":"Alice"}}}`
+	if string(data) != want {
+		t.Errorf("unexpected JSON:\n got  %s\n want %s", data, want)
+	}
+}
+
+func TestDecodeGraphValue_Path(t *testing.T) {
+	alice := []interface{}{byte(nodeSignature), []interface{}{int64(1), []interface{}{"Person"}, map[string]interface{}{}}}
+	bob := []interface{}{byte(nodeSignature), []interface{}{int64(2), []interface{}{"Person"}, map[string]interface{}{}}}
+	knows := []interface{}{byte(unboundRelationshipSignature), []interface{}{int64(10), "KNOWS", map[string]interface{}{}}}
+
+	// (alice)<-[:KNOWS]-(bob): the relationship index is negative.
+	raw := []interface{}{byte(pathSignature), []interface{}{
+		[]interface{}{alice, bob},
+		[]interface{}{knows},
+		[]interface{}{int64(-1), int64(1)},
+	}}
+
+	path, ok := decodeGraphValue(raw).(*Path)
+	if !ok {
+		t.Fatalf("expected *Path, got %T", decodeGraphValue(raw))
+	}
+	if len(path.Nodes) != 2 || len(path.Relationships) != 1 {
+		t.Fatalf("unexpected path shape: %+v", path)
+	}
+	rel := path.Relationships[0]
+	if rel.Type != "KNOWS" || rel.StartID != 2 || rel.EndID != 1 {
+		t.Errorf("unexpected relationship: %+v", rel)
+	}
+
+	data, err := json.Marshal(path)
+	if err != nil {
+		t.Fatalf("failed to marshal path: %v", err)
+	}
+	want := `{"nodes":[{"id":1,"labels":["Person"],"properties":{}},{"id":2,"labels":["Person"],"properties":{}}],` +
+		`"relationships":[{"id":10,"type":"KNOWS","startId":2,"endId":1,"properties":{}}]}`
+	if string(data) != want {
+		t.Errorf("unexpected JSON:\n got  %s\n want %s", data, want)
+	}
+}
@@ -0,0 +1,159 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// prepare runs the configured QueryInterceptor and then the pre-send checks,
+// returning the query and parameters to send.
+func (d *driver) prepare(ctx context.Context, query string, params map[string]interface{}) (string, map[string]interface{}, error) {
+	if d.config.QueryInterceptor != nil {
+		var err error
+		query, params, err = d.config.QueryInterceptor(ctx, query, params)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	if err := d.config.QueryGuard.check(query, params); err != nil {
+		return "", nil, err
+	}
+	if err := checkParamRefs(query, params); err != nil {
+		return "", nil, err
+	}
+	return query, params, nil
+}
+
+// QueryGuard rejects oversized queries before they are sent to the server.
+// A zero field disables the corresponding check.
+type QueryGuard struct {
+	// MaxLength is the maximum query length in bytes.
+	MaxLength int
+
+	// MaxParams is the maximum number of parameters, counting both the
+	// supplied map and the distinct $name references in the query text.
+	MaxParams int
+}
+
+// check returns a *UsageError if query or params exceed the configured limits.
+func (g *QueryGuard) check(query string, params map[string]interface{}) error {
+	if g == nil {
+		return nil
+	}
+	if g.MaxLength > 0 && len(query) > g.MaxLength {
+		return NewUsageError(fmt.Sprintf("query length %d exceeds the limit of %d bytes", len(query), g.MaxLength))
+	}
+	if g.MaxParams > 0 {
+		count := countParamRefs(query)
+		if len(params) > count {
+			count = len(params)
+		}
+		if count > g.MaxParams {
+			return NewUsageError(fmt.Sprintf("query uses %d parameters, exceeding the limit of %d", count, g.MaxParams))
+		}
+	}
+	return nil
+}
+
+// countParamRefs returns the number of distinct $name parameters referenced
+// in query, ignoring anything inside string literals, backtick-quoted names
+// and comments.
+func countParamRefs(query string) int {
+	return len(paramRefs(query))
+}
+
+// checkParamRefs returns a *UsageError when query and params disagree: the
+// query has no $name placeholders but params were supplied, which usually
+// means it was compiled with literals inlined, or it references placeholders
+// that params lacks.
+func checkParamRefs(query string, params map[string]interface{}) error {
+	refs := paramRefs(query)
+	if len(refs) == 0 {
+		if len(params) > 0 {
+			return NewUsageError(fmt.Sprintf("query has no $ placeholders but %d parameters were supplied; was it compiled with inline literals?", len(params)))
+		}
+		return nil
+	}
+
+	var missing []string
+	for name := range refs {
+		if _, ok := params[name]; !ok {
+			missing = append(missing, "$"+name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return NewUsageError(fmt.Sprintf("query references %s, missing from the supplied parameters", strings.Join(missing, ", ")))
+	}
+	return nil
+}
+
+// paramRefs returns the distinct $name parameters referenced in query.
+func paramRefs(query string) map[string]struct{} {
+	seen := make(map[string]struct{})
+	for i := 0; i < len(query); i++ {
+		switch c := query[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			for i++; i < len(query) && query[i] != c; i++ {
+				if query[i] == '\\' && c != '`' {
+					i++
+				}
+			}
+		case c == '/' && i+1 < len(query) && query[i+1] == '/':
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(query) && query[i+1] == '*':
+			end := i + 2
+			for end+1 < len(query) && (query[end] != '*' || query[end+1] != '/') {
+				end++
+			}
+			i = end + 1
+		case c == '$':
+			name, end := paramName(query, i+1)
+			if name != "" {
+				seen[name] = struct{}{}
+			}
+			i = end - 1
+		}
+	}
+	return seen
+}
+
+// paramName reads the parameter name starting at query[start], just after a
+// $, and returns it with the offset just past it. The name is either a
+// backtick-quoted one such as `my param`, in which a doubled backtick stands
+// for one, or a run of Unicode letters, digits and underscores.
+func paramName(query string, start int) (string, int) {
+	if start < len(query) && query[start] == '`' {
+		var b strings.Builder
+		for i := start + 1; i < len(query); i++ {
+			if query[i] != '`' {
+				b.WriteByte(query[i])
+				continue
+			}
+			if i+1 < len(query) && query[i+1] == '`' {
+				b.WriteByte('`')
+				i++
+				continue
+			}
+			return b.String(), i + 1
+		}
+		// Unterminated; leave it for the server to reject.
+		return "", len(query)
+	}
+
+	end := start
+	for end < len(query) {
+		r, size := utf8.DecodeRuneInString(query[end:])
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			break
+		}
+		end += size
+	}
+	return query[start:end], end
+}
@@ -0,0 +1,156 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/seuros/gopher-cypher/src/bolt/messaging"
+)
+
+func TestRunWithContext_QueryGuardRejectsLongQuery(t *testing.T) {
+	config := DefaultConfig()
+	config.QueryGuard = &QueryGuard{MaxLength: 32}
+	d := &driver{config: config, logger: &NoOpLogger{}}
+
+	query := "RETURN '" + strings.Repeat("x", 64) + "'"
+	_, _, _, err := d.RunWithContext(context.Background(), query, nil, nil)
+
+	var usageErr *UsageError
+	if !errors.As(err, &usageErr) {
+		t.Fatalf("expected *UsageError, got %v", err)
+	}
+	if !strings.Contains(usageErr.Message, "exceeds the limit of 32 bytes") {
+		t.Errorf("unexpected message: %s", usageErr.Message)
+	}
+}
+
+func TestRunWithContext_QueryGuardRejectsTooManyParams(t *testing.T) {
+	config := DefaultConfig()
+	config.QueryGuard = &QueryGuard{MaxParams: 2}
+	d := &driver{config: config, logger: &NoOpLogger{}}
+
+	query := "MATCH (n) WHERE n.a = $a AND n.b = $b AND n.c = $c AND n.d = $a RETURN n"
+	_, _, _, err := d.RunWithContext(context.Background(), query, nil, nil)
+
+	var usageErr *UsageError
+	if !errors.As(err, &usageErr) {
+		t.Fatalf("expected *UsageError, got %v", err)
+	}
+	if !strings.Contains(usageErr.Message, "uses 3 parameters") {
+		t.Errorf("unexpected message: %s", usageErr.Message)
+	}
+}
+
+func TestCountParamRefs(t *testing.T) {
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"RETURN 1", 0},
+		{"RETURN $a, $b, $a", 2},
+		{"RETURN '$a', \"$b\", `$c`, $d", 1},
+		{"RETURN $a // $b\n, $c /* $d */", 2},
+		{"RETURN $0, $", 1},
+	}
+	for _, tt := range tests {
+		if got := countParamRefs(tt.query); got != tt.want {
+			t.Errorf("countParamRefs(%q) = %d, want %d", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestRunWithContext_RejectsParamsWithoutPlaceholders(t *testing.T) {
+	d := &driver{config: DefaultConfig(), logger: &NoOpLogger{}}
+
+	query := "MATCH (n) WHERE n.name = 'Alice' RETURN n"
+	_, _, _, err := d.RunWithContext(context.Background(), query, map[string]interface{}{"p1": "Alice"}, nil)
+
+	var usageErr *UsageError
+	if !errors.As(err, &usageErr) {
+		t.Fatalf("expected *UsageError, got %v", err)
+	}
+	if !strings.Contains(usageErr.Message, "no $ placeholders but 1 parameters were supplied") {
+		t.Errorf("unexpected message: %s", usageErr.Message)
+	}
+}
+
+func TestRunWithContext_RejectsMissingParams(t *testing.T) {
+	d := &driver{config: DefaultConfig(), logger: &NoOpLogger{}}
+
+	query := "MATCH (n) WHERE n.age > $p2 AND n.name = $p1 AND n.note = '$p3' RETURN n LIMIT $p4"
+	_, _, _, err := d.RunWithContext(context.Background(), query, map[string]interface{}{"p1": "Alice"}, nil)
+
+	var usageErr *UsageError
+	if !errors.As(err, &usageErr) {
+		t.Fatalf("expected *UsageError, got %v", err)
+	}
+	if !strings.Contains(usageErr.Message, "references $p2, $p4, missing") {
+		t.Errorf("unexpected message: %s", usageErr.Message)
+	}
+}
+
+func TestCheckParamRefs(t *testing.T) {
+	tests := []struct {
+		query  string
+		params map[string]interface{}
+		ok     bool
+	}{
+		{"RETURN 1", nil, true},
+		{"RETURN 1", map[string]interface{}{}, true},
+		{"RETURN $a", map[string]interface{}{"a": 1, "unused": 2}, true},
+		{"RETURN $config.timeout", map[string]interface{}{"config": map[string]interface{}{}}, true},
+		{"RETURN $a", map[string]interface{}{"a": nil}, true},
+		{"RETURN $`my param`", map[string]interface{}{"my param": 1}, true},
+		{"RETURN $`a``b`", map[string]interface{}{"a`b": 1}, true},
+		{"RETURN $größe, $名前", map[string]interface{}{"größe": 1, "名前": 2}, true},
+		{"RETURN 1", map[string]interface{}{"a": 1}, false},
+		{"RETURN $a", nil, false},
+		{"RETURN $`my param`", map[string]interface{}{"my": 1}, false},
+		{"RETURN $größe", map[string]interface{}{"gr": 1}, false},
+	}
+	for _, tt := range tests {
+		if err := checkParamRefs(tt.query, tt.params); (err == nil) != tt.ok {
+			t.Errorf("checkParamRefs(%q, %v) = %v, want ok=%v", tt.query, tt.params, err, tt.ok)
+		}
+	}
+}
+
+func TestRunWithContext_QueryInterceptor(t *testing.T) {
+	conn := &boltScriptConn{}
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"fields": []interface{}{"n"}})
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{})
+
+	d := newScriptedDriver(t, conn)
+	d.config.QueryInterceptor = func(ctx context.Context, query string, params map[string]interface{}) (string, map[string]interface{}, error) {
+		rewritten := map[string]interface{}{"tenant": "acme"}
+		for k, v := range params {
+			rewritten[k] = v
+		}
+		return strings.Replace(query, " RETURN", " WHERE n.tenant = $tenant RETURN", 1), rewritten, nil
+	}
+
+	_, _, _, err := d.RunWithContext(context.Background(), "MATCH (n) RETURN n", nil, nil)
+	if err != nil {
+		t.Fatalf("RunWithContext failed: %v", err)
+	}
+	sent := conn.sent(t)
+	if len(sent) == 0 || sent[0].Signature() != messaging.RunSignature {
+		t.Fatalf("expected RUN to be sent first, got %v", sent)
+	}
+	if got := sent[0].Fields()[0]; got != "MATCH (n) WHERE n.tenant = $tenant RETURN n" {
+		t.Errorf("expected the rewritten query in RUN, got %v", got)
+	}
+	if params, _ := sent[0].Fields()[1].(map[string]interface{}); params["tenant"] != "acme" {
+		t.Errorf("expected the tenant parameter in RUN, got %v", sent[0].Fields()[1])
+	}
+
+	rejected := errors.New("no tenant")
+	d.config.QueryInterceptor = func(ctx context.Context, query string, params map[string]interface{}) (string, map[string]interface{}, error) {
+		return "", nil, rejected
+	}
+	if _, err := d.RunStream(context.Background(), "MATCH (n) RETURN n", nil, nil); !errors.Is(err, rejected) {
+		t.Errorf("expected the interceptor to reject the query, got %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package driver
+
+import (
+	"errors"
+	"time"
+)
+
+// startKeepAlive launches the background loop that pings idle pooled
+// connections with NOOP chunks. It is stopped by Close.
+func (d *driver) startKeepAlive(interval time.Duration) {
+	d.keepAliveStop = make(chan struct{})
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				d.keepAliveIdle(interval)
+			case <-d.keepAliveStop:
+				return
+			}
+		}
+	}()
+}
+
+// keepAliveIdle visits each idle connection once, sending a NOOP to those
+// that have been quiet for at least interval. Connections whose write fails
+// are discarded.
+func (d *driver) keepAliveIdle(interval time.Duration) {
+	for i := d.netPool.Len(); i > 0; i-- {
+		if d.netPool.Len() == 0 {
+			// Everything was checked out meanwhile; Get would dial a new one.
+			return
+		}
+		conn, err := d.netPool.Get()
+		if err != nil {
+			return
+		}
+
+		pc, ok := conn.(*pooledConn)
+		if !ok {
+			d.netPool.Put(conn, nil)
+			continue
+		}
+
+		if err := pc.sendNoop(interval); err != nil {
+			logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryConnection, "Keep-alive NOOP failed, discarding connection", "error", err)
+			d.netPool.Put(conn, errors.Join(errors.New("keep-alive failed"), err))
+			continue
+		}
+		d.netPool.Put(conn, nil)
+	}
+}
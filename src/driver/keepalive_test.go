@@ -0,0 +1,45 @@
+package driver
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/yudhasubki/netpool"
+)
+
+func TestKeepAliveIdle_SendsNoopToQuietConnections(t *testing.T) {
+	conn := &boltScriptConn{}
+	pc := newPooledConn(conn)
+	pool, err := netpool.New(func() (net.Conn, error) {
+		return pc, nil
+	}, netpool.WithMinPool(1), netpool.WithMaxPool(1))
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+
+	d := &driver{netPool: newConnPool(pool, 1), config: DefaultConfig(), logger: &NoOpLogger{}}
+
+	// Freshly used connections are left alone.
+	pc.touch()
+	d.keepAliveIdle(time.Hour)
+	if conn.out.Len() != 0 {
+		t.Fatalf("expected no NOOP for a recently used connection, got %x", conn.out.Bytes())
+	}
+
+	pc.lastUsedAt = time.Now().Add(-2 * time.Second)
+	d.keepAliveIdle(time.Second)
+	if !bytes.Equal(conn.out.Bytes(), []byte{0x00, 0x00}) {
+		t.Fatalf("expected a single NOOP chunk, got %x", conn.out.Bytes())
+	}
+	if pool.Len() != 1 {
+		t.Errorf("expected connection to stay pooled, idle=%d", pool.Len())
+	}
+
+	// The NOOP itself resets the quiet period.
+	d.keepAliveIdle(time.Second)
+	if conn.out.Len() != 2 {
+		t.Errorf("expected no second NOOP within the interval, wrote %d bytes", conn.out.Len())
+	}
+}
@@ -255,6 +255,133 @@ func NewBoltTracingConfig(level LogLevel, output io.Writer) *LoggingConfig {
 	return config
 }
 
+// LoggingFromEnv builds a logging configuration from the environment so
+// logging can be tuned without code changes:
+//
+//	CYQ_LOG_LEVEL       global level (debug, info, warn, error, off)
+//	CYQ_LOG_FORMAT      console (default) or json; json is written to stderr
+//	CYQ_LOG_CATEGORIES  comma-separated categories, optionally with a level,
+//	                    e.g. "bolt=debug,query=warn,connection"
+//
+// Levels are read with ParseLogLevel. When none of the variables is set the
+// silent DefaultLoggingConfig is returned.
+func LoggingFromEnv() *LoggingConfig {
+	levelVar, hasLevel := os.LookupEnv("CYQ_LOG_LEVEL")
+	format, hasFormat := os.LookupEnv("CYQ_LOG_FORMAT")
+	categories, hasCategories := os.LookupEnv("CYQ_LOG_CATEGORIES")
+	if !hasLevel && !hasFormat && !hasCategories {
+		return DefaultLoggingConfig()
+	}
+
+	level := ParseLogLevel(strings.TrimSpace(levelVar))
+	var config *LoggingConfig
+	if strings.EqualFold(strings.TrimSpace(format), "json") {
+		config = NewStructuredLoggingConfig(level, os.Stderr)
+	} else {
+		config = NewConsoleLoggingConfig(level)
+	}
+
+	categorized, _ := config.Logger.(CategorizedLogger)
+	for _, entry := range strings.Split(categories, ",") {
+		name, levelName, hasCategoryLevel := strings.Cut(strings.TrimSpace(entry), "=")
+		if name == "" {
+			continue
+		}
+		category := LogCategory(strings.ToLower(strings.TrimSpace(name)))
+		categoryLevel := level
+		if hasCategoryLevel {
+			categoryLevel = ParseLogLevel(strings.TrimSpace(levelName))
+			config.CategoryLevels[category] = categoryLevel
+			if categorized != nil {
+				categorized.SetCategoryLevel(category, categoryLevel)
+			}
+		}
+		config.EnabledCategories[category] = categoryLevel != LogLevelOff
+		config.applyCategoryFlag(category, categoryLevel)
+	}
+	return config
+}
+
+// applyCategoryFlag keeps the legacy Log* feature flags in step with a
+// category's level, since plain loggers are still gated by them.
+func (c *LoggingConfig) applyCategoryFlag(category LogCategory, level LogLevel) {
+	debug := level <= LogLevelDebug
+	switch category {
+	case LogCategoryBolt:
+		c.LogBoltMessages = debug
+	case LogCategoryConnection:
+		c.LogConnectionPool = debug
+	case LogCategoryQuery:
+		c.LogQueryTiming = level <= LogLevelInfo
+	case LogCategoryAuth:
+		c.LogAuthEvents = debug
+	case LogCategoryTLS:
+		c.LogTLSEvents = debug
+	case LogCategoryStreaming:
+		c.LogStreamingEvents = debug
+	case LogCategoryReactive:
+		c.LogReactiveEvents = debug
+	}
+}
+
+// categoryEnabled reports whether the configuration lets a message of the
+// given category and level through. An explicit EnabledCategories entry wins;
+// otherwise warnings and errors always pass and lower levels follow the
+// category's Log* feature flag. Query timing is logged at info level only
+// when LogQueryTiming is set.
+func (c *LoggingConfig) categoryEnabled(category LogCategory, level LogLevel) bool {
+	if c == nil {
+		return true
+	}
+	if enabled, ok := c.EnabledCategories[category]; ok {
+		return enabled
+	}
+	if level >= LogLevelWarn {
+		return true
+	}
+	switch category {
+	case LogCategoryBolt:
+		return c.LogBoltMessages
+	case LogCategoryConnection:
+		return c.LogConnectionPool
+	case LogCategoryQuery:
+		return level < LogLevelInfo || c.LogQueryTiming
+	case LogCategoryAuth:
+		return c.LogAuthEvents
+	case LogCategoryTLS:
+		return c.LogTLSEvents
+	case LogCategoryStreaming:
+		return c.LogStreamingEvents
+	case LogCategoryReactive:
+		return c.LogReactiveEvents
+	}
+	return true
+}
+
+// logEvent is how the driver emits its own log messages. Messages the
+// configuration disables are dropped; the rest go through LogWithCategory
+// when the logger is a CategorizedLogger, so it can filter per category, and
+// through the matching level method otherwise.
+func logEvent(logger Logger, config *LoggingConfig, level LogLevel, category LogCategory, msg string, keysAndValues ...interface{}) {
+	if logger == nil || !config.categoryEnabled(category, level) {
+		return
+	}
+	if categorized, ok := logger.(CategorizedLogger); ok {
+		categorized.LogWithCategory(level, category, msg, keysAndValues...)
+		return
+	}
+	switch level {
+	case LogLevelDebug:
+		logger.Debug(msg, keysAndValues...)
+	case LogLevelInfo:
+		logger.Info(msg, keysAndValues...)
+	case LogLevelWarn:
+		logger.Warn(msg, keysAndValues...)
+	case LogLevelError:
+		logger.Error(msg, keysAndValues...)
+	}
+}
+
 // NoOpLogger is a logger that does nothing (default behavior)
 type NoOpLogger struct{}
 
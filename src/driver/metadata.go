@@ -0,0 +1,139 @@
+package driver
+
+import "time"
+
+// txTimeoutKey is the RUN/BEGIN metadata key carrying the server-side timeout.
+const txTimeoutKey = "tx_timeout"
+
+// bookmarksKey is the RUN/BEGIN metadata key carrying bookmarks the server must
+// have caught up to before running the query.
+const bookmarksKey = "bookmarks"
+
+// modeKey is the RUN/BEGIN metadata key carrying the access mode.
+const modeKey = "mode"
+
+// AccessMode tells the server (and routing) whether a query only reads.
+type AccessMode int
+
+const (
+	// AccessModeWrite is the default; servers treat a missing mode as write.
+	AccessModeWrite AccessMode = iota
+	// AccessModeRead marks a query as read-only, allowing it on followers
+	// and read replicas.
+	AccessModeRead
+)
+
+// boltMode returns the single-character mode value Bolt expects.
+func (m AccessMode) boltMode() string {
+	if m == AccessModeRead {
+		return "r"
+	}
+	return "w"
+}
+
+// WithAccessMode returns a copy of metaData that runs the query in mode.
+func WithAccessMode(metaData map[string]interface{}, mode AccessMode) map[string]interface{} {
+	out := make(map[string]interface{}, len(metaData)+1)
+	for k, v := range metaData {
+		out[k] = v
+	}
+	out[modeKey] = mode
+	return out
+}
+
+// WithQueryTimeout returns a copy of metaData that asks the server to abort the
+// query after timeout. It overrides Config.QueryTimeout for a single call.
+func WithQueryTimeout(metaData map[string]interface{}, timeout time.Duration) map[string]interface{} {
+	out := make(map[string]interface{}, len(metaData)+1)
+	for k, v := range metaData {
+		out[k] = v
+	}
+	out[txTimeoutKey] = timeout
+	return out
+}
+
+// WithNotificationFilter returns a copy of metaData carrying filter in the
+// transaction metadata. The driver-wide Config.NotificationFilter is applied
+// once per connection in HELLO; this overrides it for a single call.
+func WithNotificationFilter(metaData map[string]interface{}, filter *NotificationFilter) map[string]interface{} {
+	out := make(map[string]interface{}, len(metaData)+2)
+	for k, v := range metaData {
+		out[k] = v
+	}
+	for k, v := range filter.metadata() {
+		out[k] = v
+	}
+	return out
+}
+
+// WithBookmarks returns a copy of metaData that makes the query wait for the
+// given bookmarks, giving a one-off call causal consistency with earlier work.
+func WithBookmarks(metaData map[string]interface{}, bookmarks ...string) map[string]interface{} {
+	out := make(map[string]interface{}, len(metaData)+1)
+	for k, v := range metaData {
+		out[k] = v
+	}
+	out[bookmarksKey] = bookmarks
+	return out
+}
+
+// normalizeBookmarks converts the accepted bookmark shapes (string, []string,
+// []interface{}) into the list of strings Bolt expects.
+func normalizeBookmarks(v interface{}) []interface{} {
+	var out []interface{}
+	switch b := v.(type) {
+	case string:
+		if b != "" {
+			out = append(out, b)
+		}
+	case []string:
+		for _, bookmark := range b {
+			if bookmark != "" {
+				out = append(out, bookmark)
+			}
+		}
+	case []interface{}:
+		for _, bookmark := range b {
+			if s, ok := bookmark.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+// runMetadata builds the metadata sent with RUN (and BEGIN) for a call. The
+// caller's map is never mutated; driver-wide defaults only fill keys the caller
+// left unset.
+func (d *driver) runMetadata(metaData map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(metaData)+1)
+	for k, v := range metaData {
+		out[k] = v
+	}
+
+	if _, exists := out[txTimeoutKey]; !exists && d.config.QueryTimeout > 0 {
+		out[txTimeoutKey] = d.config.QueryTimeout
+	}
+	if timeout, ok := out[txTimeoutKey].(time.Duration); ok {
+		if timeout > 0 {
+			// Bolt expects whole milliseconds; round sub-millisecond values up.
+			out[txTimeoutKey] = int64((timeout + time.Millisecond - 1) / time.Millisecond)
+		} else {
+			delete(out, txTimeoutKey)
+		}
+	}
+
+	if mode, ok := out[modeKey].(AccessMode); ok {
+		out[modeKey] = mode.boltMode()
+	}
+
+	if raw, exists := out[bookmarksKey]; exists {
+		if bookmarks := normalizeBookmarks(raw); len(bookmarks) > 0 {
+			out[bookmarksKey] = bookmarks
+		} else {
+			delete(out, bookmarksKey)
+		}
+	}
+
+	return out
+}
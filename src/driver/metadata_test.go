@@ -0,0 +1,221 @@
+package driver
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/seuros/gopher-cypher/src/bolt/messaging"
+	"github.com/seuros/gopher-cypher/src/internal/boltutil"
+)
+
+func TestRunMetadata_TxTimeout(t *testing.T) {
+	d := &driver{config: DefaultConfig()}
+
+	if _, exists := d.runMetadata(nil)[txTimeoutKey]; exists {
+		t.Error("Expected tx_timeout to be omitted when no timeout is configured")
+	}
+
+	d.config.QueryTimeout = 5 * time.Second
+	run := messaging.NewRun("RETURN 1", nil, d.runMetadata(nil))
+	if got := run.Metadata()[txTimeoutKey]; got != int64(5000) {
+		t.Errorf("Expected RUN tx_timeout 5000, got %v (%T)", got, got)
+	}
+
+	begin := messaging.NewBegin(d.runMetadata(nil))
+	if got := begin.Metadata()[txTimeoutKey]; got != int64(5000) {
+		t.Errorf("Expected BEGIN tx_timeout 5000, got %v (%T)", got, got)
+	}
+
+	caller := map[string]interface{}{"db": "neo4j"}
+	perCall := d.runMetadata(WithQueryTimeout(caller, 250*time.Millisecond))
+	if got := perCall[txTimeoutKey]; got != int64(250) {
+		t.Errorf("Expected per-call tx_timeout 250, got %v", got)
+	}
+	if _, exists := caller[txTimeoutKey]; exists {
+		t.Error("Caller metadata must not be mutated")
+	}
+}
+
+func TestNotificationFilterMetadata(t *testing.T) {
+	var unset *NotificationFilter
+	if len(unset.metadata()) != 0 {
+		t.Errorf("Expected nil filter to emit no fields, got %v", unset.metadata())
+	}
+	if got := (&NotificationFilter{}).metadata(); len(got) != 0 {
+		t.Errorf("Expected empty filter to emit no fields, got %v", got)
+	}
+
+	filter := &NotificationFilter{
+		MinSeverity:        "INFORMATION",
+		DisabledCategories: []string{"HINT", "UNRECOGNIZED"},
+	}
+
+	hello := boltutil.HelloMetadata(filter.metadata())
+	if hello["notifications_minimum_severity"] != "INFORMATION" {
+		t.Errorf("Expected HELLO min severity INFORMATION, got %v", hello["notifications_minimum_severity"])
+	}
+	categories, ok := hello["notifications_disabled_categories"].([]interface{})
+	if !ok || len(categories) != 2 || categories[0] != "HINT" || categories[1] != "UNRECOGNIZED" {
+		t.Errorf("Unexpected HELLO disabled categories: %v", hello["notifications_disabled_categories"])
+	}
+
+	d := &driver{config: DefaultConfig()}
+	begin := messaging.NewBegin(d.runMetadata(WithNotificationFilter(nil, filter)))
+	if begin.Metadata()["notifications_minimum_severity"] != "INFORMATION" {
+		t.Errorf("Expected BEGIN min severity INFORMATION, got %v", begin.Metadata())
+	}
+
+	run := messaging.NewRun("RETURN 1", nil, d.runMetadata(nil))
+	if _, exists := run.Metadata()["notifications_minimum_severity"]; exists {
+		t.Error("Expected RUN metadata to omit notification fields when no per-call filter is set")
+	}
+	if _, exists := boltutil.HelloMetadata(nil)["notifications_minimum_severity"]; exists {
+		t.Error("Expected HELLO metadata to omit notification fields when unset")
+	}
+}
+
+func TestRunWithContext_Bookmarks(t *testing.T) {
+	conn := &boltScriptConn{}
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"fields": []interface{}{"n"}})
+	conn.queue(t, messaging.RecordSignature, []interface{}{1})
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"bookmark": "FB:kcwQ2"})
+
+	d := newScriptedDriver(t, conn)
+	metaData := map[string]interface{}{"bookmarks": []string{"FB:kcwQ1"}}
+	_, _, summary, err := d.RunWithContext(context.Background(), "RETURN 1 AS n", nil, metaData)
+	if err != nil {
+		t.Fatalf("RunWithContext failed: %v", err)
+	}
+
+	sent := conn.sent(t)
+	if len(sent) == 0 || sent[0].Signature() != messaging.RunSignature {
+		t.Fatalf("expected RUN to be sent first, got %v", sent)
+	}
+	runMeta, _ := sent[0].Fields()[2].(map[string]interface{})
+	bookmarks, _ := runMeta["bookmarks"].([]interface{})
+	if len(bookmarks) != 1 || bookmarks[0] != "FB:kcwQ1" {
+		t.Errorf("expected bookmarks [FB:kcwQ1] in RUN metadata, got %v", runMeta["bookmarks"])
+	}
+
+	if summary.Bookmark != "FB:kcwQ2" {
+		t.Errorf("expected returned bookmark FB:kcwQ2, got %q", summary.Bookmark)
+	}
+}
+
+func TestRunWithContext_ServerTimings(t *testing.T) {
+	conn := &boltScriptConn{}
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"fields": []interface{}{"n"}, "t_first": int64(12)})
+	conn.queue(t, messaging.RecordSignature, []interface{}{1})
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"t_last": int64(34)})
+
+	d := newScriptedDriver(t, conn)
+	_, _, summary, err := d.RunWithContext(context.Background(), "RETURN 1 AS n", nil, nil)
+	if err != nil {
+		t.Fatalf("RunWithContext failed: %v", err)
+	}
+	if summary.ResultAvailableAfter != 12*time.Millisecond {
+		t.Errorf("expected ResultAvailableAfter 12ms, got %v", summary.ResultAvailableAfter)
+	}
+	if summary.ResultConsumedAfter != 34*time.Millisecond {
+		t.Errorf("expected ResultConsumedAfter 34ms, got %v", summary.ResultConsumedAfter)
+	}
+}
+
+func TestStreamingResult_ServerTimings(t *testing.T) {
+	conn := &boltScriptConn{}
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"fields": []interface{}{"n"}, "result_available_after": int64(5)})
+	conn.queue(t, messaging.RecordSignature, []interface{}{1})
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"result_consumed_after": int64(7)})
+
+	stream, _ := newScriptedStream(t, conn)
+	if err := stream.sendRun(context.Background()); err != nil {
+		t.Fatalf("sendRun failed: %v", err)
+	}
+
+	result := NewStreamingResult(stream, stream.query, nil)
+	ctx := context.Background()
+	for result.Next(ctx) {
+	}
+	summary, err := result.Consume(ctx)
+	if err != nil {
+		t.Fatalf("Consume failed: %v", err)
+	}
+	if summary.ResultAvailableAfter != 5*time.Millisecond {
+		t.Errorf("expected ResultAvailableAfter 5ms, got %v", summary.ResultAvailableAfter)
+	}
+	if summary.ResultConsumedAfter != 7*time.Millisecond {
+		t.Errorf("expected ResultConsumedAfter 7ms, got %v", summary.ResultConsumedAfter)
+	}
+}
+
+func TestRunMetadata_Bookmarks(t *testing.T) {
+	d := &driver{config: DefaultConfig()}
+
+	tests := []struct {
+		name string
+		in   interface{}
+		want []interface{}
+	}{
+		{"string", "bm1", []interface{}{"bm1"}},
+		{"string slice", []string{"bm1", "", "bm2"}, []interface{}{"bm1", "bm2"}},
+		{"interface slice", []interface{}{"bm1", 3}, []interface{}{"bm1"}},
+		{"empty", []string{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := d.runMetadata(map[string]interface{}{"bookmarks": tt.in})
+			got, exists := out["bookmarks"]
+			if tt.want == nil {
+				if exists {
+					t.Errorf("expected bookmarks to be omitted, got %v", got)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	withBookmarks := WithBookmarks(nil, "bm3")
+	if got := d.runMetadata(withBookmarks)["bookmarks"]; !reflect.DeepEqual(got, []interface{}{"bm3"}) {
+		t.Errorf("expected WithBookmarks to produce [bm3], got %v", got)
+	}
+}
+
+func TestRunWithContext_AccessMode(t *testing.T) {
+	conn := &boltScriptConn{}
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"fields": []interface{}{"n"}})
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{})
+
+	d := newScriptedDriver(t, conn)
+	metaData := WithAccessMode(nil, AccessModeRead)
+	if _, _, _, err := d.RunWithContext(context.Background(), "MATCH (n) RETURN n", nil, metaData); err != nil {
+		t.Fatalf("RunWithContext failed: %v", err)
+	}
+
+	sent := conn.sent(t)
+	if len(sent) == 0 || sent[0].Signature() != messaging.RunSignature {
+		t.Fatalf("expected RUN to be sent first, got %v", sent)
+	}
+	runMeta, _ := sent[0].Fields()[2].(map[string]interface{})
+	if runMeta["mode"] != "r" {
+		t.Errorf("expected mode r in RUN metadata, got %v", runMeta["mode"])
+	}
+	if metaData["mode"] != AccessModeRead {
+		t.Error("caller metadata must not be mutated")
+	}
+}
+
+func TestRunMetadata_AccessMode(t *testing.T) {
+	d := &driver{config: DefaultConfig()}
+
+	if got := d.runMetadata(WithAccessMode(nil, AccessModeWrite))["mode"]; got != "w" {
+		t.Errorf("expected explicit write mode w, got %v", got)
+	}
+	if _, exists := d.runMetadata(nil)["mode"]; exists {
+		t.Error("expected no mode when unset, leaving the server default of write")
+	}
+}
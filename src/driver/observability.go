@@ -2,7 +2,6 @@ package driver
 
 import (
 	"context"
-	"strings"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -31,6 +30,10 @@ type ObservabilityConfig struct {
 
 	// MetricAttributes are additional attributes to add to all metrics
 	MetricAttributes []attribute.KeyValue
+
+	// RecordCountBuckets are the bucket boundaries of the
+	// db.query.record_count histogram. Nil uses the SDK defaults.
+	RecordCountBuckets []float64
 }
 
 // DefaultObservabilityConfig returns default observability configuration
@@ -62,14 +65,21 @@ type observabilityInstruments struct {
 	connectionErrors     metric.Int64Counter
 	queryErrors          metric.Int64Counter
 	recordsReturned      metric.Int64Counter
+	recordCount          metric.Int64Histogram
 	authenticationsCount metric.Int64Counter
 }
 
-// initObservability initializes OpenTelemetry instruments
-func initObservability() *observabilityInstruments {
+// initObservability initializes OpenTelemetry instruments from the global
+// providers.
+func initObservability(config *ObservabilityConfig) *observabilityInstruments {
 	tracer := otel.Tracer(instrumentationName, trace.WithInstrumentationVersion(instrumentationVersion))
 	meter := otel.Meter(instrumentationName, metric.WithInstrumentationVersion(instrumentationVersion))
+	return newObservabilityInstruments(tracer, meter, config)
+}
 
+// newObservabilityInstruments creates the instruments on the given tracer
+// and meter.
+func newObservabilityInstruments(tracer trace.Tracer, meter metric.Meter, config *ObservabilityConfig) *observabilityInstruments {
 	instruments := &observabilityInstruments{
 		tracer: tracer,
 		meter:  meter,
@@ -127,6 +137,17 @@ func initObservability() *observabilityInstruments {
 		otel.Handle(err)
 	}
 
+	recordCountOpts := []metric.Int64HistogramOption{
+		metric.WithDescription("Number of records returned per query"),
+	}
+	if config != nil && len(config.RecordCountBuckets) > 0 {
+		recordCountOpts = append(recordCountOpts, metric.WithExplicitBucketBoundaries(config.RecordCountBuckets...))
+	}
+	instruments.recordCount, err = meter.Int64Histogram("db.query.record_count", recordCountOpts...)
+	if err != nil {
+		otel.Handle(err)
+	}
+
 	instruments.authenticationsCount, err = meter.Int64Counter(
 		"db.authentication.count",
 		metric.WithDescription("Number of authentication attempts"),
@@ -145,6 +166,13 @@ type ResultSummary struct {
 	Parameters    map[string]interface{}
 	ExecutionTime time.Duration
 
+	// Server-reported timings: how long until the first record was available
+	// (t_first) and how long the server spent streaming the result (t_last).
+	// The first is planning time, the second streaming time; both are zero
+	// when the server does not report them.
+	ResultAvailableAfter time.Duration
+	ResultConsumedAfter  time.Duration
+
 	// Result metrics
 	RecordsAvailable int64
 	RecordsConsumed  int64
@@ -276,6 +304,7 @@ func (oi *observabilityInstruments) finishQuerySpan(spanCtx *spanContext, summar
 			if summary.RecordsConsumed > 0 {
 				oi.recordsReturned.Add(context.Background(), summary.RecordsConsumed, attrs)
 			}
+			oi.recordCount.Record(context.Background(), summary.RecordsConsumed, metric.WithAttributes(append(config.MetricAttributes, queryTypeAttr)...))
 		}
 	}
 
@@ -333,25 +362,3 @@ func (oi *observabilityInstruments) recordConnectionEvent(eventType string, conf
 		oi.authenticationsCount.Add(context.Background(), 1, metric.WithAttributes(append(config.MetricAttributes, statusAttr)...))
 	}
 }
-
-// inferQueryType attempts to determine the type of query from its text
-func inferQueryType(query string) string {
-	// Simple heuristic - in practice, this could be more sophisticated
-	queryUpper := strings.ToUpper(query)
-
-	// Check for schema operations first (more specific)
-	switch {
-	case strings.Contains(queryUpper, "CREATE INDEX"), strings.Contains(queryUpper, "DROP INDEX"),
-		strings.Contains(queryUpper, "CREATE CONSTRAINT"), strings.Contains(queryUpper, "DROP CONSTRAINT"):
-		return "SCHEMA_WRITE"
-	case strings.Contains(queryUpper, "CREATE"), strings.Contains(queryUpper, "MERGE"),
-		strings.Contains(queryUpper, "SET"), strings.Contains(queryUpper, "DELETE"),
-		strings.Contains(queryUpper, "REMOVE"):
-		return "WRITE"
-	case strings.Contains(queryUpper, "MATCH"), strings.Contains(queryUpper, "RETURN"),
-		strings.Contains(queryUpper, "WITH"):
-		return "READ"
-	default:
-		return "UNKNOWN"
-	}
-}
@@ -2,10 +2,14 @@ package driver
 
 import (
 	"context"
+	"reflect"
 	"testing"
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
 )
 
 func TestDefaultObservabilityConfig(t *testing.T) {
@@ -39,7 +43,7 @@ func TestDefaultObservabilityConfig(t *testing.T) {
 }
 
 func TestObservabilityInstrumentation(t *testing.T) {
-	instruments := initObservability()
+	instruments := initObservability(DefaultObservabilityConfig())
 
 	if instruments.tracer == nil {
 		t.Error("Tracer should be initialized")
@@ -71,6 +75,42 @@ func TestInferQueryType(t *testing.T) {
 		{"RETURN 1", "READ"},
 		{"WITH 1 as x RETURN x", "READ"},
 		{"UNKNOWN QUERY", "UNKNOWN"},
+
+		// Procedures are classified by the catalog; unknown ones count as writes.
+		{"CALL db.labels()", "READ"},
+		{"CALL db.labels() YIELD label RETURN label", "READ"},
+		{"call db.index.fulltext.queryNodes('idx', 'x') YIELD node RETURN node", "READ"},
+		{"CALL dbms.components()", "READ"},
+		{"CALL dbms.security.createUser('bob', 'secret', false)", "WRITE"},
+		{"CALL dbms.setConfigValue('db.logs.query.enabled', 'off')", "WRITE"},
+		{"CALL dbms.killQuery('query-1')", "WRITE"},
+		{"CALL db.createLabel('Person')", "WRITE"},
+		{"CALL apoc.create.node(['A'], {}) YIELD node RETURN node", "WRITE"},
+		{"CALL db.index.fulltext.createNodeIndex('idx', ['A'], ['name'])", "SCHEMA_WRITE"},
+		{"CALL custom.proc()", "WRITE"},
+		{"MATCH (n) CALL { WITH n RETURN n.x AS x } RETURN x", "READ"},
+
+		// SHOW commands only read.
+		{"SHOW DATABASES", "READ"},
+		{"USE system SHOW DATABASES YIELD name, currentStatus", "READ"},
+		{"USE `my-db` SHOW CONSTRAINTS", "READ"},
+		{"SHOW INDEXES", "READ"},
+		{"show constraints YIELD name WHERE name CONTAINS 'create'", "READ"},
+		{"SHOW PROCEDURES YIELD name, mode", "READ"},
+
+		// Writes win over the surrounding reads.
+		{"MATCH (n:Person) SET n.seen = true RETURN n", "WRITE"},
+		{"MATCH (n) WITH n LIMIT 10 DETACH DELETE n", "WRITE"},
+		{"MATCH (a), (b) MERGE (a)-[:KNOWS]->(b)", "WRITE"},
+		{"CREATE TEXT INDEX person_name FOR (n:Person) ON (n.name)", "SCHEMA_WRITE"},
+		{"DROP CONSTRAINT person_id IF EXISTS", "SCHEMA_WRITE"},
+
+		// Keywords in literals, comments, properties and labels don't count.
+		{"MATCH (n {status: 'CREATE'}) RETURN n", "READ"},
+		{"MATCH (n) RETURN n.set, n.created // DELETE later", "READ"},
+		{"MATCH (n:Delete) RETURN n SKIP 1 /* SET */", "READ"},
+		{"MATCH (n) RETURN {set: 1} AS m", "READ"},
+		{"MATCH (n) RETURN n ORDER BY n.name OFFSET 5", "READ"},
 	}
 
 	for _, tt := range tests {
@@ -172,7 +212,7 @@ func TestObservabilityDisabled(t *testing.T) {
 }
 
 func TestSpanContextHandling(t *testing.T) {
-	instruments := initObservability()
+	instruments := initObservability(DefaultObservabilityConfig())
 	config := DefaultObservabilityConfig()
 
 	ctx := context.Background()
@@ -200,3 +240,47 @@ func TestSpanContextHandling(t *testing.T) {
 	// This should not panic
 	instruments.finishQuerySpan(spanCtx, summary, nil, config)
 }
+
+// recordingMeter is a no-op meter that keeps the Int64 histograms it
+// creates, so tests can inspect their configuration and recorded values.
+type recordingMeter struct {
+	noop.Meter
+	histograms map[string]*recordingHistogram
+}
+
+type recordingHistogram struct {
+	noop.Int64Histogram
+	config metric.Int64HistogramConfig
+	values []int64
+}
+
+func (m *recordingMeter) Int64Histogram(name string, opts ...metric.Int64HistogramOption) (metric.Int64Histogram, error) {
+	h := &recordingHistogram{config: metric.NewInt64HistogramConfig(opts...)}
+	m.histograms[name] = h
+	return h, nil
+}
+
+func (h *recordingHistogram) Record(_ context.Context, value int64, _ ...metric.RecordOption) {
+	h.values = append(h.values, value)
+}
+
+func TestRecordCountHistogram(t *testing.T) {
+	config := DefaultObservabilityConfig()
+	config.RecordCountBuckets = []float64{0, 1, 10, 100}
+	meter := &recordingMeter{histograms: make(map[string]*recordingHistogram)}
+	instruments := newObservabilityInstruments(tracenoop.NewTracerProvider().Tracer("test"), meter, config)
+
+	h, ok := meter.histograms["db.query.record_count"]
+	if !ok {
+		t.Fatal("expected the db.query.record_count histogram to be created")
+	}
+	if got := h.config.ExplicitBucketBoundaries(); !reflect.DeepEqual(got, config.RecordCountBuckets) {
+		t.Errorf("expected buckets %v, got %v", config.RecordCountBuckets, got)
+	}
+
+	_, spanCtx := instruments.startQuerySpan(context.Background(), "MATCH (n) RETURN n", nil, config)
+	instruments.finishQuerySpan(spanCtx, &ResultSummary{QueryType: "READ", RecordsConsumed: 42}, nil, config)
+	if !reflect.DeepEqual(h.values, []int64{42}) {
+		t.Errorf("expected one record of 42, got %v", h.values)
+	}
+}
@@ -1,11 +1,11 @@
 package driver
 
 func (d *driver) Ping() error {
-	d.logger.Debug("Starting ping to server")
+	logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryConnection, "Starting ping to server")
 
 	conn, err := d.netPool.Get()
 	if err != nil {
-		d.logger.Error("Ping failed: unable to get connection", "error", err)
+		logEvent(d.logger, d.config.Logging, LogLevelError, LogCategoryConnection, "Ping failed: unable to get connection", "error", err)
 		return err
 	}
 	defer func() {
@@ -15,10 +15,10 @@ func (d *driver) Ping() error {
 	// Use ensureAuthenticated for consistent connection handling
 	_, err = d.ensureAuthenticated(conn)
 	if err != nil {
-		d.logger.Error("Ping failed", "error", err)
+		logEvent(d.logger, d.config.Logging, LogLevelError, LogCategoryConnection, "Ping failed", "error", err)
 		return err
 	}
 
-	d.logger.Debug("Ping successful")
+	logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryConnection, "Ping successful")
 	return nil
 }
@@ -0,0 +1,198 @@
+package driver
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// queryPlanJSON is the wire shape of QueryPlan. The field names follow the
+// keys Neo4j uses in the plan metadata.
+type queryPlanJSON struct {
+	OperatorType string                 `json:"operatorType"`
+	Identifiers  []string               `json:"identifiers"`
+	Arguments    map[string]interface{} `json:"arguments"`
+	Children     []*QueryPlan           `json:"children"`
+}
+
+// MarshalJSON encodes the plan tree. Argument values come straight from the
+// Bolt decoder, so they are normalized first (non-finite floats, structures,
+// non-string map keys) to guarantee valid JSON.
+func (p *QueryPlan) MarshalJSON() ([]byte, error) {
+	return json.Marshal(queryPlanJSON{
+		OperatorType: p.OperatorType,
+		Identifiers:  nonNilStrings(p.Identifiers),
+		Arguments:    jsonSafeMap(p.Arguments),
+		Children:     nonNilPlans(p.Children),
+	})
+}
+
+type queryProfileJSON struct {
+	OperatorType string                 `json:"operatorType"`
+	Identifiers  []string               `json:"identifiers"`
+	Arguments    map[string]interface{} `json:"arguments"`
+	DbHits       int64                  `json:"dbHits"`
+	Rows         int64                  `json:"rows"`
+	Time         int64                  `json:"time"`
+	Children     []*QueryProfile        `json:"children"`
+}
+
+// MarshalJSON encodes the profile tree; Time is reported in nanoseconds as
+// Neo4j does.
+func (p *QueryProfile) MarshalJSON() ([]byte, error) {
+	children := p.Children
+	if children == nil {
+		children = []*QueryProfile{}
+	}
+	return json.Marshal(queryProfileJSON{
+		OperatorType: p.OperatorType,
+		Identifiers:  nonNilStrings(p.Identifiers),
+		Arguments:    jsonSafeMap(p.Arguments),
+		DbHits:       p.DbHits,
+		Rows:         p.Rows,
+		Time:         p.Time.Nanoseconds(),
+		Children:     children,
+	})
+}
+
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+func nonNilPlans(p []*QueryPlan) []*QueryPlan {
+	if p == nil {
+		return []*QueryPlan{}
+	}
+	return p
+}
+
+func jsonSafeMap(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = jsonSafeValue(v)
+	}
+	return out
+}
+
+// jsonSafeValue converts decoded Bolt values into something encoding/json
+// accepts without error.
+func jsonSafeValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case nil, string, bool, int64, int, byte:
+		return x
+	case float64:
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return fmt.Sprint(x)
+		}
+		return x
+	case map[string]interface{}:
+		return jsonSafeMap(x)
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(x))
+		for k, vv := range x {
+			out[fmt.Sprint(k)] = jsonSafeValue(vv)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(x))
+		for i, vv := range x {
+			out[i] = jsonSafeValue(vv)
+		}
+		return out
+	default:
+		if _, err := json.Marshal(x); err == nil {
+			return x
+		}
+		return fmt.Sprint(x)
+	}
+}
+
+// parseQueryPlan decodes the "plan" entry of a SUCCESS metadata map.
+func parseQueryPlan(raw interface{}) *QueryPlan {
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	plan := &QueryPlan{
+		OperatorType: stringFromMeta(m, "operatorType"),
+		Identifiers:  stringsFromMeta(m, "identifiers"),
+		Arguments:    mapFromMeta(m, "args"),
+	}
+	if children, ok := m["children"].([]interface{}); ok {
+		for _, child := range children {
+			if parsed := parseQueryPlan(child); parsed != nil {
+				plan.Children = append(plan.Children, parsed)
+			}
+		}
+	}
+	return plan
+}
+
+// parseQueryProfile decodes the "profile" entry of a SUCCESS metadata map.
+func parseQueryProfile(raw interface{}) *QueryProfile {
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	profile := &QueryProfile{
+		OperatorType: stringFromMeta(m, "operatorType"),
+		Identifiers:  stringsFromMeta(m, "identifiers"),
+		Arguments:    mapFromMeta(m, "args"),
+		DbHits:       int64FromMeta(m, "dbHits"),
+		Rows:         int64FromMeta(m, "rows"),
+		Time:         time.Duration(int64FromMeta(m, "time")),
+	}
+	if children, ok := m["children"].([]interface{}); ok {
+		for _, child := range children {
+			if parsed := parseQueryProfile(child); parsed != nil {
+				profile.Children = append(profile.Children, parsed)
+			}
+		}
+	}
+	return profile
+}
+
+func stringFromMeta(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+func stringsFromMeta(m map[string]interface{}, key string) []string {
+	list, ok := m[key].([]interface{})
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func mapFromMeta(m map[string]interface{}, key string) map[string]interface{} {
+	args, ok := m[key].(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}
+	}
+	return args
+}
+
+func int64FromMeta(m map[string]interface{}, key string) int64 {
+	switch v := m[key].(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
@@ -0,0 +1,89 @@
+package driver
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestQueryPlan_MarshalJSON(t *testing.T) {
+	plan := &QueryPlan{
+		OperatorType: "ProduceResults@neo4j",
+		Identifiers:  []string{"n"},
+		Arguments: map[string]interface{}{
+			"EstimatedRows": 10.0,
+			"Weird":         math.Inf(1),
+			"Nested":        []interface{}{map[string]interface{}{"k": int64(1)}},
+		},
+		Children: []*QueryPlan{
+			{OperatorType: "AllNodesScan@neo4j", Identifiers: []string{"n"}},
+		},
+	}
+
+	data, err := json.Marshal(plan)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !json.Valid(data) {
+		t.Fatalf("Invalid JSON: %s", data)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded["operatorType"] != "ProduceResults@neo4j" {
+		t.Errorf("Unexpected operatorType: %v", decoded["operatorType"])
+	}
+	children, ok := decoded["children"].([]interface{})
+	if !ok || len(children) != 1 {
+		t.Fatalf("Expected one child, got %v", decoded["children"])
+	}
+	child := children[0].(map[string]interface{})
+	if child["operatorType"] != "AllNodesScan@neo4j" {
+		t.Errorf("Unexpected child operatorType: %v", child["operatorType"])
+	}
+	if args := decoded["arguments"].(map[string]interface{}); args["Weird"] != "+Inf" {
+		t.Errorf("Expected non-finite float to be stringified, got %v", args["Weird"])
+	}
+}
+
+func TestQueryProfile_MarshalJSON(t *testing.T) {
+	profile := &QueryProfile{OperatorType: "Filter", DbHits: 4, Rows: 2, Time: 1500 * time.Nanosecond}
+
+	data, err := json.Marshal(profile)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded["dbHits"] != 4.0 || decoded["rows"] != 2.0 || decoded["time"] != 1500.0 {
+		t.Errorf("Unexpected profile counters: %v", decoded)
+	}
+}
+
+func TestParseQueryPlan(t *testing.T) {
+	raw := map[string]interface{}{
+		"operatorType": "ProduceResults",
+		"identifiers":  []interface{}{"n"},
+		"args":         map[string]interface{}{"Details": "n"},
+		"children": []interface{}{
+			map[string]interface{}{"operatorType": "AllNodesScan", "identifiers": []interface{}{"n"}},
+		},
+	}
+
+	plan := parseQueryPlan(raw)
+	if plan == nil || plan.OperatorType != "ProduceResults" || len(plan.Children) != 1 {
+		t.Fatalf("Unexpected plan: %+v", plan)
+	}
+	if plan.Children[0].OperatorType != "AllNodesScan" {
+		t.Errorf("Unexpected child: %+v", plan.Children[0])
+	}
+	if plan.Arguments["Details"] != "n" {
+		t.Errorf("Unexpected arguments: %v", plan.Arguments)
+	}
+}
@@ -0,0 +1,86 @@
+package driver
+
+import (
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/yudhasubki/netpool"
+)
+
+// PoolStats is a snapshot of the connection pool, as returned by
+// Driver.PoolStats.
+type PoolStats struct {
+	// InUse is the number of connections currently handed out.
+	InUse int
+	// Idle is the number of open connections waiting in the pool.
+	Idle int
+	// Max is the upper bound on open connections.
+	Max int
+	// WaitCount is the number of acquisitions that found the pool
+	// exhausted and had to wait for a connection to be returned.
+	WaitCount int64
+	// WaitDuration is the total time those acquisitions spent waiting.
+	WaitDuration time.Duration
+}
+
+// defaultPoolMax is netpool's own limit, used when no ConnectionPool config
+// sets one.
+const defaultPoolMax = 15
+
+// connPool wraps netpool.Netpool, which only exposes its idle count, to
+// track the figures PoolStats reports.
+type connPool struct {
+	*netpool.Netpool
+	max          int
+	inUse        atomic.Int64
+	waitCount    atomic.Int64
+	waitDuration atomic.Int64
+}
+
+func newConnPool(pool *netpool.Netpool, max int) *connPool {
+	return &connPool{Netpool: pool, max: max}
+}
+
+// Get takes a connection from the pool, blocking while the pool is full.
+func (p *connPool) Get() (net.Conn, error) {
+	// netpool blocks only when nothing is idle and the limit is reached.
+	exhausted := p.Len() == 0 && int(p.inUse.Load()) >= p.max
+	start := time.Now()
+
+	conn, err := p.Netpool.Get()
+	if exhausted {
+		p.waitCount.Add(1)
+		p.waitDuration.Add(int64(time.Since(start)))
+	}
+	if err == nil {
+		p.inUse.Add(1)
+	}
+	return conn, err
+}
+
+// Put returns conn to the pool, or closes it when err is non-nil.
+func (p *connPool) Put(conn net.Conn, err error) {
+	if conn != nil {
+		p.inUse.Add(-1)
+	}
+	p.Netpool.Put(conn, err)
+}
+
+func (p *connPool) stats() PoolStats {
+	return PoolStats{
+		InUse:        int(p.inUse.Load()),
+		Idle:         p.Len(),
+		Max:          p.max,
+		WaitCount:    p.waitCount.Load(),
+		WaitDuration: time.Duration(p.waitDuration.Load()),
+	}
+}
+
+// PoolStats returns a snapshot of the connection pool.
+func (d *driver) PoolStats() PoolStats {
+	if d.netPool == nil {
+		return PoolStats{}
+	}
+	return d.netPool.stats()
+}
@@ -0,0 +1,78 @@
+package driver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/seuros/gopher-cypher/src/bolt/messaging"
+	"github.com/yudhasubki/netpool"
+)
+
+func TestConnPool_Stats(t *testing.T) {
+	np, err := netpool.New(func() (net.Conn, error) {
+		return newPooledConn(&mockConn{}), nil
+	}, netpool.WithMinPool(0), netpool.WithMaxPool(2))
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	pool := newConnPool(np, 2)
+
+	assertStats := func(want PoolStats) {
+		t.Helper()
+		got := pool.stats()
+		got.WaitDuration = 0
+		if got != want {
+			t.Errorf("stats = %+v, want %+v", got, want)
+		}
+	}
+
+	a, _ := pool.Get()
+	b, _ := pool.Get()
+	assertStats(PoolStats{InUse: 2, Idle: 0, Max: 2})
+
+	pool.Put(a, nil)
+	assertStats(PoolStats{InUse: 1, Idle: 1, Max: 2})
+
+	a, _ = pool.Get()
+	assertStats(PoolStats{InUse: 2, Idle: 0, Max: 2})
+
+	// The pool is exhausted, so a third acquisition waits for a release.
+	got := make(chan net.Conn)
+	go func() {
+		c, _ := pool.Get()
+		got <- c
+	}()
+	time.Sleep(20 * time.Millisecond)
+	pool.Put(b, nil)
+	c := <-got
+
+	stats := pool.stats()
+	if stats.WaitCount != 1 || stats.WaitDuration < 10*time.Millisecond {
+		t.Errorf("expected one wait of at least 10ms, got %d over %v", stats.WaitCount, stats.WaitDuration)
+	}
+
+	pool.Put(a, nil)
+	pool.Put(c, net.ErrClosed) // discarded, not returned
+	assertStats(PoolStats{InUse: 0, Idle: 1, Max: 2, WaitCount: 1})
+}
+
+func TestDriver_PoolStats(t *testing.T) {
+	conn := &boltScriptConn{}
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"fields": []interface{}{"n"}})
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{})
+
+	d := newScriptedDriver(t, conn)
+	if _, _, _, err := d.RunWithContext(context.Background(), "RETURN 1 AS n", nil, nil); err != nil {
+		t.Fatalf("RunWithContext failed: %v", err)
+	}
+
+	stats := d.PoolStats()
+	if stats.InUse != 0 || stats.Idle != 1 || stats.Max != 1 {
+		t.Errorf("expected the connection back in the pool, got %+v", stats)
+	}
+	if (&driver{}).PoolStats() != (PoolStats{}) {
+		t.Error("expected zero stats without a pool")
+	}
+}
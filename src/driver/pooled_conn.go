@@ -1,6 +1,7 @@
 package driver
 
 import (
+	"io"
 	"net"
 	"sync"
 	"time"
@@ -16,6 +17,7 @@ type pooledConn struct {
 	boltVersion   [2]byte // [major, minor]
 	createdAt     time.Time
 	lastUsedAt    time.Time
+	lastNoopAt    time.Time
 }
 
 // newPooledConn wraps a raw connection with state tracking.
@@ -30,7 +32,10 @@ func newPooledConn(conn net.Conn) *pooledConn {
 // isAlive checks if the connection is still responsive by attempting
 // a non-blocking read with a very short deadline. A timeout indicates
 // the connection is alive (no data pending), while EOF or other errors
-// indicate a dead connection.
+// indicate a dead connection. Pending NOOP chunks (0x00 0x00) sent by the
+// server are consumed whole so the Bolt framing stays intact; any other
+// unsolicited data means the stream can't be trusted and the connection is
+// reported dead.
 func (pc *pooledConn) isAlive() bool {
 	pc.mu.Lock()
 	defer pc.mu.Unlock()
@@ -41,18 +46,32 @@ func (pc *pooledConn) isAlive() bool {
 	}
 	defer func() { _ = pc.SetReadDeadline(time.Time{}) }()
 
-	// Try to read one byte - timeout means alive, EOF/error means dead
-	one := make([]byte, 1)
-	_, err := pc.Read(one)
-	if err != nil {
-		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
-			return true // Timeout means connection is alive, just no data pending
+	for {
+		// Try to read one chunk header - timeout means alive, EOF/error means dead
+		header := make([]byte, 2)
+		n, err := pc.Read(header)
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				return true // Timeout means connection is alive, just no data pending
+			}
+			return false // EOF, broken pipe, connection reset, etc.
+		}
+		if n == 1 {
+			// The second half of the header should follow immediately.
+			if err := pc.SetReadDeadline(time.Now().Add(100 * time.Millisecond)); err != nil {
+				return false
+			}
+			if _, err := io.ReadFull(pc.Conn, header[1:]); err != nil {
+				return false
+			}
+			if err := pc.SetReadDeadline(time.Now().Add(1 * time.Millisecond)); err != nil {
+				return false
+			}
+		}
+		if header[0] != 0x00 || header[1] != 0x00 {
+			return false
 		}
-		return false // EOF, broken pipe, connection reset, etc.
 	}
-	// Got data unexpectedly - this shouldn't happen in normal operation
-	// but the connection is technically alive
-	return true
 }
 
 // markAuthenticated records successful Bolt authentication and version.
@@ -141,3 +160,33 @@ func (pc *pooledConn) markDirty() {
 
 	pc.authenticated = false
 }
+
+// sendNoop writes an empty Bolt chunk if the connection has been quiet for at
+// least interval. NOOPs keep intermediaries from dropping the TCP connection
+// but do not count as use, so they don't postpone re-authentication.
+func (pc *pooledConn) sendNoop(interval time.Duration) error {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	quietSince := pc.lastUsedAt
+	if quietSince.IsZero() {
+		quietSince = pc.createdAt
+	}
+	if pc.lastNoopAt.After(quietSince) {
+		quietSince = pc.lastNoopAt
+	}
+	if time.Since(quietSince) < interval {
+		return nil
+	}
+
+	if err := pc.SetWriteDeadline(time.Now().Add(interval)); err != nil {
+		return err
+	}
+	defer func() { _ = pc.SetWriteDeadline(time.Time{}) }()
+
+	if _, err := pc.Write([]byte{0x00, 0x00}); err != nil {
+		return err
+	}
+	pc.lastNoopAt = time.Now()
+	return nil
+}
@@ -107,6 +107,31 @@ func TestPooledConnIsAlive_EOF(t *testing.T) {
 	}
 }
 
+func TestPooledConnIsAlive_ConsumesNoopChunks(t *testing.T) {
+	// Server keep-alive NOOPs are pending: they must be drained, not misread
+	conn := &boltScriptConn{mockConn: mockConn{readTimeout: true}}
+	conn.in.Write([]byte{0x00, 0x00, 0x00, 0x00})
+	pc := newPooledConn(conn)
+
+	if !pc.isAlive() {
+		t.Error("connection with pending NOOP chunks should be considered alive")
+	}
+	if conn.in.Len() != 0 {
+		t.Errorf("expected NOOP chunks to be consumed, %d bytes left", conn.in.Len())
+	}
+}
+
+func TestPooledConnIsAlive_UnexpectedData(t *testing.T) {
+	// Unsolicited non-NOOP data leaves the framing unknown
+	conn := &boltScriptConn{mockConn: mockConn{readTimeout: true}}
+	conn.in.Write([]byte{0x00, 0x03, 0xB1, 0x70, 0xA0})
+	pc := newPooledConn(conn)
+
+	if pc.isAlive() {
+		t.Error("connection with unexpected pending data should be considered dead")
+	}
+}
+
 func TestPooledConnIsAlive_DeadlineError(t *testing.T) {
 	// Can't set deadline = connection is broken
 	mock := &mockConn{deadlineErr: net.ErrClosed}
@@ -0,0 +1,222 @@
+package driver
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Query types reported by InferQueryType, from least to most demanding.
+const (
+	queryTypeUnknown     = "UNKNOWN"
+	queryTypeRead        = "READ"
+	queryTypeWrite       = "WRITE"
+	queryTypeSchemaWrite = "SCHEMA_WRITE"
+)
+
+var queryTypeRank = map[string]int{
+	queryTypeUnknown:     0,
+	queryTypeRead:        1,
+	queryTypeWrite:       2,
+	queryTypeSchemaWrite: 3,
+}
+
+// procedureTypes classifies well-known procedures by exact (lower-cased)
+// name; procedurePrefixes covers whole namespaces.
+var procedureTypes = map[string]string{
+	"db.labels":                    queryTypeRead,
+	"db.relationshiptypes":         queryTypeRead,
+	"db.propertykeys":              queryTypeRead,
+	"db.schema.visualization":      queryTypeRead,
+	"db.schema.nodetypeproperties": queryTypeRead,
+	"db.schema.reltypeproperties":  queryTypeRead,
+	"db.indexes":                   queryTypeRead,
+	"db.constraints":               queryTypeRead,
+	"db.info":                      queryTypeRead,
+	"db.ping":                      queryTypeRead,
+	"db.awaitindex":                queryTypeRead,
+	"db.awaitindexes":              queryTypeRead,
+	"db.index.fulltext.listavailableanalyzers": queryTypeRead,
+	"db.createlabel":                            queryTypeWrite,
+	"db.createproperty":                         queryTypeWrite,
+	"db.createrelationshiptype":                 queryTypeWrite,
+	"db.index.fulltext.createnodeindex":         queryTypeSchemaWrite,
+	"db.index.fulltext.createrelationshipindex": queryTypeSchemaWrite,
+	"db.index.fulltext.drop":                    queryTypeSchemaWrite,
+	"db.index.vector.createnodeindex":           queryTypeSchemaWrite,
+	"db.create.setnodevectorproperty":           queryTypeWrite,
+	// dbms.* also holds user management and configuration changes, so only
+	// the procedures known to read are listed; the rest count as writes.
+	"dbms.components":      queryTypeRead,
+	"dbms.procedures":      queryTypeRead,
+	"dbms.functions":       queryTypeRead,
+	"dbms.info":            queryTypeRead,
+	"dbms.listconfig":      queryTypeRead,
+	"dbms.showcurrentuser": queryTypeRead,
+	"dbms.queryjmx":        queryTypeRead,
+}
+
+var procedurePrefixes = []struct {
+	prefix    string
+	queryType string
+}{
+	{"db.index.fulltext.query", queryTypeRead},
+	{"db.index.vector.query", queryTypeRead},
+	{"apoc.meta.", queryTypeRead},
+	{"apoc.create.", queryTypeWrite},
+	{"apoc.merge.", queryTypeWrite},
+	{"apoc.refactor.", queryTypeWrite},
+	{"apoc.periodic.", queryTypeWrite},
+}
+
+var procedureCallPattern = regexp.MustCompile(`(?i)\bCALL\s+([A-Za-z_][\w.]*)`)
+
+// showCommandPattern matches a SHOW command (SHOW DATABASES, SHOW INDEXES,
+// ...), optionally preceded by the USE clause that typically targets the
+// system database. It runs on stripped text, where a quoted name keeps only
+// its backticks.
+var showCommandPattern = regexp.MustCompile("(?i)^\\s*(?:USE\\s+(?:`[^`]*`|[\\w.]+)\\s+)?SHOW\\b")
+
+// InferQueryType classifies a query as READ, WRITE, SCHEMA_WRITE or UNKNOWN
+// using the same heuristic the driver applies to span and metric attributes.
+func InferQueryType(query string) string {
+	return inferQueryType(query)
+}
+
+// inferQueryType classifies a query from its clause keywords and the
+// procedures it calls, reporting the most demanding type found: a MATCH that
+// also SETs is a WRITE. Keywords inside string literals, comments, property
+// keys, labels and parameters are ignored. Procedures missing from the
+// catalog are assumed to write, since a writer can serve a read but not the
+// other way round. Administrative SHOW commands only read.
+func inferQueryType(query string) string {
+	text := stripLiteralsAndComments(query)
+	if showCommandPattern.MatchString(text) {
+		return queryTypeRead
+	}
+	keywords := queryKeywords(text)
+
+	result := queryTypeUnknown
+	raise := func(t string) {
+		if queryTypeRank[t] > queryTypeRank[result] {
+			result = t
+		}
+	}
+
+	for i, kw := range keywords {
+		switch kw {
+		case "CREATE", "DROP":
+			if isSchemaCommand(keywords[i+1:]) {
+				raise(queryTypeSchemaWrite)
+			} else if kw == "CREATE" {
+				raise(queryTypeWrite)
+			}
+		case "MERGE", "SET", "DELETE", "REMOVE":
+			raise(queryTypeWrite)
+		case "MATCH", "RETURN", "WITH", "UNWIND":
+			raise(queryTypeRead)
+		}
+	}
+
+	for _, m := range procedureCallPattern.FindAllStringSubmatch(text, -1) {
+		raise(procedureType(m[1]))
+	}
+	return result
+}
+
+// isSchemaCommand reports whether the keywords following CREATE or DROP name
+// an index or constraint, allowing for a kind such as TEXT or FULLTEXT.
+func isSchemaCommand(rest []string) bool {
+	for i := 0; i < len(rest) && i < 2; i++ {
+		if rest[i] == "INDEX" || rest[i] == "CONSTRAINT" {
+			return true
+		}
+	}
+	return false
+}
+
+func procedureType(name string) string {
+	name = strings.ToLower(name)
+	if t, ok := procedureTypes[name]; ok {
+		return t
+	}
+	for _, p := range procedurePrefixes {
+		if strings.HasPrefix(name, p.prefix) {
+			return p.queryType
+		}
+	}
+	return queryTypeWrite
+}
+
+// stripLiteralsAndComments blanks out string literals, backtick-quoted names
+// and comments so their contents are not mistaken for keywords.
+func stripLiteralsAndComments(query string) string {
+	runes := []rune(query)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; {
+		case r == '\'' || r == '"' || r == '`':
+			j := i + 1
+			for j < len(runes) && runes[j] != r {
+				if runes[j] == '\\' && r != '`' {
+					runes[j] = ' '
+					j++
+					if j >= len(runes) {
+						break
+					}
+				}
+				runes[j] = ' '
+				j++
+			}
+			i = j
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			for ; i < len(runes) && runes[i] != '\n'; i++ {
+				runes[i] = ' '
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			for ; i < len(runes); i++ {
+				if runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/' {
+					runes[i], runes[i+1] = ' ', ' '
+					i++
+					break
+				}
+				runes[i] = ' '
+			}
+		}
+	}
+	return string(runes)
+}
+
+// queryKeywords returns the upper-cased words of text in order, skipping
+// property keys, labels, parameters and map keys.
+func queryKeywords(text string) []string {
+	runes := []rune(text)
+	var words []string
+	for i := 0; i < len(runes); {
+		if !unicode.IsLetter(runes[i]) && runes[i] != '_' {
+			i++
+			continue
+		}
+		start := i
+		for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+			i++
+		}
+		prev := nonSpaceRune(runes, start-1, -1)
+		next := nonSpaceRune(runes, i, 1)
+		if prev == '.' || prev == ':' || prev == '$' || next == ':' {
+			continue
+		}
+		words = append(words, strings.ToUpper(string(runes[start:i])))
+	}
+	return words
+}
+
+// nonSpaceRune returns the first non-space rune from i stepping by step, or
+// zero when there is none.
+func nonSpaceRune(runes []rune, i, step int) rune {
+	for ; i >= 0 && i < len(runes); i += step {
+		if !unicode.IsSpace(runes[i]) {
+			return runes[i]
+		}
+	}
+	return 0
+}
@@ -3,6 +3,7 @@ package driver
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -20,6 +21,16 @@ type ReactiveResult interface {
 	// Subscribe consumes the reactive stream with the provided subscriber
 	Subscribe(ctx context.Context, subscriber Subscriber) error
 
+	// SubscribeWith is like Subscribe but returns a Subscription whose
+	// Cancel stops delivery and releases the underlying result
+	SubscribeWith(ctx context.Context, subscriber Subscriber) (Subscription, error)
+
+	// PipeTo feeds every event to sink and blocks until the stream ends,
+	// returning its error. Operators applied before PipeTo shape what the
+	// sink sees; wrap sink in a PipeSubscriber to transform records on the
+	// way into another stage (blocking operation)
+	PipeTo(ctx context.Context, sink Subscriber) error
+
 	// Records returns a channel that emits RecordEvent items
 	Records(ctx context.Context) <-chan RecordEvent
 
@@ -29,24 +40,50 @@ type ReactiveResult interface {
 	// Filter filters records based on a predicate function
 	Filter(fn FilterFunc) ReactiveResult
 
-	// Map transforms records to a different type
+	// Map transforms records to a different type. Values that are not a
+	// Record or map are wrapped as Record{"value": v}
 	Map(fn MapFunc) ReactiveResult
 
+	// MapE is like Map, but an error returned by fn ends the stream with an
+	// error event
+	MapE(fn MapEFunc) ReactiveResult
+
 	// Batch groups records into batches of specified size
 	Batch(size int) ReactiveResult
 
+	// Buffer decouples a fast producer from a slow consumer through a queue
+	// of the given capacity, applying strategy when the queue is full.
+	// Dropped records are reported in Metrics().RecordsDropped
+	Buffer(capacity int, strategy BackpressureStrategy) ReactiveResult
+
 	// BatchByTime groups records into time-based batches
 	BatchByTime(duration time.Duration) ReactiveResult
 
+	// WindowByTime groups records into windows of the given length, emitted
+	// as Record{"window": []*Record, "start": time.Time, "end": time.Time}.
+	// Tumbling windows follow one another; overlapping ones start every half
+	// window, so each record appears in two. Empty windows are not emitted
+	WindowByTime(d time.Duration, overlapping bool) ReactiveResult
+
 	// Take limits the stream to the first n records
 	Take(n int64) ReactiveResult
 
 	// Skip skips the first n records
 	Skip(n int64) ReactiveResult
 
+	// Sample forwards every nth record and drops the rest
+	Sample(n int) ReactiveResult
+
 	// Distinct removes duplicate records based on a key function
 	Distinct(keyFunc func(*Record) string) ReactiveResult
 
+	// GroupBy buffers records by key and, when the stream completes, emits
+	// one Record{"key": k, "group": []*Record} per key in first-seen order
+	GroupBy(keyFunc func(*Record) string) ReactiveResult
+
+	// Scan emits the running accumulator after each record as Record{"acc": ...}
+	Scan(initial interface{}, fn ScanFunc) ReactiveResult
+
 	// Throttle limits the rate of record emission
 	Throttle(rate time.Duration) ReactiveResult
 
@@ -62,17 +99,42 @@ type ReactiveResult interface {
 	// DoOnError performs a side effect when an error occurs
 	DoOnError(action func(error)) ReactiveResult
 
+	// DoFinally runs action exactly once when the stream terminates for any
+	// reason: completion, error or cancellation
+	DoFinally(action func()) ReactiveResult
+
+	// DoOnStart runs action once when the stream begins pulling from the
+	// source, before the first record
+	DoOnStart(action func()) ReactiveResult
+
+	// DoOnBackpressure runs action whenever a Buffer in the chain drops
+	// records because its consumer can't keep up, with the number just
+	// dropped. Without a Buffer nothing is ever dropped, so it never fires
+	DoOnBackpressure(action func(dropped int64)) ReactiveResult
+
 	// Keys returns the column names for this result
 	Keys() ([]string, error)
 
 	// ToSlice collects all records into a slice (blocking operation)
 	ToSlice(ctx context.Context) ([]*Record, error)
 
+	// ToSliceN collects at most max records, then cancels the rest of the
+	// stream (blocking operation)
+	ToSliceN(ctx context.Context, max int) ([]*Record, error)
+
 	// First returns the first record (blocking operation)
 	First(ctx context.Context) (*Record, error)
 
 	// Count counts all records in the stream (blocking operation)
 	Count(ctx context.Context) (int64, error)
+
+	// ToChannel subscribes to the stream and returns a record channel and a
+	// terminal error channel; both are closed when the stream ends
+	ToChannel(ctx context.Context) (<-chan *Record, <-chan error)
+
+	// Metrics returns a snapshot of the stream metrics; it is the zero value
+	// unless ReactiveConfig.Metrics is enabled
+	Metrics() ReactiveMetrics
 }
 
 // RecordEvent represents an event in the reactive stream
@@ -102,10 +164,25 @@ type Subscriber interface {
 	OnComplete(summary *ResultSummary)
 }
 
+// Subscription is the handle returned by SubscribeWith.
+type Subscription interface {
+	// Cancel stops the stream and releases its connection. It may be called
+	// from inside a subscriber callback and more than once. Once it returns
+	// no further callbacks start; cancellation itself is not reported
+	// through OnError.
+	Cancel()
+
+	// Done is closed when delivery has ended, whether by completion, error
+	// or Cancel, and the result's resources have been released.
+	Done() <-chan struct{}
+}
+
 // Function types for reactive operators
 type TransformFunc func(*Record) *Record
 type FilterFunc func(*Record) bool
 type MapFunc func(*Record) interface{}
+type MapEFunc func(*Record) (interface{}, error)
+type ScanFunc func(acc interface{}, record *Record) interface{}
 type ErrorHandler func(error) error
 
 // BackpressureStrategy defines how to handle backpressure
@@ -141,6 +218,9 @@ type ReactiveConfig struct {
 
 	// Metrics enables reactive stream metrics collection
 	Metrics bool
+
+	// Clock drives the time-based operators. Nil uses the system clock.
+	Clock Clock
 }
 
 // DefaultReactiveConfig returns default reactive configuration
@@ -161,9 +241,14 @@ type reactiveResult struct {
 	params      map[string]interface{}
 	config      *ReactiveConfig
 	operators   []reactiveOperator
+	startHooks  []func()
 	mu          sync.RWMutex
 	logger      Logger
 	observables *observabilityInstruments
+	metrics     *ReactiveMetrics
+
+	// backpressureHooks are handed to every operator that can drop records.
+	backpressureHooks []func(dropped int64)
 }
 
 // reactiveOperator represents a composable operation in the reactive chain
@@ -171,19 +256,46 @@ type reactiveOperator interface {
 	apply(ctx context.Context, input <-chan RecordEvent, output chan<- RecordEvent) error
 }
 
+// droppingOperator is a reactiveOperator that may drop records under
+// backpressure. Records asks it for a copy reporting drops to onDrop, since
+// DoOnBackpressure may be chained after the operator was added.
+type droppingOperator interface {
+	withDropHook(onDrop func(dropped int64)) reactiveOperator
+}
+
 // NewReactiveResult creates a new reactive result from a streaming result
 func NewReactiveResult(source Result, query string, params map[string]interface{}, config *ReactiveConfig) ReactiveResult {
 	if config == nil {
 		config = DefaultReactiveConfig()
 	}
 
-	return &reactiveResult{
+	r := &reactiveResult{
 		source:    source,
 		query:     query,
 		params:    params,
 		config:    config,
 		operators: make([]reactiveOperator, 0),
 	}
+	if config.Metrics {
+		r.metrics = NewReactiveMetrics()
+	}
+	return r
+}
+
+// clock returns the configured Clock, defaulting to the system clock.
+func (r *reactiveResult) clock() Clock {
+	if r.config.Clock != nil {
+		return r.config.Clock
+	}
+	return RealClock{}
+}
+
+// Metrics returns a snapshot of the metrics collected for this stream.
+func (r *reactiveResult) Metrics() ReactiveMetrics {
+	if r.metrics == nil {
+		return ReactiveMetrics{}
+	}
+	return r.metrics.GetSnapshot()
 }
 
 func (r *reactiveResult) Keys() ([]string, error) {
@@ -193,46 +305,92 @@ func (r *reactiveResult) Keys() ([]string, error) {
 func (r *reactiveResult) Subscribe(ctx context.Context, subscriber Subscriber) error {
 	recordChan := r.Records(ctx)
 
+	go r.deliver(ctx, recordChan, subscriber, func() bool { return false })
+
+	return nil
+}
+
+// subscription implements Subscription.
+type subscription struct {
+	cancel   context.CancelFunc
+	canceled atomic.Bool
+	done     chan struct{}
+}
+
+func (s *subscription) Cancel() {
+	s.canceled.Store(true)
+	s.cancel()
+}
+
+func (s *subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+func (r *reactiveResult) SubscribeWith(ctx context.Context, subscriber Subscriber) (Subscription, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	sub := &subscription{cancel: cancel, done: make(chan struct{})}
+	recordChan := r.Records(ctx)
+
 	go func() {
-		defer func() {
-			if rec := recover(); rec != nil {
-				if err, ok := rec.(error); ok {
-					subscriber.OnError(err)
-				} else {
-					subscriber.OnError(NewUsageError("Panic in reactive stream"))
-				}
+		defer close(sub.done)
+		defer cancel()
+
+		r.deliver(ctx, recordChan, subscriber, sub.canceled.Load)
+
+		// Wait for the pipeline to wind down before touching the source.
+		for range recordChan {
+		}
+		if sub.canceled.Load() {
+			if cancelable, ok := r.source.(interface{ Cancel(context.Context) error }); ok {
+				_ = cancelable.Cancel(context.Background())
 			}
-		}()
+		}
+	}()
 
-		for {
-			select {
-			case event, ok := <-recordChan:
-				if !ok {
-					return // Channel closed
-				}
+	return sub, nil
+}
 
-				if event.Error != nil {
-					subscriber.OnError(event.Error)
-					return
-				}
+// deliver feeds events to subscriber until the stream terminates. stopped is
+// checked before every callback so a cancelled subscription goes quiet.
+func (r *reactiveResult) deliver(ctx context.Context, recordChan <-chan RecordEvent, subscriber Subscriber, stopped func() bool) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			if err, ok := rec.(error); ok {
+				subscriber.OnError(err)
+			} else {
+				subscriber.OnError(NewUsageError("Panic in reactive stream"))
+			}
+		}
+	}()
 
-				if event.Complete {
-					subscriber.OnComplete(event.Summary)
-					return
-				}
+	for {
+		select {
+		case event, ok := <-recordChan:
+			if !ok || stopped() {
+				return // Channel closed or subscription cancelled
+			}
 
-				if event.Record != nil {
-					subscriber.OnNext(event.Record)
-				}
+			if event.Error != nil {
+				subscriber.OnError(event.Error)
+				return
+			}
 
-			case <-ctx.Done():
-				subscriber.OnError(ctx.Err())
+			if event.Complete {
+				subscriber.OnComplete(event.Summary)
 				return
 			}
-		}
-	}()
 
-	return nil
+			if event.Record != nil {
+				subscriber.OnNext(event.Record)
+			}
+
+		case <-ctx.Done():
+			if !stopped() {
+				subscriber.OnError(ctx.Err())
+			}
+			return
+		}
+	}
 }
 
 func (r *reactiveResult) Records(ctx context.Context) <-chan RecordEvent {
@@ -257,6 +415,9 @@ func (r *reactiveResult) Records(ctx context.Context) <-chan RecordEvent {
 		// Apply operators in chain
 		current := source
 		for _, op := range r.operators {
+			if d, ok := op.(droppingOperator); ok && len(r.backpressureHooks) > 0 {
+				op = d.withDropHook(r.notifyBackpressure)
+			}
 			next := make(chan RecordEvent, r.config.BufferSize)
 			wg.Add(1)
 			go func(operator reactiveOperator, input <-chan RecordEvent, out chan<- RecordEvent) {
@@ -279,6 +440,9 @@ func (r *reactiveResult) Records(ctx context.Context) <-chan RecordEvent {
 					done = true
 					break
 				}
+				if event.Error != nil && r.metrics != nil {
+					r.metrics.RecordError()
+				}
 				select {
 				case output <- event:
 				case <-ctx.Done():
@@ -302,9 +466,23 @@ func (r *reactiveResult) Records(ctx context.Context) <-chan RecordEvent {
 	return output
 }
 
+// notifyBackpressure runs the DoOnBackpressure hooks.
+func (r *reactiveResult) notifyBackpressure(dropped int64) {
+	for _, hook := range r.backpressureHooks {
+		hook(dropped)
+	}
+}
+
 func (r *reactiveResult) emitFromSource(ctx context.Context, output chan<- RecordEvent) {
 	defer close(output)
 
+	for _, hook := range r.startHooks {
+		hook()
+	}
+	if r.metrics != nil {
+		r.metrics.start(len(r.operators))
+	}
+
 	for r.source.Next(ctx) {
 		record := r.source.Record()
 
@@ -320,6 +498,9 @@ func (r *reactiveResult) emitFromSource(ctx context.Context, output chan<- Recor
 
 		select {
 		case output <- event:
+			if r.metrics != nil {
+				r.metrics.RecordProcessed()
+			}
 		case <-ctx.Done():
 			return
 		}
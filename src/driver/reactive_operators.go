@@ -80,6 +80,16 @@ func (op *filterOperator) apply(ctx context.Context, input <-chan RecordEvent, o
 
 // Map operator implementation
 func (r *reactiveResult) Map(fn MapFunc) ReactiveResult {
+	if fn == nil {
+		return r.MapE(nil)
+	}
+	return r.MapE(func(record *Record) (interface{}, error) {
+		return fn(record), nil
+	})
+}
+
+// MapE operator implementation
+func (r *reactiveResult) MapE(fn MapEFunc) ReactiveResult {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -89,7 +99,7 @@ func (r *reactiveResult) Map(fn MapFunc) ReactiveResult {
 }
 
 type mapOperator struct {
-	fn MapFunc
+	fn MapEFunc
 }
 
 func (op *mapOperator) apply(ctx context.Context, input <-chan RecordEvent, output chan<- RecordEvent) error {
@@ -99,14 +109,14 @@ func (op *mapOperator) apply(ctx context.Context, input <-chan RecordEvent, outp
 			if !ok {
 				return nil
 			}
+			var mapErr error
 			if event.Record != nil && op.fn != nil {
-				mapped := op.fn(event.Record)
-				// Convert mapped result back to Record format
-				if mappedRecord, ok := mapped.(Record); ok {
-					event.Record = &mappedRecord
-				} else if mappedMap, ok := mapped.(map[string]interface{}); ok {
-					mappedRecord := Record(mappedMap)
-					event.Record = &mappedRecord
+				var mapped interface{}
+				mapped, mapErr = op.fn(event.Record)
+				if mapErr != nil {
+					event = RecordEvent{Error: mapErr}
+				} else {
+					event.Record = toMappedRecord(mapped)
 				}
 			}
 
@@ -115,12 +125,32 @@ func (op *mapOperator) apply(ctx context.Context, input <-chan RecordEvent, outp
 			case <-ctx.Done():
 				return ctx.Err()
 			}
+			if mapErr != nil {
+				return mapErr
+			}
 		case <-ctx.Done():
 			return ctx.Err()
 		}
 	}
 }
 
+// toMappedRecord converts a Map result back into a Record. Records and maps
+// are used as they are; any other value becomes Record{"value": v}.
+func toMappedRecord(mapped interface{}) *Record {
+	switch v := mapped.(type) {
+	case Record:
+		return &v
+	case *Record:
+		if v != nil {
+			return v
+		}
+	case map[string]interface{}:
+		record := Record(v)
+		return &record
+	}
+	return &Record{"value": mapped}
+}
+
 // Batch operator implementation
 func (r *reactiveResult) Batch(size int) ReactiveResult {
 	r.mu.Lock()
@@ -188,17 +218,18 @@ func (r *reactiveResult) BatchByTime(duration time.Duration) ReactiveResult {
 	defer r.mu.Unlock()
 
 	newResult := r.copy()
-	newResult.operators = append(newResult.operators, &batchByTimeOperator{duration: duration})
+	newResult.operators = append(newResult.operators, &batchByTimeOperator{duration: duration, clock: newResult.clock()})
 	return newResult
 }
 
 type batchByTimeOperator struct {
 	duration time.Duration
+	clock    Clock
 }
 
 func (op *batchByTimeOperator) apply(ctx context.Context, input <-chan RecordEvent, output chan<- RecordEvent) error {
 	batch := make([]*Record, 0, 100)
-	timer := time.NewTimer(op.duration)
+	timer := op.clock.NewTimer(op.duration)
 	defer timer.Stop()
 
 	emitBatch := func() {
@@ -233,7 +264,7 @@ func (op *batchByTimeOperator) apply(ctx context.Context, input <-chan RecordEve
 				}
 			}
 
-		case <-timer.C:
+		case <-timer.C():
 			emitBatch()
 
 		case <-ctx.Done():
@@ -242,6 +273,99 @@ func (op *batchByTimeOperator) apply(ctx context.Context, input <-chan RecordEve
 	}
 }
 
+// WindowByTime operator implementation
+func (r *reactiveResult) WindowByTime(d time.Duration, overlapping bool) ReactiveResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	newResult := r.copy()
+	newResult.operators = append(newResult.operators, &windowByTimeOperator{duration: d, overlapping: overlapping, clock: newResult.clock()})
+	return newResult
+}
+
+// windowByTimeOperator splits time into panes of one window, or half a
+// window when overlapping, and on each pane boundary emits the records of
+// the panes the window ending there covers.
+type windowByTimeOperator struct {
+	duration    time.Duration
+	overlapping bool
+	clock       Clock
+}
+
+func (op *windowByTimeOperator) apply(ctx context.Context, input <-chan RecordEvent, output chan<- RecordEvent) error {
+	step, panes := op.duration, 1
+	if op.overlapping {
+		step, panes = op.duration/2, 2
+	}
+	if step <= 0 {
+		step = time.Millisecond
+	}
+	ticker := op.clock.NewTicker(step)
+	defer ticker.Stop()
+
+	// recent holds the panes still inside a window, the current one last.
+	recent := [][]*Record{nil}
+
+	emitWindow := func(end time.Time) error {
+		var window []*Record
+		for _, pane := range recent {
+			window = append(window, pane...)
+		}
+		if len(window) == 0 {
+			return nil
+		}
+		windowRecord := Record{"window": window, "start": end.Add(-op.duration), "end": end}
+		select {
+		case output <- RecordEvent{Record: &windowRecord}:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	for {
+		select {
+		case event, ok := <-input:
+			if !ok {
+				if len(recent[len(recent)-1]) > 0 {
+					return emitWindow(op.clock.Now())
+				}
+				return nil
+			}
+
+			if event.Record != nil {
+				recent[len(recent)-1] = append(recent[len(recent)-1], event.Record)
+				continue
+			}
+
+			// Flush the partial window before completion or error.
+			if len(recent[len(recent)-1]) > 0 {
+				if err := emitWindow(op.clock.Now()); err != nil {
+					return err
+				}
+				recent = [][]*Record{nil}
+			}
+			select {
+			case output <- event:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+		case end := <-ticker.C():
+			if err := emitWindow(end); err != nil {
+				return err
+			}
+			recent = append(recent, nil)
+			if len(recent) > panes {
+				recent = recent[len(recent)-panes:]
+			}
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 // Take operator implementation
 func (r *reactiveResult) Take(n int64) ReactiveResult {
 	r.mu.Lock()
@@ -329,6 +453,47 @@ func (op *skipOperator) apply(ctx context.Context, input <-chan RecordEvent, out
 	}
 }
 
+// Sample operator implementation
+func (r *reactiveResult) Sample(n int) ReactiveResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	newResult := r.copy()
+	newResult.operators = append(newResult.operators, &sampleOperator{n: n})
+	return newResult
+}
+
+type sampleOperator struct {
+	n int
+}
+
+func (op *sampleOperator) apply(ctx context.Context, input <-chan RecordEvent, output chan<- RecordEvent) error {
+	count := 0
+
+	for {
+		select {
+		case event, ok := <-input:
+			if !ok {
+				return nil
+			}
+			if event.Record != nil && op.n > 1 {
+				count++
+				if count%op.n != 0 {
+					continue // Not the nth record of this group
+				}
+			}
+
+			select {
+			case output <- event:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 // Distinct operator implementation
 func (r *reactiveResult) Distinct(keyFunc func(*Record) string) ReactiveResult {
 	r.mu.Lock()
@@ -369,22 +534,123 @@ func (op *distinctOperator) apply(ctx context.Context, input <-chan RecordEvent,
 	}
 }
 
+// GroupBy operator implementation
+func (r *reactiveResult) GroupBy(keyFunc func(*Record) string) ReactiveResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	newResult := r.copy()
+	newResult.operators = append(newResult.operators, &groupByOperator{keyFunc: keyFunc})
+	return newResult
+}
+
+type groupByOperator struct {
+	keyFunc func(*Record) string
+}
+
+func (op *groupByOperator) apply(ctx context.Context, input <-chan RecordEvent, output chan<- RecordEvent) error {
+	// Groups live in apply so each subscription starts empty.
+	groups := make(map[string][]*Record)
+	var order []string
+
+	for {
+		select {
+		case event, ok := <-input:
+			if !ok {
+				return nil
+			}
+			if event.Record != nil {
+				key := ""
+				if op.keyFunc != nil {
+					key = op.keyFunc(event.Record)
+				}
+				if _, exists := groups[key]; !exists {
+					order = append(order, key)
+				}
+				groups[key] = append(groups[key], event.Record)
+				continue
+			}
+
+			if event.Complete {
+				for _, key := range order {
+					select {
+					case output <- RecordEvent{Record: &Record{"key": key, "group": groups[key]}}:
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				}
+			}
+
+			select {
+			case output <- event:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Scan operator implementation
+func (r *reactiveResult) Scan(initial interface{}, fn ScanFunc) ReactiveResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	newResult := r.copy()
+	newResult.operators = append(newResult.operators, &scanOperator{initial: initial, fn: fn})
+	return newResult
+}
+
+type scanOperator struct {
+	initial interface{}
+	fn      ScanFunc
+}
+
+func (op *scanOperator) apply(ctx context.Context, input <-chan RecordEvent, output chan<- RecordEvent) error {
+	// The accumulator lives in apply so each subscription starts from initial.
+	acc := op.initial
+
+	for {
+		select {
+		case event, ok := <-input:
+			if !ok {
+				return nil
+			}
+			if event.Record != nil && op.fn != nil {
+				acc = op.fn(acc, event.Record)
+				accRecord := Record{"acc": acc}
+				event.Record = &accRecord
+			}
+
+			select {
+			case output <- event:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 // Throttle operator implementation
 func (r *reactiveResult) Throttle(rate time.Duration) ReactiveResult {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	newResult := r.copy()
-	newResult.operators = append(newResult.operators, &throttleOperator{rate: rate})
+	newResult.operators = append(newResult.operators, &throttleOperator{rate: rate, clock: newResult.clock()})
 	return newResult
 }
 
 type throttleOperator struct {
-	rate time.Duration
+	rate  time.Duration
+	clock Clock
 }
 
 func (op *throttleOperator) apply(ctx context.Context, input <-chan RecordEvent, output chan<- RecordEvent) error {
-	ticker := time.NewTicker(op.rate)
+	ticker := op.clock.NewTicker(op.rate)
 	defer ticker.Stop()
 
 	for {
@@ -396,7 +662,7 @@ func (op *throttleOperator) apply(ctx context.Context, input <-chan RecordEvent,
 			if event.Record != nil {
 				// Wait for next tick before emitting record
 				select {
-				case <-ticker.C:
+				case <-ticker.C():
 				case <-ctx.Done():
 					return ctx.Err()
 				}
@@ -413,6 +679,107 @@ func (op *throttleOperator) apply(ctx context.Context, input <-chan RecordEvent,
 	}
 }
 
+// Buffer operator implementation
+func (r *reactiveResult) Buffer(capacity int, strategy BackpressureStrategy) ReactiveResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if capacity <= 0 {
+		capacity = 1
+	}
+	newResult := r.copy()
+	newResult.operators = append(newResult.operators, &bufferOperator{
+		capacity: capacity,
+		strategy: strategy,
+		metrics:  newResult.metrics,
+	})
+	return newResult
+}
+
+// bufferOperator decouples the upstream producer from downstream consumers
+// through a bounded queue. A producer goroutine feeds the queue through a
+// BackpressureHandler, which decides what happens to records that arrive
+// while it is full; errors and completion are never dropped.
+type bufferOperator struct {
+	capacity int
+	strategy BackpressureStrategy
+	metrics  *ReactiveMetrics
+	onDrop   func(dropped int64)
+}
+
+func (op *bufferOperator) withDropHook(onDrop func(dropped int64)) reactiveOperator {
+	withHook := *op
+	withHook.onDrop = onDrop
+	return &withHook
+}
+
+func (op *bufferOperator) apply(ctx context.Context, input <-chan RecordEvent, output chan<- RecordEvent) error {
+	queue := make(chan RecordEvent, op.capacity)
+	// Only BackpressureLatest parks an overflow record in the handler; the
+	// other strategies get an unbuffered slot that nothing reads from.
+	pending := 0
+	if op.strategy == BackpressureLatest {
+		pending = 1
+	}
+	handler := NewBackpressureHandler(op.strategy, pending)
+
+	go func() {
+		defer close(queue)
+		var dropped int64
+		for {
+			select {
+			case event, ok := <-input:
+				if !ok {
+					_ = handler.DrainBuffer(ctx, queue)
+					return
+				}
+				if event.Record == nil {
+					if handler.DrainBuffer(ctx, queue) != nil {
+						return
+					}
+					select {
+					case queue <- event:
+					case <-ctx.Done():
+						return
+					}
+					continue
+				}
+				if len(queue) == cap(queue) && op.metrics != nil {
+					op.metrics.recordBackpressure()
+				}
+				if handler.Handle(ctx, event, queue) != nil {
+					return
+				}
+				if total := handler.GetDroppedCount(); total > dropped {
+					if op.metrics != nil {
+						for i := dropped; i < total; i++ {
+							op.metrics.RecordDropped()
+						}
+					}
+					if op.onDrop != nil {
+						op.onDrop(total - dropped)
+					}
+					dropped = total
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for event := range queue {
+		select {
+		case output <- event:
+		case <-ctx.Done():
+			// Unblock the producer so it can observe cancellation and exit.
+			for range queue {
+			}
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
 // Side effect operators
 func (r *reactiveResult) OnError(handler ErrorHandler) ReactiveResult {
 	r.mu.Lock()
@@ -560,18 +927,90 @@ func (op *doOnErrorOperator) apply(ctx context.Context, input <-chan RecordEvent
 	}
 }
 
+func (r *reactiveResult) DoFinally(action func()) ReactiveResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	newResult := r.copy()
+	newResult.operators = append(newResult.operators, &doFinallyOperator{action: action})
+	return newResult
+}
+
+type doFinallyOperator struct {
+	action func()
+}
+
+func (op *doFinallyOperator) apply(ctx context.Context, input <-chan RecordEvent, output chan<- RecordEvent) error {
+	if op.action != nil {
+		defer op.action()
+	}
+
+	for {
+		select {
+		case event, ok := <-input:
+			if !ok {
+				return nil
+			}
+
+			select {
+			case output <- event:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// DoOnStart registers a hook on the source rather than an operator, so it
+// fires once per subscription no matter how many operators are chained.
+func (r *reactiveResult) DoOnStart(action func()) ReactiveResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	newResult := r.copy()
+	if action != nil {
+		newResult.startHooks = append(newResult.startHooks, action)
+	}
+	return newResult
+}
+
+// DoOnBackpressure, like DoOnStart, registers a hook rather than an
+// operator; it reaches every Buffer in the chain, including earlier ones.
+func (r *reactiveResult) DoOnBackpressure(action func(dropped int64)) ReactiveResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	newResult := r.copy()
+	if action != nil {
+		newResult.backpressureHooks = append(newResult.backpressureHooks, action)
+	}
+	return newResult
+}
+
 // Helper method to copy reactive result for operator chaining
 func (r *reactiveResult) copy() *reactiveResult {
 	operators := make([]reactiveOperator, len(r.operators))
 	copy(operators, r.operators)
+	startHooks := make([]func(), len(r.startHooks))
+	copy(startHooks, r.startHooks)
+	backpressureHooks := make([]func(int64), len(r.backpressureHooks))
+	copy(backpressureHooks, r.backpressureHooks)
 
+	// Derived results share the source, which can only be consumed once, so
+	// they also share its metrics.
 	return &reactiveResult{
 		source:      r.source,
 		query:       r.query,
 		params:      r.params,
 		config:      r.config,
 		operators:   operators,
+		startHooks:  startHooks,
 		logger:      r.logger,
 		observables: r.observables,
+		metrics:     r.metrics,
+
+		backpressureHooks: backpressureHooks,
 	}
 }
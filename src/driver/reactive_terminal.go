@@ -35,6 +35,52 @@ func (r *reactiveResult) ToSlice(ctx context.Context) ([]*Record, error) {
 	return records, nil
 }
 
+// ToSliceN collects at most max records (blocking operation). Once max
+// records have arrived the stream is cancelled and the underlying result is
+// cancelled too, so the rest of a large result is never read.
+func (r *reactiveResult) ToSliceN(ctx context.Context, max int) ([]*Record, error) {
+	if max <= 0 {
+		return nil, NewUsageError("ToSliceN requires a positive max")
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	records := make([]*Record, 0, max)
+	var err error
+	// Keep reading until the channel closes so every stage has exited
+	// before the source is cancelled.
+	for event := range r.Take(int64(max)).Records(streamCtx) {
+		if err != nil || len(records) == max {
+			continue
+		}
+		switch {
+		case event.Error != nil:
+			err = event.Error
+			cancel()
+		case event.Record != nil:
+			records = append(records, event.Record)
+			if len(records) == max {
+				cancel()
+			}
+		}
+	}
+
+	if len(records) == max {
+		if cancelable, ok := r.source.(interface{ Cancel(context.Context) error }); ok {
+			_ = cancelable.Cancel(ctx)
+		}
+		return records, nil
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
 type sliceSubscriber struct {
 	records *[]*Record
 	err     *error
@@ -160,8 +206,112 @@ func (s *countSubscriber) OnComplete(summary *ResultSummary) {
 	s.wg.Done()
 }
 
+// ToChannel exposes the stream as a pair of channels. Records are delivered
+// on the first; the second receives at most one error (the stream error or
+// the context's) and is closed without a value on successful completion.
+// Both channels are closed when the stream terminates.
+func (r *reactiveResult) ToChannel(ctx context.Context) (<-chan *Record, <-chan error) {
+	records := make(chan *Record, r.config.BufferSize)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(records)
+
+		events := r.Records(ctx)
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					if err := ctx.Err(); err != nil {
+						errs <- err
+					}
+					return
+				}
+				if event.Error != nil {
+					errs <- event.Error
+					return
+				}
+				if event.Complete {
+					return
+				}
+				if event.Record == nil {
+					continue
+				}
+				select {
+				case records <- event.Record:
+				case <-ctx.Done():
+					errs <- ctx.Err()
+					return
+				}
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return records, errs
+}
+
+// PipeTo delivers the stream to sink and waits for it to end. The sink sees
+// the terminal OnError or OnComplete as usual; the error is also returned.
+func (r *reactiveResult) PipeTo(ctx context.Context, sink Subscriber) error {
+	var streamErr error
+	sub, err := r.SubscribeWith(ctx, &FuncSubscriber{
+		OnNextFunc: sink.OnNext,
+		OnErrorFunc: func(err error) {
+			streamErr = err
+			sink.OnError(err)
+		},
+		OnCompleteFunc: sink.OnComplete,
+	})
+	if err != nil {
+		return err
+	}
+
+	<-sub.Done()
+	return streamErr
+}
+
 // Common subscriber implementations for convenience
 
+// PipeSubscriber adapts one stage of a pipeline to the next: each record is
+// passed through Transform, when set, and forwarded to Sink. A nil result
+// from Transform drops the record. Errors and completion are forwarded
+// unchanged, so PipeSubscribers chain:
+//
+//	enrich := NewPipeSubscriber(store, addTimestamp)
+//	err := result.Filter(isActive).PipeTo(ctx, NewPipeSubscriber(enrich, redact))
+type PipeSubscriber struct {
+	Sink      Subscriber
+	Transform TransformFunc
+}
+
+// NewPipeSubscriber returns a PipeSubscriber forwarding to sink through
+// transform, which may be nil.
+func NewPipeSubscriber(sink Subscriber, transform TransformFunc) *PipeSubscriber {
+	return &PipeSubscriber{Sink: sink, Transform: transform}
+}
+
+func (p *PipeSubscriber) OnNext(record *Record) {
+	if p.Transform != nil {
+		record = p.Transform(record)
+		if record == nil {
+			return
+		}
+	}
+	p.Sink.OnNext(record)
+}
+
+func (p *PipeSubscriber) OnError(err error) {
+	p.Sink.OnError(err)
+}
+
+func (p *PipeSubscriber) OnComplete(summary *ResultSummary) {
+	p.Sink.OnComplete(summary)
+}
+
 // FuncSubscriber allows using functions as subscribers
 type FuncSubscriber struct {
 	OnNextFunc     func(*Record)
@@ -284,22 +434,28 @@ func (bp *BackpressureHandler) Handle(ctx context.Context, event RecordEvent, ou
 		}
 
 	case BackpressureLatest:
+		// A parked event is older than this one, so it has to go first; if
+		// there is still no room it is superseded.
+		select {
+		case parked := <-bp.buffer:
+			select {
+			case output <- parked:
+			default:
+				bp.mu.Lock()
+				bp.dropped++
+				bp.mu.Unlock()
+			}
+		default:
+		}
 		select {
 		case output <- event:
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
-			// Try to replace buffered event with latest
-			select {
-			case <-bp.buffer:
-				// Removed old event
-			default:
-				// No buffered event
-			}
 			select {
 			case bp.buffer <- event:
 			default:
-				// Still couldn't buffer, drop
+				// No room to park it either, drop
 				bp.mu.Lock()
 				bp.dropped++
 				bp.mu.Unlock()
@@ -344,6 +500,7 @@ type ReactiveMetrics struct {
 	BackpressureEvents int64
 	ErrorCount         int64
 	OperatorCount      int
+	started            time.Time
 	mu                 sync.RWMutex
 }
 
@@ -352,11 +509,29 @@ func NewReactiveMetrics() *ReactiveMetrics {
 	return &ReactiveMetrics{}
 }
 
-// RecordProcessed increments the processed records counter
+// start marks the beginning of a stream run; throughput and latency are
+// measured from here.
+func (m *ReactiveMetrics) start(operators int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.started = time.Now()
+	m.OperatorCount = operators
+}
+
+// RecordProcessed increments the processed records counter and refreshes
+// ThroughputPerSec and AverageLatency (mean time per record since start).
 func (m *ReactiveMetrics) RecordProcessed() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.RecordsProcessed++
+	if m.started.IsZero() {
+		return
+	}
+	elapsed := time.Since(m.started)
+	m.AverageLatency = elapsed / time.Duration(m.RecordsProcessed)
+	if elapsed > 0 {
+		m.ThroughputPerSec = float64(m.RecordsProcessed) / elapsed.Seconds()
+	}
 }
 
 // RecordDropped increments the dropped records counter
@@ -366,6 +541,13 @@ func (m *ReactiveMetrics) RecordDropped() {
 	m.RecordsDropped++
 }
 
+// recordBackpressure counts a record that arrived while a buffer was full.
+func (m *ReactiveMetrics) recordBackpressure() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.BackpressureEvents++
+}
+
 // RecordError increments the error counter
 func (m *ReactiveMetrics) RecordError() {
 	m.mu.Lock()
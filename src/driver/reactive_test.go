@@ -3,7 +3,9 @@ package driver
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -561,3 +563,748 @@ func TestReactiveResult_DoOnNext(t *testing.T) {
 		t.Errorf("Expected side effect to be called 3 times, got %d", sideEffectCount)
 	}
 }
+
+func TestReactiveResult_DoFinally(t *testing.T) {
+	records := []*Record{
+		{"value": 1},
+		{"value": 2},
+		{"value": 3},
+	}
+	keys := []string{"value"}
+
+	tests := []struct {
+		name    string
+		setup   func(*MockReactiveStreamConnection)
+		timeout time.Duration
+	}{
+		{name: "complete", setup: func(*MockReactiveStreamConnection) {}},
+		{name: "error", setup: func(m *MockReactiveStreamConnection) { m.SetError(true) }},
+		{name: "cancel", setup: func(m *MockReactiveStreamConnection) { m.SetDelay(100 * time.Millisecond) }, timeout: 50 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := NewMockReactiveStreamConnection(records, keys)
+			tt.setup(conn)
+			reactiveResult := NewReactiveResult(NewStreamingResult(conn, "MOCK QUERY", nil), "MATCH (n) RETURN n.value", nil, DefaultReactiveConfig())
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			var mu sync.Mutex
+			var calls int
+			withFinally := reactiveResult.DoFinally(func() {
+				mu.Lock()
+				calls++
+				mu.Unlock()
+			})
+
+			// Draining the channel waits for every operator goroutine to exit.
+			for range withFinally.Records(ctx) {
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if calls != 1 {
+				t.Errorf("Expected finally action to run once, got %d", calls)
+			}
+		})
+	}
+}
+
+func TestReactiveResult_Scan(t *testing.T) {
+	records := []*Record{
+		{"value": 1},
+		{"value": 2},
+		{"value": 3},
+	}
+	keys := []string{"value"}
+
+	streamingResult := createMockStreamingResult(records, keys)
+	reactiveResult := NewReactiveResult(streamingResult, "MATCH (n) RETURN n.value", nil, DefaultReactiveConfig())
+
+	sums := reactiveResult.Scan(0, func(acc interface{}, record *Record) interface{} {
+		return acc.(int) + (*record)["value"].(int)
+	})
+
+	ctx := context.Background()
+	collectedRecords, err := sums.ToSlice(ctx)
+	if err != nil {
+		t.Fatalf("ToSlice failed: %v", err)
+	}
+
+	expected := []int{1, 3, 6}
+	if len(collectedRecords) != len(expected) {
+		t.Fatalf("Expected %d records, got %d", len(expected), len(collectedRecords))
+	}
+	for i, want := range expected {
+		if got := (*collectedRecords[i])["acc"]; got != want {
+			t.Errorf("Record %d: expected acc %d, got %v", i, want, got)
+		}
+	}
+}
+
+func TestReactiveResult_DoOnStart(t *testing.T) {
+	records := []*Record{
+		{"value": 1},
+		{"value": 2},
+		{"value": 3},
+	}
+	keys := []string{"value"}
+
+	streamingResult := createMockStreamingResult(records, keys)
+	reactiveResult := NewReactiveResult(streamingResult, "MATCH (n) RETURN n.value", nil, DefaultReactiveConfig())
+
+	var starts, seen int32
+	var seenAtStart int32 = -1
+	result := reactiveResult.
+		DoOnStart(func() {
+			atomic.AddInt32(&starts, 1)
+			atomic.StoreInt32(&seenAtStart, atomic.LoadInt32(&seen))
+		}).
+		DoOnNext(func(*Record) { atomic.AddInt32(&seen, 1) }).
+		Filter(func(*Record) bool { return true }).
+		Transform(func(record *Record) *Record { return record })
+
+	collectedRecords, err := result.ToSlice(context.Background())
+	if err != nil {
+		t.Fatalf("ToSlice failed: %v", err)
+	}
+	if len(collectedRecords) != 3 {
+		t.Fatalf("Expected 3 records, got %d", len(collectedRecords))
+	}
+	if got := atomic.LoadInt32(&starts); got != 1 {
+		t.Errorf("Expected start hook to fire once, fired %d times", got)
+	}
+	if got := atomic.LoadInt32(&seenAtStart); got != 0 {
+		t.Errorf("Expected start hook to fire before the first record, %d records were already seen", got)
+	}
+}
+
+func TestReactiveResult_Sample(t *testing.T) {
+	records := make([]*Record, 10)
+	for i := range records {
+		records[i] = &Record{"value": i + 1}
+	}
+	keys := []string{"value"}
+
+	tests := []struct {
+		name     string
+		n        int
+		expected []int
+	}{
+		{"every third", 3, []int{3, 6, 9}},
+		{"n of one forwards all", 1, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}},
+		{"non-positive forwards all", 0, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			streamingResult := createMockStreamingResult(records, keys)
+			reactiveResult := NewReactiveResult(streamingResult, "MATCH (n) RETURN n.value", nil, DefaultReactiveConfig())
+
+			collectedRecords, err := reactiveResult.Sample(tt.n).ToSlice(context.Background())
+			if err != nil {
+				t.Fatalf("ToSlice failed: %v", err)
+			}
+			if len(collectedRecords) != len(tt.expected) {
+				t.Fatalf("Expected %d records, got %d", len(tt.expected), len(collectedRecords))
+			}
+			for i, want := range tt.expected {
+				if got := (*collectedRecords[i])["value"]; got != want {
+					t.Errorf("Record %d: expected %d, got %v", i, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestReactiveResult_ToChannel(t *testing.T) {
+	records := []*Record{
+		{"value": 1},
+		{"value": 2},
+		{"value": 3},
+	}
+	keys := []string{"value"}
+
+	streamingResult := createMockStreamingResult(records, keys)
+	reactiveResult := NewReactiveResult(streamingResult, "MATCH (n) RETURN n.value", nil, DefaultReactiveConfig())
+
+	recordChan, errChan := reactiveResult.ToChannel(context.Background())
+
+	var values []int
+	for record := range recordChan {
+		values = append(values, (*record)["value"].(int))
+	}
+	if len(values) != 3 || values[0] != 1 || values[1] != 2 || values[2] != 3 {
+		t.Errorf("Expected [1 2 3], got %v", values)
+	}
+
+	select {
+	case err, ok := <-errChan:
+		if ok {
+			t.Errorf("Expected error channel to close without a value, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected error channel to be closed")
+	}
+}
+
+func TestReactiveResult_Metrics(t *testing.T) {
+	records := []*Record{{"value": 1}, {"value": 2}, {"value": 3}, {"value": 4}}
+	streamingResult := createMockStreamingResult(records, []string{"value"})
+	reactiveResult := NewReactiveResult(streamingResult, "MATCH (n) RETURN n.value", nil, DefaultReactiveConfig()).
+		Filter(func(r *Record) bool { return (*r)["value"].(int)%2 == 0 })
+
+	count, err := reactiveResult.Count(context.Background())
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("Expected 2 filtered records, got %d", count)
+	}
+
+	metrics := reactiveResult.Metrics()
+	if metrics.RecordsProcessed != int64(len(records)) {
+		t.Errorf("Expected %d records processed, got %d", len(records), metrics.RecordsProcessed)
+	}
+	if metrics.OperatorCount != 1 {
+		t.Errorf("Expected operator count 1, got %d", metrics.OperatorCount)
+	}
+	if metrics.ThroughputPerSec <= 0 {
+		t.Errorf("Expected positive throughput, got %f", metrics.ThroughputPerSec)
+	}
+	if metrics.ErrorCount != 0 {
+		t.Errorf("Expected no errors, got %d", metrics.ErrorCount)
+	}
+}
+
+func TestReactiveResult_MetricsCountsErrors(t *testing.T) {
+	conn := NewMockReactiveStreamConnection(nil, []string{"value"})
+	conn.SetError(true)
+	reactiveResult := NewReactiveResult(NewStreamingResult(conn, "MOCK QUERY", nil), "MOCK QUERY", nil, DefaultReactiveConfig())
+
+	if _, err := reactiveResult.Count(context.Background()); err == nil {
+		t.Fatal("Expected error from source")
+	}
+	if got := reactiveResult.Metrics().ErrorCount; got != 1 {
+		t.Errorf("Expected error count 1, got %d", got)
+	}
+}
+
+func TestReactiveResult_MetricsDisabled(t *testing.T) {
+	config := DefaultReactiveConfig()
+	config.Metrics = false
+	streamingResult := createMockStreamingResult([]*Record{{"value": 1}}, []string{"value"})
+	reactiveResult := NewReactiveResult(streamingResult, "MOCK QUERY", nil, config)
+
+	if _, err := reactiveResult.Count(context.Background()); err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if got := reactiveResult.Metrics().RecordsProcessed; got != 0 {
+		t.Errorf("Expected no metrics when disabled, got %d records", got)
+	}
+}
+
+func TestReactiveResult_BufferDropsWhenFull(t *testing.T) {
+	records := make([]*Record, 50)
+	for i := range records {
+		records[i] = &Record{"value": i}
+	}
+	config := DefaultReactiveConfig()
+	config.BufferSize = 1
+
+	streamingResult := createMockStreamingResult(records, []string{"value"})
+	reactiveResult := NewReactiveResult(streamingResult, "MATCH (n) RETURN n.value", nil, config).
+		Buffer(2, BackpressureDrop).
+		DoOnNext(func(*Record) { time.Sleep(5 * time.Millisecond) })
+
+	collected, err := reactiveResult.ToSlice(context.Background())
+	if err != nil {
+		t.Fatalf("ToSlice failed: %v", err)
+	}
+
+	metrics := reactiveResult.Metrics()
+	if metrics.RecordsDropped == 0 {
+		t.Fatal("Expected some records to be dropped by the slow consumer")
+	}
+	if got := int64(len(collected)) + metrics.RecordsDropped; got != int64(len(records)) {
+		t.Errorf("Expected received + dropped = %d, got %d (%d received)", len(records), got, len(collected))
+	}
+	last := -1
+	for _, record := range collected {
+		value := (*record)["value"].(int)
+		if value <= last {
+			t.Fatalf("Records out of order: %d after %d", value, last)
+		}
+		last = value
+	}
+}
+
+func TestReactiveResult_DoOnBackpressure(t *testing.T) {
+	records := make([]*Record, 50)
+	for i := range records {
+		records[i] = &Record{"value": i}
+	}
+	config := DefaultReactiveConfig()
+	config.BufferSize = 1
+
+	var calls, reported int64
+	streamingResult := createMockStreamingResult(records, []string{"value"})
+	// The hook is chained after Buffer and must still see its drops.
+	reactiveResult := NewReactiveResult(streamingResult, "MATCH (n) RETURN n.value", nil, config).
+		Buffer(2, BackpressureDrop).
+		DoOnNext(func(*Record) { time.Sleep(5 * time.Millisecond) }).
+		DoOnBackpressure(func(dropped int64) {
+			if dropped <= 0 {
+				t.Errorf("Expected a positive dropped count, got %d", dropped)
+			}
+			atomic.AddInt64(&calls, 1)
+			atomic.AddInt64(&reported, dropped)
+		})
+
+	if _, err := reactiveResult.ToSlice(context.Background()); err != nil {
+		t.Fatalf("ToSlice failed: %v", err)
+	}
+	if atomic.LoadInt64(&calls) == 0 {
+		t.Fatal("Expected the backpressure hook to fire for the slow consumer")
+	}
+	if got, want := atomic.LoadInt64(&reported), reactiveResult.Metrics().RecordsDropped; got != want {
+		t.Errorf("Expected the hook to report all %d drops, got %d", want, got)
+	}
+}
+
+func TestReactiveResult_BufferBlockKeepsEverything(t *testing.T) {
+	records := make([]*Record, 20)
+	for i := range records {
+		records[i] = &Record{"value": i}
+	}
+	config := DefaultReactiveConfig()
+	config.BufferSize = 1
+
+	streamingResult := createMockStreamingResult(records, []string{"value"})
+	reactiveResult := NewReactiveResult(streamingResult, "MATCH (n) RETURN n.value", nil, config).
+		Buffer(2, BackpressureBlock).
+		DoOnNext(func(*Record) { time.Sleep(time.Millisecond) })
+
+	collected, err := reactiveResult.ToSlice(context.Background())
+	if err != nil {
+		t.Fatalf("ToSlice failed: %v", err)
+	}
+	if len(collected) != len(records) {
+		t.Errorf("Expected %d records, got %d", len(records), len(collected))
+	}
+	if dropped := reactiveResult.Metrics().RecordsDropped; dropped != 0 {
+		t.Errorf("Expected no drops with BackpressureBlock, got %d", dropped)
+	}
+}
+
+func TestReactiveResult_MapWrapsNonMapValues(t *testing.T) {
+	records := []*Record{{"value": 1}, {"value": 2}}
+	streamingResult := createMockStreamingResult(records, []string{"value"})
+	reactiveResult := NewReactiveResult(streamingResult, "MATCH (n) RETURN n.value", nil, DefaultReactiveConfig())
+
+	collected, err := reactiveResult.Map(func(r *Record) interface{} {
+		return fmt.Sprintf("item-%d", (*r)["value"])
+	}).ToSlice(context.Background())
+	if err != nil {
+		t.Fatalf("ToSlice failed: %v", err)
+	}
+	if len(collected) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(collected))
+	}
+	if got := (*collected[1])["value"]; got != "item-2" {
+		t.Errorf("Expected wrapped value item-2, got %v", *collected[1])
+	}
+}
+
+func TestReactiveResult_MapEEmitsErrors(t *testing.T) {
+	records := []*Record{{"value": 1}, {"value": 2}, {"value": 3}}
+	streamingResult := createMockStreamingResult(records, []string{"value"})
+	reactiveResult := NewReactiveResult(streamingResult, "MATCH (n) RETURN n.value", nil, DefaultReactiveConfig())
+
+	mapErr := fmt.Errorf("cannot map 2")
+	var seen []int
+	var streamErr error
+	for event := range reactiveResult.MapE(func(r *Record) (interface{}, error) {
+		if (*r)["value"] == 2 {
+			return nil, mapErr
+		}
+		return r, nil
+	}).Records(context.Background()) {
+		if event.Error != nil {
+			streamErr = event.Error
+			continue
+		}
+		if event.Record != nil {
+			seen = append(seen, (*event.Record)["value"].(int))
+		}
+		if event.Complete {
+			t.Error("Stream should not complete after a mapping error")
+		}
+	}
+
+	if streamErr != mapErr {
+		t.Errorf("Expected mapping error event, got %v", streamErr)
+	}
+	if len(seen) != 1 || seen[0] != 1 {
+		t.Errorf("Expected only the record before the error, got %v", seen)
+	}
+}
+
+func TestReactiveResult_ToSliceN(t *testing.T) {
+	records := make([]*Record, 10)
+	for i := range records {
+		records[i] = &Record{"value": i}
+	}
+	conn := NewMockReactiveStreamConnection(records, []string{"value"})
+	conn.SetDelay(2 * time.Millisecond)
+	streamingResult := NewStreamingResult(conn, "MOCK QUERY", nil)
+	reactiveResult := NewReactiveResult(streamingResult, "UNWIND range(0, 9) AS value RETURN value", nil, DefaultReactiveConfig())
+
+	ctx := context.Background()
+	collected, err := reactiveResult.ToSliceN(ctx, 4)
+	if err != nil {
+		t.Fatalf("ToSliceN failed: %v", err)
+	}
+	if len(collected) != 4 {
+		t.Fatalf("Expected 4 records, got %d", len(collected))
+	}
+	for i, rec := range collected {
+		if (*rec)["value"] != i {
+			t.Errorf("Expected record %d to have value %d, got %v", i, i, (*rec)["value"])
+		}
+	}
+
+	if ctx.Err() != nil {
+		t.Error("Caller context should not be cancelled")
+	}
+	if streamingResult.IsOpen() {
+		t.Error("Expected the underlying result to be cancelled")
+	}
+	if conn.index >= len(records) {
+		t.Errorf("Expected the source to stop early, it pulled %d records", conn.index)
+	}
+}
+
+func TestReactiveResult_SubscribeWithCancel(t *testing.T) {
+	records := make([]*Record, 10)
+	for i := range records {
+		records[i] = &Record{"value": i}
+	}
+	conn := NewMockReactiveStreamConnection(records, []string{"value"})
+	conn.SetDelay(2 * time.Millisecond)
+	streamingResult := NewStreamingResult(conn, "MOCK QUERY", nil)
+	reactiveResult := NewReactiveResult(streamingResult, "UNWIND range(0, 9) AS value RETURN value", nil, DefaultReactiveConfig())
+
+	var sub Subscription
+	ready := make(chan struct{})
+	var nexts, terminals atomic.Int32
+	subscriber := &FuncSubscriber{
+		OnNextFunc: func(record *Record) {
+			<-ready
+			nexts.Add(1)
+			sub.Cancel()
+		},
+		OnErrorFunc:    func(err error) { terminals.Add(1) },
+		OnCompleteFunc: func(summary *ResultSummary) { terminals.Add(1) },
+	}
+
+	sub, err := reactiveResult.SubscribeWith(context.Background(), subscriber)
+	if err != nil {
+		t.Fatalf("SubscribeWith failed: %v", err)
+	}
+	close(ready)
+
+	select {
+	case <-sub.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("subscription did not finish after Cancel")
+	}
+	// Give a misbehaving pipeline the chance to deliver more.
+	time.Sleep(20 * time.Millisecond)
+
+	if n := nexts.Load(); n != 1 {
+		t.Errorf("expected exactly 1 OnNext call, got %d", n)
+	}
+	if n := terminals.Load(); n != 0 {
+		t.Errorf("expected no OnError or OnComplete after Cancel, got %d", n)
+	}
+	if streamingResult.IsOpen() {
+		t.Error("expected the underlying result to be cancelled")
+	}
+	if conn.index >= len(records) {
+		t.Errorf("expected the source to stop early, it pulled %d records", conn.index)
+	}
+	sub.Cancel() // idempotent
+}
+
+func TestReactiveResult_ToSliceNShortStream(t *testing.T) {
+	records := []*Record{{"value": 1}, {"value": 2}}
+	reactiveResult := NewReactiveResult(createMockStreamingResult(records, []string{"value"}), "MOCK QUERY", nil, DefaultReactiveConfig())
+
+	collected, err := reactiveResult.ToSliceN(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("ToSliceN failed: %v", err)
+	}
+	if len(collected) != 2 {
+		t.Errorf("Expected all 2 records, got %d", len(collected))
+	}
+
+	if _, err := reactiveResult.ToSliceN(context.Background(), 0); err == nil {
+		t.Error("Expected an error for a non-positive max")
+	}
+}
+
+func TestReactiveResult_GroupBy(t *testing.T) {
+	records := []*Record{
+		{"type": "fruit", "name": "apple"},
+		{"type": "veg", "name": "leek"},
+		{"type": "fruit", "name": "pear"},
+		{"type": "nut", "name": "pecan"},
+		{"type": "veg", "name": "kale"},
+	}
+	reactiveResult := NewReactiveResult(createMockStreamingResult(records, []string{"type", "name"}), "MOCK QUERY", nil, DefaultReactiveConfig())
+
+	grouped, err := reactiveResult.GroupBy(func(r *Record) string {
+		return (*r)["type"].(string)
+	}).ToSlice(context.Background())
+	if err != nil {
+		t.Fatalf("ToSlice failed: %v", err)
+	}
+
+	want := []struct {
+		key   string
+		names []string
+	}{
+		{"fruit", []string{"apple", "pear"}},
+		{"veg", []string{"leek", "kale"}},
+		{"nut", []string{"pecan"}},
+	}
+	if len(grouped) != len(want) {
+		t.Fatalf("Expected %d groups, got %d", len(want), len(grouped))
+	}
+	for i, w := range want {
+		rec := *grouped[i]
+		if rec["key"] != w.key {
+			t.Errorf("Group %d: expected key %q, got %v", i, w.key, rec["key"])
+		}
+		group, ok := rec["group"].([]*Record)
+		if !ok || len(group) != len(w.names) {
+			t.Fatalf("Group %q: expected %d records, got %v", w.key, len(w.names), rec["group"])
+		}
+		for j, name := range w.names {
+			if (*group[j])["name"] != name {
+				t.Errorf("Group %q record %d: expected %s, got %v", w.key, j, name, (*group[j])["name"])
+			}
+		}
+	}
+}
+
+func TestReactiveResult_BatchByTimeWithFakeClock(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	config := DefaultReactiveConfig()
+	config.Clock = clock
+	batched := NewReactiveResult(createMockStreamingResult(nil, []string{"n"}), "MOCK QUERY", nil, config).
+		BatchByTime(time.Second).(*reactiveResult)
+	op := batched.operators[0].(*batchByTimeOperator)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	input := make(chan RecordEvent)
+	output := make(chan RecordEvent, 10)
+	go func() { _ = op.apply(ctx, input, output) }()
+
+	input <- RecordEvent{Record: &Record{"n": 1}}
+	input <- RecordEvent{Record: &Record{"n": 2}}
+
+	clock.Advance(999 * time.Millisecond)
+	select {
+	case event := <-output:
+		t.Fatalf("expected no batch before the interval elapsed, got %v", event.Record)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	clock.Advance(time.Millisecond)
+	select {
+	case event := <-output:
+		batch, _ := (*event.Record)["batch"].([]*Record)
+		if len(batch) != 2 || (*batch[0])["n"] != 1 || (*batch[1])["n"] != 2 {
+			t.Errorf("expected a batch of both records, got %v", event.Record)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the batch to flush when the fake clock reached the interval")
+	}
+}
+
+func TestReactiveResult_WindowByTimeWithFakeClock(t *testing.T) {
+	start := time.Unix(0, 0)
+	run := func(t *testing.T, overlapping bool) (*FakeClock, chan<- RecordEvent, <-chan RecordEvent) {
+		clock := NewFakeClock(start)
+		config := DefaultReactiveConfig()
+		config.Clock = clock
+		windowed := NewReactiveResult(createMockStreamingResult(nil, []string{"n"}), "MOCK QUERY", nil, config).
+			WindowByTime(2*time.Second, overlapping).(*reactiveResult)
+		op := windowed.operators[0].(*windowByTimeOperator)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		input := make(chan RecordEvent)
+		output := make(chan RecordEvent, 10)
+		go func() { _ = op.apply(ctx, input, output) }()
+		return clock, input, output
+	}
+	expectWindow := func(t *testing.T, output <-chan RecordEvent, end time.Duration, want ...int) {
+		t.Helper()
+		select {
+		case event := <-output:
+			if event.Record == nil {
+				t.Fatalf("expected a window, got %+v", event)
+			}
+			window, _ := (*event.Record)["window"].([]*Record)
+			var got []int
+			for _, rec := range window {
+				got = append(got, (*rec)["n"].(int))
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("expected window %v, got %v", want, got)
+			}
+			if e, _ := (*event.Record)["end"].(time.Time); !e.Equal(start.Add(end)) {
+				t.Errorf("expected the window to end at %v, got %v", end, e.Sub(start))
+			}
+			if s, _ := (*event.Record)["start"].(time.Time); !s.Equal(start.Add(end - 2*time.Second)) {
+				t.Errorf("expected the window to start at %v, got %v", end-2*time.Second, s.Sub(start))
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected a window %v ending at %v", want, end)
+		}
+	}
+	expectNone := func(t *testing.T, output <-chan RecordEvent) {
+		t.Helper()
+		select {
+		case event := <-output:
+			t.Fatalf("expected no window, got %v", event.Record)
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+
+	t.Run("tumbling", func(t *testing.T) {
+		clock, input, output := run(t, false)
+		input <- RecordEvent{Record: &Record{"n": 1}}
+		input <- RecordEvent{Record: &Record{"n": 2}}
+		clock.Advance(time.Second)
+		expectNone(t, output)
+		clock.Advance(time.Second)
+		expectWindow(t, output, 2*time.Second, 1, 2)
+
+		input <- RecordEvent{Record: &Record{"n": 3}}
+		clock.Advance(2 * time.Second)
+		expectWindow(t, output, 4*time.Second, 3)
+
+		clock.Advance(2 * time.Second)
+		expectNone(t, output)
+
+		input <- RecordEvent{Record: &Record{"n": 4}}
+		input <- RecordEvent{Complete: true}
+		expectWindow(t, output, 6*time.Second, 4)
+		if event := <-output; !event.Complete {
+			t.Errorf("expected completion after the partial window, got %+v", event)
+		}
+	})
+
+	t.Run("overlapping", func(t *testing.T) {
+		clock, input, output := run(t, true)
+		input <- RecordEvent{Record: &Record{"n": 1}}
+		clock.Advance(time.Second)
+		expectWindow(t, output, time.Second, 1)
+
+		input <- RecordEvent{Record: &Record{"n": 2}}
+		clock.Advance(time.Second)
+		expectWindow(t, output, 2*time.Second, 1, 2)
+
+		input <- RecordEvent{Record: &Record{"n": 3}}
+		clock.Advance(time.Second)
+		expectWindow(t, output, 3*time.Second, 2, 3)
+
+		clock.Advance(time.Second)
+		expectWindow(t, output, 4*time.Second, 3)
+
+		clock.Advance(time.Second)
+		expectNone(t, output)
+	})
+}
+
+func TestFakeClock_Ticker(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	ticker := clock.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	clock.Advance(2500 * time.Millisecond)
+	select {
+	case tick := <-ticker.C():
+		if !tick.Equal(time.Unix(1, 0)) {
+			t.Errorf("expected the first pending tick at 1s, got %v", tick)
+		}
+	default:
+		t.Fatal("expected a tick")
+	}
+	select {
+	case tick := <-ticker.C():
+		t.Errorf("expected surplus ticks to be dropped, got %v", tick)
+	default:
+	}
+	if got := clock.Now(); !got.Equal(time.Unix(2, 5e8)) {
+		t.Errorf("expected the clock at 2.5s, got %v", got)
+	}
+}
+
+func TestReactiveResult_PipeTo(t *testing.T) {
+	records := []*Record{{"n": 1}, {"n": 2}, {"n": 3}, {"n": 4}}
+	reactiveResult := NewReactiveResult(createMockStreamingResult(records, []string{"n"}), "MOCK QUERY", nil, DefaultReactiveConfig())
+
+	var collected []*Record
+	var completed bool
+	collector := &FuncSubscriber{
+		OnNextFunc:     func(record *Record) { collected = append(collected, record) },
+		OnCompleteFunc: func(summary *ResultSummary) { completed = true },
+	}
+	double := func(record *Record) *Record {
+		n := (*record)["n"].(int)
+		if n == 3 {
+			return nil // dropped by the pipe
+		}
+		return &Record{"n": n * 2}
+	}
+
+	err := reactiveResult.
+		Filter(func(record *Record) bool { return (*record)["n"].(int) > 1 }).
+		PipeTo(context.Background(), NewPipeSubscriber(collector, double))
+	if err != nil {
+		t.Fatalf("PipeTo failed: %v", err)
+	}
+
+	if !completed {
+		t.Error("expected completion to be forwarded to the sink")
+	}
+	if len(collected) != 2 || (*collected[0])["n"] != 4 || (*collected[1])["n"] != 8 {
+		t.Errorf("expected [4 8], got %v", collected)
+	}
+}
+
+func TestReactiveResult_PipeToError(t *testing.T) {
+	conn := NewMockReactiveStreamConnection(nil, []string{"n"})
+	conn.SetError(true)
+	reactiveResult := NewReactiveResult(NewStreamingResult(conn, "MOCK QUERY", nil), "MOCK QUERY", nil, DefaultReactiveConfig())
+
+	var sinkErr error
+	err := reactiveResult.PipeTo(context.Background(), NewPipeSubscriber(&FuncSubscriber{
+		OnErrorFunc: func(err error) { sinkErr = err },
+	}, nil))
+	if err == nil || err != sinkErr {
+		t.Errorf("expected the stream error to be returned and forwarded, got %v and %v", err, sinkErr)
+	}
+}
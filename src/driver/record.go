@@ -0,0 +1,71 @@
+package driver
+
+import "math"
+
+// GetString returns the value at key if it is a string.
+func (r Record) GetString(key string) (string, bool) {
+	s, ok := r[key].(string)
+	return s, ok
+}
+
+// GetInt returns the value at key as an int64. PackStream decodes every
+// integer as int64, but other integer types and floats holding a whole
+// number (as produced by JSON or by values built in Go) are accepted too.
+func (r Record) GetInt(key string) (int64, bool) {
+	switch v := r[key].(type) {
+	case int64:
+		return v, true
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint8:
+		return int64(v), true
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			return int64(v), true
+		}
+	}
+	return 0, false
+}
+
+// GetFloat returns the value at key as a float64. Integers are widened, since
+// Cypher arithmetic such as sum() or avg() may yield either.
+func (r Record) GetFloat(key string) (float64, bool) {
+	switch v := r[key].(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	}
+	if i, ok := r.GetInt(key); ok {
+		return float64(i), true
+	}
+	return 0, false
+}
+
+// GetBool returns the value at key if it is a bool.
+func (r Record) GetBool(key string) (bool, bool) {
+	b, ok := r[key].(bool)
+	return b, ok
+}
+
+// GetMap returns the value at key if it is a map.
+func (r Record) GetMap(key string) (map[string]interface{}, bool) {
+	m, ok := r[key].(map[string]interface{})
+	return m, ok
+}
+
+// GetList returns the value at key if it is a list.
+func (r Record) GetList(key string) ([]interface{}, bool) {
+	l, ok := r[key].([]interface{})
+	return l, ok
+}
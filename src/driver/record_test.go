@@ -0,0 +1,74 @@
+package driver
+
+import "testing"
+
+func TestRecordGetters(t *testing.T) {
+	rec := Record{
+		"name":   "Alice",
+		"age":    int64(30),
+		"small":  int32(7),
+		"whole":  2.0,
+		"score":  4.5,
+		"active": true,
+		"props":  map[string]interface{}{"k": "v"},
+		"tags":   []interface{}{"a", "b"},
+		"null":   nil,
+	}
+
+	if v, ok := rec.GetString("name"); !ok || v != "Alice" {
+		t.Errorf("GetString(name) = %q, %v", v, ok)
+	}
+	if v, ok := rec.GetInt("age"); !ok || v != 30 {
+		t.Errorf("GetInt(age) = %d, %v", v, ok)
+	}
+	if v, ok := rec.GetInt("small"); !ok || v != 7 {
+		t.Errorf("GetInt(small) = %d, %v", v, ok)
+	}
+	if v, ok := rec.GetInt("whole"); !ok || v != 2 {
+		t.Errorf("GetInt(whole) = %d, %v", v, ok)
+	}
+	if v, ok := rec.GetFloat("score"); !ok || v != 4.5 {
+		t.Errorf("GetFloat(score) = %v, %v", v, ok)
+	}
+	if v, ok := rec.GetFloat("age"); !ok || v != 30 {
+		t.Errorf("GetFloat(age) = %v, %v", v, ok)
+	}
+	if v, ok := rec.GetBool("active"); !ok || !v {
+		t.Errorf("GetBool(active) = %v, %v", v, ok)
+	}
+	if v, ok := rec.GetMap("props"); !ok || v["k"] != "v" {
+		t.Errorf("GetMap(props) = %v, %v", v, ok)
+	}
+	if v, ok := rec.GetList("tags"); !ok || len(v) != 2 {
+		t.Errorf("GetList(tags) = %v, %v", v, ok)
+	}
+}
+
+func TestRecordGettersMissingOrMismatched(t *testing.T) {
+	rec := Record{"name": "Alice", "age": int64(30), "score": 4.5, "null": nil}
+
+	for _, key := range []string{"missing", "null", "age"} {
+		if _, ok := rec.GetString(key); ok {
+			t.Errorf("GetString(%s) should fail", key)
+		}
+	}
+	for _, key := range []string{"missing", "null", "name", "score"} {
+		if _, ok := rec.GetInt(key); ok {
+			t.Errorf("GetInt(%s) should fail", key)
+		}
+	}
+	for _, key := range []string{"missing", "null", "name"} {
+		if _, ok := rec.GetFloat(key); ok {
+			t.Errorf("GetFloat(%s) should fail", key)
+		}
+	}
+	if _, ok := rec.GetBool("name"); ok {
+		t.Error("GetBool(name) should fail")
+	}
+	if _, ok := rec.GetMap("name"); ok {
+		t.Error("GetMap(name) should fail")
+	}
+	if _, ok := rec.GetList("missing"); ok {
+		t.Error("GetList(missing) should fail")
+	}
+}
@@ -2,6 +2,10 @@ package driver
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
 	"strings"
 	"time"
 )
@@ -59,14 +63,18 @@ type StreamingResult struct {
 	conn       StreamConnection
 	keys       []string
 	currentRec *Record
-	peekedRec  *Record
-	hasPeeked  bool
+	peeked     []*Record // lookahead records buffered by Peek/PeekN, in order
 	summary    *ResultSummary
 	err        error
 	closed     bool
 	query      string
 	params     map[string]interface{}
 	startTime  time.Time
+	fetchSize  int
+
+	consumed         int64
+	progressFn       func(consumed int64)
+	progressInterval int64
 }
 
 func (r *StreamingResult) close() {
@@ -79,7 +87,12 @@ func (r *StreamingResult) close() {
 
 // StreamConnection defines the interface for streaming connections
 type StreamConnection interface {
-	// PullNext fetches the next record from the stream
+	// PullNext fetches the next record from the stream. batchSize is the number
+	// of records to request when a round-trip is needed; implementations buffer
+	// the surplus and serve it from subsequent calls. The stream ends with a
+	// summary, or with a nil record and nil summary when it has no summary to
+	// give (e.g. it was closed); an implementation must keep pulling rather
+	// than return that mid-stream.
 	PullNext(ctx context.Context, batchSize int) (*Record, *ResultSummary, error)
 	// GetKeys returns the column keys for this result stream
 	GetKeys() ([]string, error)
@@ -87,6 +100,18 @@ type StreamConnection interface {
 	Close() error
 }
 
+// discardableStreamConnection is implemented by stream connections that can
+// have the server drop the remaining records without transferring them.
+type discardableStreamConnection interface {
+	Discard(ctx context.Context) (*ResultSummary, error)
+}
+
+// resettableStreamConnection is implemented by stream connections that can
+// abort the running query server-side while keeping the connection usable.
+type resettableStreamConnection interface {
+	Reset(ctx context.Context) error
+}
+
 // NewStreamingResult creates a new streaming result
 func NewStreamingResult(conn StreamConnection, query string, params map[string]interface{}) *StreamingResult {
 	return &StreamingResult{
@@ -94,6 +119,50 @@ func NewStreamingResult(conn StreamConnection, query string, params map[string]i
 		query:     query,
 		params:    params,
 		startTime: time.Now(),
+		fetchSize: DefaultFetchSize,
+	}
+}
+
+// SetFetchSize overrides the number of records requested per round-trip.
+// Values <= 0 restore DefaultFetchSize.
+func (r *StreamingResult) SetFetchSize(n int) {
+	if n <= 0 {
+		n = DefaultFetchSize
+	}
+	r.fetchSize = n
+}
+
+// DefaultProgressInterval is the number of records between progress callbacks.
+const DefaultProgressInterval = 1000
+
+// SetProgressCallback registers fn to be called with the cumulative number of
+// records consumed every progress interval (see SetProgressInterval) while
+// iterating with Next, Collect or any method built on them. Nil removes it.
+func (r *StreamingResult) SetProgressCallback(fn func(consumed int64)) {
+	r.progressFn = fn
+}
+
+// SetProgressInterval sets how many records pass between progress callbacks.
+// Values <= 0 restore DefaultProgressInterval.
+func (r *StreamingResult) SetProgressInterval(n int64) {
+	if n <= 0 {
+		n = DefaultProgressInterval
+	}
+	r.progressInterval = n
+}
+
+// recordConsumed counts a record handed out by Next and reports progress.
+func (r *StreamingResult) recordConsumed() {
+	r.consumed++
+	if r.progressFn == nil {
+		return
+	}
+	interval := r.progressInterval
+	if interval <= 0 {
+		interval = DefaultProgressInterval
+	}
+	if r.consumed%interval == 0 {
+		r.progressFn(r.consumed)
 	}
 }
 
@@ -111,26 +180,33 @@ func (r *StreamingResult) Keys() ([]string, error) {
 }
 
 func (r *StreamingResult) Next(ctx context.Context) bool {
-	if r.err != nil || r.closed {
-		return false
+	// Records buffered by a peek come first, even if the stream has since ended
+	if len(r.peeked) > 0 {
+		r.currentRec = r.peeked[0]
+		r.peeked[0] = nil
+		r.peeked = r.peeked[1:]
+		r.recordConsumed()
+		return true
 	}
 
-	// If we have a peeked record, use it
-	if r.hasPeeked {
-		r.currentRec = r.peekedRec
-		r.peekedRec = nil
-		r.hasPeeked = false
-		return r.currentRec != nil
+	if r.err != nil || r.closed {
+		return false
 	}
 
 	// Fetch next record
-	r.currentRec, r.summary, r.err = r.conn.PullNext(ctx, 1)
+	r.currentRec, r.summary, r.err = r.conn.PullNext(ctx, r.fetchSize)
 	if r.err != nil || r.summary != nil {
 		r.close()
 		return false
 	}
 
-	return r.currentRec != nil && r.err == nil
+	if r.currentRec == nil {
+		// Ended without a summary; release the connection all the same.
+		r.close()
+		return false
+	}
+	r.recordConsumed()
+	return true
 }
 
 func (r *StreamingResult) NextRecord(ctx context.Context, record **Record) bool {
@@ -146,29 +222,57 @@ func (r *StreamingResult) Record() *Record {
 }
 
 func (r *StreamingResult) Peek(ctx context.Context) bool {
-	if r.err != nil || r.closed {
-		return false
-	}
-
-	if !r.hasPeeked {
-		r.peekedRec, r.summary, r.err = r.conn.PullNext(ctx, 1)
-		r.hasPeeked = true
-		if r.err != nil || r.summary != nil {
-			r.close()
-		}
-	}
-
-	return r.peekedRec != nil && r.err == nil
+	r.fillPeeked(ctx, 1)
+	return len(r.peeked) > 0
 }
 
 func (r *StreamingResult) PeekRecord(ctx context.Context, record **Record) bool {
 	hasPeek := r.Peek(ctx)
 	if record != nil {
-		*record = r.peekedRec
+		*record = nil
+		if hasPeek {
+			*record = r.peeked[0]
+		}
 	}
 	return hasPeek
 }
 
+// PeekN returns up to n upcoming records without consuming them; subsequent
+// calls to Next return them in order. The boolean reports whether n records
+// were available: it is false when the stream ended or failed first, in
+// which case the records that were buffered are still returned.
+func (r *StreamingResult) PeekN(ctx context.Context, n int) ([]*Record, bool) {
+	if n <= 0 {
+		return nil, true
+	}
+	r.fillPeeked(ctx, n)
+	count := n
+	if len(r.peeked) < count {
+		count = len(r.peeked)
+	}
+	records := make([]*Record, count)
+	copy(records, r.peeked)
+	return records, count == n
+}
+
+// fillPeeked pulls records into the lookahead buffer until it holds n or the
+// stream ends.
+func (r *StreamingResult) fillPeeked(ctx context.Context, n int) {
+	for len(r.peeked) < n && r.err == nil && !r.closed {
+		var rec *Record
+		rec, r.summary, r.err = r.conn.PullNext(ctx, r.fetchSize)
+		if r.err != nil || r.summary != nil {
+			r.close()
+			return
+		}
+		if rec == nil {
+			r.close()
+			return
+		}
+		r.peeked = append(r.peeked, rec)
+	}
+}
+
 func (r *StreamingResult) Err() error {
 	return r.err
 }
@@ -178,8 +282,29 @@ func (r *StreamingResult) Collect(ctx context.Context) ([]*Record, error) {
 		return nil, r.err
 	}
 
+	records, err := r.CollectPartial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// CollectPartial fetches all remaining records like Collect, but when the
+// stream fails part way, or ctx is cancelled, it returns the records fetched
+// so far together with the error instead of discarding them.
+func (r *StreamingResult) CollectPartial(ctx context.Context) ([]*Record, error) {
 	var records []*Record
-	for r.Next(ctx) {
+	// Records already buffered by a peek are kept even if the stream failed
+	// since.
+	for len(r.peeked) > 0 || r.err == nil {
+		if err := ctx.Err(); err != nil {
+			r.err = err
+			r.close()
+			break
+		}
+		if !r.Next(ctx) {
+			break
+		}
 		// Create a copy of the current record to avoid issues with reuse
 		recordCopy := make(Record)
 		for k, v := range *r.currentRec {
@@ -187,12 +312,64 @@ func (r *StreamingResult) Collect(ctx context.Context) ([]*Record, error) {
 		}
 		records = append(records, &recordCopy)
 	}
+	return records, r.err
+}
 
-	if r.err != nil {
-		return nil, r.err
+// CollectMap fetches all remaining records and groups them by the value of
+// keyField. Records sharing a key are appended in stream order. A record that
+// lacks keyField, or whose key value cannot be used as a map key, aborts the
+// collection with a UsageError and closes the stream.
+func (r *StreamingResult) CollectMap(ctx context.Context, keyField string) (map[interface{}][]*Record, error) {
+	records, err := r.Collect(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	return records, nil
+	grouped := make(map[interface{}][]*Record)
+	for _, record := range records {
+		key, exists := (*record)[keyField]
+		if !exists {
+			r.close()
+			return nil, NewUsageError(fmt.Sprintf("Record is missing key field %q", keyField))
+		}
+		if key != nil && !reflect.TypeOf(key).Comparable() {
+			r.close()
+			return nil, NewUsageError(fmt.Sprintf("Key field %q has unhashable type %T", keyField, key))
+		}
+		grouped[key] = append(grouped[key], record)
+	}
+
+	return grouped, nil
+}
+
+// WriteNDJSON writes each remaining record to w as one JSON object per line
+// and returns the number of records written. Records are encoded as they
+// arrive, so the result is never held in memory. If a record cannot be
+// encoded or written, the stream is closed and the count written so far is
+// returned with the error.
+func (r *StreamingResult) WriteNDJSON(ctx context.Context, w io.Writer) (int64, error) {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	var written int64
+	// Like CollectPartial, records buffered by a peek are written even if
+	// the stream failed since.
+	for len(r.peeked) > 0 || r.err == nil {
+		if err := ctx.Err(); err != nil {
+			r.err = err
+			r.close()
+			break
+		}
+		if !r.Next(ctx) {
+			break
+		}
+		if err := enc.Encode(r.currentRec); err != nil {
+			r.close()
+			return written, err
+		}
+		written++
+	}
+	return written, r.err
 }
 
 func (r *StreamingResult) Single(ctx context.Context) (*Record, error) {
@@ -220,6 +397,13 @@ func (r *StreamingResult) Single(ctx context.Context) (*Record, error) {
 }
 
 func (r *StreamingResult) Consume(ctx context.Context) (*ResultSummary, error) {
+	if discardable, ok := r.conn.(discardableStreamConnection); ok && !r.closed && r.err == nil {
+		// Skip the remaining records server-side rather than pulling them.
+		r.peeked = nil
+		r.summary, r.err = discardable.Discard(ctx)
+		r.close()
+	}
+
 	// Drain remaining records
 	for r.Next(ctx) {
 		// Just consume them
@@ -246,8 +430,25 @@ func (r *StreamingResult) Consume(ctx context.Context) (*ResultSummary, error) {
 	return r.summary, nil
 }
 
+// Cancel abandons the remaining records. When the underlying connection
+// supports it a RESET aborts the query on the server and the connection goes
+// back to the pool healthy; otherwise the stream is simply closed.
+func (r *StreamingResult) Cancel(ctx context.Context) error {
+	if r.closed {
+		return nil
+	}
+
+	var err error
+	if resettable, ok := r.conn.(resettableStreamConnection); ok {
+		err = resettable.Reset(ctx)
+	}
+	r.peeked = nil
+	r.close()
+	return err
+}
+
 func (r *StreamingResult) IsOpen() bool {
-	return !r.closed && r.summary == nil
+	return len(r.peeked) > 0 || (!r.closed && r.summary == nil)
 }
 
 // UsageError represents an error in how the Result is being used
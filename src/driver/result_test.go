@@ -1,9 +1,14 @@
 package driver
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
+
+	"github.com/seuros/gopher-cypher/src/bolt/messaging"
+	"github.com/seuros/gopher-cypher/src/driver/testserver"
 )
 
 // MockStreamConnection implements StreamConnection for testing
@@ -14,6 +19,9 @@ type MockStreamConnection struct {
 	index     int
 	closed    bool
 	pullCount int
+	// failErr, when set, is returned instead of the summary once the
+	// records run out.
+	failErr error
 }
 
 func NewMockStreamConnection(keys []string, records []*Record) *MockStreamConnection {
@@ -32,6 +40,9 @@ func (m *MockStreamConnection) PullNext(ctx context.Context, batchSize int) (*Re
 	m.pullCount++
 
 	if m.index >= len(m.records) {
+		if m.failErr != nil {
+			return nil, nil, m.failErr
+		}
 		// Return summary on exhaustion
 		if m.summary == nil {
 			m.summary = &ResultSummary{
@@ -225,6 +236,73 @@ func TestStreamingResult_Collect(t *testing.T) {
 	}
 }
 
+func TestStreamingResult_CollectPartial(t *testing.T) {
+	records := []*Record{{"id": 1}, {"id": 2}}
+	mockConn := NewMockStreamConnection([]string{"id"}, records)
+	mockConn.failErr = errors.New("connection lost")
+	result := NewStreamingResult(mockConn, "MATCH (n) RETURN n.id AS id", nil)
+
+	collected, err := result.CollectPartial(context.Background())
+	if err == nil || err.Error() != "connection lost" {
+		t.Fatalf("Expected the stream error, got %v", err)
+	}
+	if len(collected) != 2 || (*collected[0])["id"] != 1 || (*collected[1])["id"] != 2 {
+		t.Errorf("Expected both records before the failure, got %v", collected)
+	}
+	if !mockConn.closed {
+		t.Error("Expected connection to be closed after the failure")
+	}
+
+	// Collect keeps its all-or-nothing behaviour.
+	mockConn = NewMockStreamConnection([]string{"id"}, records)
+	mockConn.failErr = errors.New("connection lost")
+	result = NewStreamingResult(mockConn, "MATCH (n) RETURN n.id AS id", nil)
+	if collected, err := result.Collect(context.Background()); err == nil || collected != nil {
+		t.Errorf("Expected Collect to return nil records and an error, got %v, %v", collected, err)
+	}
+}
+
+func TestStreamingResult_CollectPartial_AfterPeek(t *testing.T) {
+	records := []*Record{{"id": 1}, {"id": 2}}
+	mockConn := NewMockStreamConnection([]string{"id"}, records)
+	mockConn.failErr = errors.New("connection lost")
+	result := NewStreamingResult(mockConn, "MATCH (n) RETURN n.id AS id", nil)
+
+	// Peeking past the end buffers both records and hits the failure.
+	if peeked, ok := result.PeekN(context.Background(), 3); ok || len(peeked) != 2 {
+		t.Fatalf("Expected two peeked records and a short read, got %v, %v", peeked, ok)
+	}
+	if result.Err() == nil {
+		t.Fatal("Expected the stream error to be recorded by PeekN")
+	}
+
+	collected, err := result.CollectPartial(context.Background())
+	if err == nil || err.Error() != "connection lost" {
+		t.Fatalf("Expected the stream error, got %v", err)
+	}
+	if len(collected) != 2 || (*collected[0])["id"] != 1 || (*collected[1])["id"] != 2 {
+		t.Errorf("Expected the peeked records, got %v", collected)
+	}
+}
+
+func TestStreamingResult_CollectPartial_Cancelled(t *testing.T) {
+	mockConn := NewMockStreamConnection([]string{"id"}, []*Record{{"id": 1}})
+	result := NewStreamingResult(mockConn, "MATCH (n) RETURN n.id AS id", nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	collected, err := result.CollectPartial(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if len(collected) != 0 {
+		t.Errorf("Expected no records, got %d", len(collected))
+	}
+	if !mockConn.closed {
+		t.Error("Expected connection to be closed after cancellation")
+	}
+}
+
 func TestStreamingResult_Single(t *testing.T) {
 	// Test with exactly one record
 	keys := []string{"result"}
@@ -353,6 +431,68 @@ func TestStreamingResult_Peek(t *testing.T) {
 	}
 }
 
+func TestStreamingResult_PeekN(t *testing.T) {
+	records := []*Record{{"num": 1}, {"num": 2}, {"num": 3}, {"num": 4}, {"num": 5}}
+	mockConn := NewMockStreamConnection([]string{"num"}, records)
+	result := NewStreamingResult(mockConn, "UNWIND range(1, 5) AS num RETURN num", nil)
+	ctx := context.Background()
+
+	peeked, ok := result.PeekN(ctx, 3)
+	if !ok || len(peeked) != 3 {
+		t.Fatalf("Expected 3 peeked records, got %d (ok=%v)", len(peeked), ok)
+	}
+	for i, rec := range peeked {
+		if (*rec)["num"] != i+1 {
+			t.Errorf("Peeked record %d should be %d, got %v", i, i+1, (*rec)["num"])
+		}
+	}
+
+	// Peeking again must not pull more than needed or reorder the buffer.
+	if again, _ := result.PeekN(ctx, 2); (*again[0])["num"] != 1 || (*again[1])["num"] != 2 {
+		t.Errorf("Repeated PeekN should return the same records, got %v", again)
+	}
+	if mockConn.pullCount != 3 {
+		t.Errorf("Expected 3 pulls, got %d", mockConn.pullCount)
+	}
+
+	for want := 1; want <= 5; want++ {
+		if !result.Next(ctx) {
+			t.Fatalf("Next() returned false before record %d", want)
+		}
+		if got := (*result.Record())["num"]; got != want {
+			t.Errorf("Expected record %d, got %v", want, got)
+		}
+	}
+	if result.Next(ctx) {
+		t.Error("Expected stream to be exhausted")
+	}
+}
+
+func TestStreamingResult_PeekN_PastEnd(t *testing.T) {
+	mockConn := NewMockStreamConnection([]string{"num"}, []*Record{{"num": 1}, {"num": 2}})
+	result := NewStreamingResult(mockConn, "UNWIND [1, 2] AS num RETURN num", nil)
+	ctx := context.Background()
+
+	peeked, ok := result.PeekN(ctx, 3)
+	if ok || len(peeked) != 2 {
+		t.Fatalf("Expected 2 records and ok=false, got %d (ok=%v)", len(peeked), ok)
+	}
+	if !result.IsOpen() {
+		t.Error("Result should stay open while buffered records remain")
+	}
+
+	var got []interface{}
+	for result.Next(ctx) {
+		got = append(got, (*result.Record())["num"])
+	}
+	if len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Errorf("Expected buffered records [1 2], got %v", got)
+	}
+	if result.IsOpen() {
+		t.Error("Result should be closed once the buffer is drained")
+	}
+}
+
 func TestStreamingResult_Keys_ClosesConnectionOnError(t *testing.T) {
 	mockConn := &KeysErrStreamConnection{err: errors.New("keys failed")}
 	result := NewStreamingResult(mockConn, "RETURN 1", nil)
@@ -365,3 +505,285 @@ func TestStreamingResult_Keys_ClosesConnectionOnError(t *testing.T) {
 		t.Error("Expected connection to be closed when Keys() fails")
 	}
 }
+
+// BatchingStreamConnection simulates the Bolt PULL behaviour: each round-trip
+// fetches up to batchSize records and buffers them for subsequent calls.
+type BatchingStreamConnection struct {
+	records    []*Record
+	index      int
+	pending    []*Record
+	roundTrips int
+	closed     bool
+}
+
+func (m *BatchingStreamConnection) GetKeys() ([]string, error) {
+	return []string{"num"}, nil
+}
+
+func (m *BatchingStreamConnection) PullNext(ctx context.Context, batchSize int) (*Record, *ResultSummary, error) {
+	if len(m.pending) == 0 {
+		if m.index >= len(m.records) {
+			return nil, &ResultSummary{}, nil
+		}
+		m.roundTrips++
+		end := m.index + batchSize
+		if end > len(m.records) {
+			end = len(m.records)
+		}
+		m.pending = append(m.pending, m.records[m.index:end]...)
+		m.index = end
+	}
+	record := m.pending[0]
+	m.pending = m.pending[1:]
+	return record, nil, nil
+}
+
+func (m *BatchingStreamConnection) Close() error {
+	m.closed = true
+	return nil
+}
+
+func TestStreamingResult_FetchSizeBatchesPulls(t *testing.T) {
+	records := make([]*Record, 25)
+	for i := range records {
+		records[i] = &Record{"num": i}
+	}
+
+	tests := []struct {
+		fetchSize  int
+		roundTrips int
+	}{
+		{fetchSize: 1, roundTrips: 25},
+		{fetchSize: 10, roundTrips: 3},
+		{fetchSize: 0, roundTrips: 1}, // falls back to DefaultFetchSize
+	}
+
+	for _, tt := range tests {
+		mockConn := &BatchingStreamConnection{records: records}
+		result := NewStreamingResult(mockConn, "UNWIND range(0, 24) AS num RETURN num", nil)
+		result.SetFetchSize(tt.fetchSize)
+
+		collected, err := result.Collect(context.Background())
+		if err != nil {
+			t.Fatalf("Collect() failed: %v", err)
+		}
+		if len(collected) != len(records) {
+			t.Fatalf("Expected %d records, got %d", len(records), len(collected))
+		}
+		if mockConn.roundTrips != tt.roundTrips {
+			t.Errorf("fetchSize %d: expected %d round-trips, got %d", tt.fetchSize, tt.roundTrips, mockConn.roundTrips)
+		}
+	}
+}
+
+func TestRunStream_PullRequestsFetchSize(t *testing.T) {
+	srv, err := testserver.New()
+	if err != nil {
+		t.Fatalf("failed to start test server: %v", err)
+	}
+	defer srv.Close()
+
+	const query = "UNWIND range(0, 24) AS num RETURN num"
+	rows := make([][]interface{}, 25)
+	for i := range rows {
+		rows[i] = []interface{}{i}
+	}
+	srv.Handle(query, testserver.Result{Fields: []string{"num"}, Records: rows})
+
+	config := DefaultConfig()
+	config.FetchSize = 10
+	d, err := NewDriverWithConfig(srv.URL(), config)
+	if err != nil {
+		t.Fatalf("failed to create driver: %v", err)
+	}
+	defer d.Close()
+
+	result, err := d.(StreamingDriver).RunStream(context.Background(), query, nil, nil)
+	if err != nil {
+		t.Fatalf("RunStream failed: %v", err)
+	}
+	collected, err := result.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if len(collected) != len(rows) {
+		t.Fatalf("expected %d records, got %d", len(rows), len(collected))
+	}
+
+	var sizes []int64
+	for _, msg := range srv.Requests() {
+		if msg.Signature() != messaging.PullSignature {
+			continue
+		}
+		meta, _ := msg.Fields()[0].(map[string]interface{})
+		n, _ := meta["n"].(int64)
+		sizes = append(sizes, n)
+	}
+	if len(sizes) != 3 {
+		t.Fatalf("expected 3 PULLs for 25 records in batches of 10, got %v", sizes)
+	}
+	for _, n := range sizes {
+		if n != 10 {
+			t.Errorf("expected every PULL to request n=10, got %v", sizes)
+			break
+		}
+	}
+}
+
+func TestStreamingResult_CollectMap(t *testing.T) {
+	keys := []string{"type", "name"}
+	records := []*Record{
+		{"type": "fruit", "name": "apple"},
+		{"type": "vegetable", "name": "carrot"},
+		{"type": "fruit", "name": "pear"},
+	}
+
+	mockConn := NewMockStreamConnection(keys, records)
+	result := NewStreamingResult(mockConn, "MATCH (n) RETURN n.type AS type, n.name AS name", nil)
+
+	grouped, err := result.CollectMap(context.Background(), "type")
+	if err != nil {
+		t.Fatalf("CollectMap() failed: %v", err)
+	}
+
+	if len(grouped) != 2 {
+		t.Fatalf("Expected 2 groups, got %d: %v", len(grouped), grouped)
+	}
+	fruit := grouped["fruit"]
+	if len(fruit) != 2 || (*fruit[0])["name"] != "apple" || (*fruit[1])["name"] != "pear" {
+		t.Errorf("Unexpected fruit group: %v", fruit)
+	}
+	vegetable := grouped["vegetable"]
+	if len(vegetable) != 1 || (*vegetable[0])["name"] != "carrot" {
+		t.Errorf("Unexpected vegetable group: %v", vegetable)
+	}
+	if !mockConn.closed {
+		t.Error("Expected connection to be closed after CollectMap()")
+	}
+}
+
+func TestStreamingResult_CollectMap_MissingKey(t *testing.T) {
+	records := []*Record{
+		{"type": "fruit", "name": "apple"},
+		{"name": "mystery"},
+	}
+
+	mockConn := NewMockStreamConnection([]string{"type", "name"}, records)
+	result := NewStreamingResult(mockConn, "MATCH (n) RETURN n", nil)
+
+	_, err := result.CollectMap(context.Background(), "type")
+	var usageErr *UsageError
+	if !errors.As(err, &usageErr) {
+		t.Fatalf("Expected UsageError for missing key, got %v", err)
+	}
+}
+
+func TestStreamingResult_WriteNDJSON(t *testing.T) {
+	records := []*Record{
+		{"id": int64(1), "name": "Alice"},
+		{"id": int64(2), "name": "Bob & Co"},
+		{"id": int64(3), "name": nil},
+	}
+	mockConn := NewMockStreamConnection([]string{"id", "name"}, records)
+	result := NewStreamingResult(mockConn, "MATCH (n) RETURN n.id AS id, n.name AS name", nil)
+
+	var buf bytes.Buffer
+	n, err := result.WriteNDJSON(context.Background(), &buf)
+	if err != nil {
+		t.Fatalf("WriteNDJSON() failed: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("Expected 3 records written, got %d", n)
+	}
+
+	want := `{"id":1,"name":"Alice"}` + "\n" +
+		`{"id":2,"name":"Bob & Co"}` + "\n" +
+		`{"id":3,"name":null}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+	if !mockConn.closed {
+		t.Error("Expected connection to be closed after WriteNDJSON()")
+	}
+}
+
+func TestStreamingResult_WriteNDJSON_AfterPeek(t *testing.T) {
+	mockConn := NewMockStreamConnection([]string{"id"}, []*Record{{"id": 1}, {"id": 2}})
+	mockConn.failErr = errors.New("connection lost")
+	result := NewStreamingResult(mockConn, "MATCH (n) RETURN n.id AS id", nil)
+
+	if _, ok := result.PeekN(context.Background(), 3); ok || result.Err() == nil {
+		t.Fatal("Expected PeekN to run into the stream error")
+	}
+
+	var buf bytes.Buffer
+	n, err := result.WriteNDJSON(context.Background(), &buf)
+	if err == nil || err.Error() != "connection lost" {
+		t.Fatalf("Expected the stream error, got %v", err)
+	}
+	if n != 2 || buf.String() != `{"id":1}`+"\n"+`{"id":2}`+"\n" {
+		t.Errorf("Expected the peeked records to be written, got %d: %q", n, buf.String())
+	}
+}
+
+func TestStreamingResult_WriteNDJSON_EncodingError(t *testing.T) {
+	records := []*Record{
+		{"v": "ok"},
+		{"v": make(chan int)},
+		{"v": "never written"},
+	}
+	mockConn := NewMockStreamConnection([]string{"v"}, records)
+	result := NewStreamingResult(mockConn, "RETURN $v AS v", nil)
+
+	var buf bytes.Buffer
+	n, err := result.WriteNDJSON(context.Background(), &buf)
+	var typeErr *json.UnsupportedTypeError
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("Expected an encoding error, got %v", err)
+	}
+	if n != 1 || buf.String() != `{"v":"ok"}`+"\n" {
+		t.Errorf("Expected only the first record, got %d: %q", n, buf.String())
+	}
+	if !mockConn.closed {
+		t.Error("Expected connection to be closed after the encoding error")
+	}
+}
+
+func TestStreamingResult_ProgressCallback(t *testing.T) {
+	records := make([]*Record, 10)
+	for i := range records {
+		records[i] = &Record{"id": i}
+	}
+
+	result := NewStreamingResult(NewMockStreamConnection([]string{"id"}, records), "MATCH (n) RETURN n.id AS id", nil)
+
+	var calls []int64
+	result.SetProgressCallback(func(consumed int64) {
+		calls = append(calls, consumed)
+	})
+	result.SetProgressInterval(5)
+
+	collected, err := result.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if len(collected) != 10 {
+		t.Fatalf("Expected 10 records, got %d", len(collected))
+	}
+	if len(calls) != 2 || calls[0] != 5 || calls[1] != 10 {
+		t.Errorf("Expected progress callbacks at [5 10], got %v", calls)
+	}
+}
+
+func TestStreamingResult_EndWithoutSummaryReleasesConnection(t *testing.T) {
+	// A nil record without a summary ends the stream.
+	conn := &ErrStreamConnection{keys: []string{"n"}}
+	result := NewStreamingResult(conn, "RETURN 1 AS n", nil)
+
+	if result.Next(context.Background()) {
+		t.Fatal("expected no record")
+	}
+	if result.IsOpen() || !conn.closed {
+		t.Errorf("expected the stream closed, open=%v closed=%v", result.IsOpen(), conn.closed)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"net"
+	"syscall"
 	"time"
 )
 
@@ -88,8 +90,17 @@ func (p *RetryPolicy) CalculateDelay(attempt int) time.Duration {
 	return time.Duration(jittered)
 }
 
-// IsRetriable checks if an error should trigger a retry.
+// IsRetriable checks if an error should trigger a retry. It is the same
+// check as IsRetriableError.
 func IsRetriable(err error) bool {
+	return IsRetriableError(err)
+}
+
+// IsRetriableError reports whether err should trigger a retry: a transient
+// server error, or a transport failure such as the connection being reset
+// or closed before the response arrived. Context cancellation and deadlines
+// are never retriable.
+func IsRetriableError(err error) bool {
 	if err == nil {
 		return false
 	}
@@ -105,6 +116,15 @@ func IsRetriable(err error) bool {
 		return false
 	}
 
+	// Transport errors that kept their cause
+	if isBrokenConnError(err) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
 	// Network/connection errors are generally retriable
 	errMsg := err.Error()
 	if contains(errMsg, "connection refused", "connection reset", "broken pipe",
@@ -169,7 +189,7 @@ func Retry[T any](ctx context.Context, policy *RetryPolicy, fn func() (T, error)
 		lastErr = err
 
 		// Check if retriable
-		if !IsRetriable(err) {
+		if !IsRetriableError(err) {
 			if policy.OnFailure != nil {
 				policy.OnFailure(err, attempt)
 			}
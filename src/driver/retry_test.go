@@ -3,6 +3,11 @@ package driver
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
 	"testing"
 	"time"
 )
@@ -115,6 +120,48 @@ func TestDatabaseError_IsRetriable(t *testing.T) {
 	}
 }
 
+func TestIsRetriableError(t *testing.T) {
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: &os.SyscallError{Syscall: "read", Err: syscall.ECONNRESET}}
+
+	tests := []struct {
+		name      string
+		err       error
+		retriable bool
+	}{
+		{"unexpected EOF", io.ErrUnexpectedEOF, true},
+		{"wrapped unexpected EOF", fmt.Errorf("error reading chunk data: %w", io.ErrUnexpectedEOF), true},
+		{"connection reset", reset, true},
+		{"wrapped connection reset", fmt.Errorf("RUN failed: %w", reset), true},
+		{"transient database error", &DatabaseError{Code: "Neo.TransientError.General.DatabaseUnavailable"}, true},
+		{"client database error", &DatabaseError{Code: "Neo.ClientError.Statement.SyntaxError"}, false},
+		{"canceled", context.Canceled, false},
+		{"usage error", NewUsageError("bad input"), false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetriableError(tt.err); got != tt.retriable {
+				t.Errorf("IsRetriableError(%v) = %v, want %v", tt.err, got, tt.retriable)
+			}
+		})
+	}
+}
+
+func TestRetry_RetriesTransportError(t *testing.T) {
+	attempts := 0
+	_, err := Retry(context.Background(), &RetryPolicy{MaxAttempts: 3}, func() (int, error) {
+		attempts++
+		if attempts == 1 {
+			return 0, fmt.Errorf("error reading chunk data: %w", io.ErrUnexpectedEOF)
+		}
+		return 1, nil
+	})
+	if err != nil || attempts != 2 {
+		t.Errorf("expected a retry after the transport error, got %v after %d attempts", err, attempts)
+	}
+}
+
 func TestRetry_Success(t *testing.T) {
 	ctx := context.Background()
 	policy := &RetryPolicy{MaxAttempts: 3}
@@ -0,0 +1,163 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/seuros/gopher-cypher/src/bolt/messaging"
+	"github.com/seuros/gopher-cypher/src/connection_url_resolver"
+)
+
+// newRouteMessage builds a ROUTE request for the resolver's database, carrying
+// the routing context (policy, region, ...) taken from the connection URL.
+func newRouteMessage(urlResolver *connection_url_resolver.ConnectionUrlResolver, bookmarks []interface{}) *messaging.Route {
+	extra := make(map[string]interface{})
+	if cfg := urlResolver.ToHash(); cfg != nil && cfg.Database != "" {
+		extra["db"] = cfg.Database
+	}
+	return messaging.NewRouteWithExtra(urlResolver.RoutingContext(), bookmarks, extra)
+}
+
+// routingTable is the cluster topology returned by ROUTE. It may be used
+// until expiresAt, after which it has to be fetched again.
+type routingTable struct {
+	database  string
+	routers   []string
+	readers   []string
+	writers   []string
+	ttl       time.Duration
+	expiresAt time.Time
+}
+
+// parseRoutingTable reads the "rt" entry of a ROUTE SUCCESS, fetched at now.
+func parseRoutingTable(metadata map[string]interface{}, now time.Time) (*routingTable, error) {
+	rt, ok := metadata["rt"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("ROUTE response has no routing table")
+	}
+
+	var ttl int64
+	switch v := rt["ttl"].(type) {
+	case int64:
+		ttl = v
+	case int:
+		ttl = int64(v)
+	default:
+		return nil, fmt.Errorf("ROUTE response has invalid ttl %v", rt["ttl"])
+	}
+
+	table := &routingTable{ttl: time.Duration(ttl) * time.Second}
+	table.expiresAt = now.Add(table.ttl)
+	table.database, _ = rt["db"].(string)
+
+	servers, _ := rt["servers"].([]interface{})
+	for _, s := range servers {
+		server, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var addresses []string
+		list, _ := server["addresses"].([]interface{})
+		for _, a := range list {
+			if address, ok := a.(string); ok {
+				addresses = append(addresses, address)
+			}
+		}
+		switch server["role"] {
+		case "ROUTE":
+			table.routers = append(table.routers, addresses...)
+		case "READ":
+			table.readers = append(table.readers, addresses...)
+		case "WRITE":
+			table.writers = append(table.writers, addresses...)
+		}
+	}
+	return table, nil
+}
+
+// isStale reports whether the table has expired at now.
+func (t *routingTable) isStale(now time.Time) bool {
+	return t == nil || !now.Before(t.expiresAt)
+}
+
+// router caches the routing table, sending a new ROUTE once it has expired or
+// after a cluster error has invalidated it.
+type router struct {
+	mu    sync.Mutex
+	fetch func(ctx context.Context) (map[string]interface{}, error)
+	now   func() time.Time
+	table *routingTable
+}
+
+func newRouter(fetch func(ctx context.Context) (map[string]interface{}, error)) *router {
+	return &router{fetch: fetch, now: time.Now}
+}
+
+// routingTable returns the cached table, refreshing it first when stale.
+func (r *router) routingTable(ctx context.Context) (*routingTable, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	if !r.table.isStale(now) {
+		return r.table, nil
+	}
+
+	metadata, err := r.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	table, err := parseRoutingTable(metadata, now)
+	if err != nil {
+		return nil, err
+	}
+	r.table = table
+	return table, nil
+}
+
+// invalidate forces the next routingTable call to send ROUTE.
+func (r *router) invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.table = nil
+}
+
+// observe invalidates the table when err shows the cluster topology changed,
+// for example a write sent to a member that is no longer the leader.
+func (r *router) observe(err error) {
+	if r == nil || err == nil {
+		return
+	}
+	var dbErr *DatabaseError
+	if errors.As(err, &dbErr) && dbErr.IsClusterError() {
+		r.invalidate()
+	}
+}
+
+// route sends ROUTE on a pooled connection and returns the SUCCESS metadata.
+func (d *driver) route(ctx context.Context) (map[string]interface{}, error) {
+	pc, _, err := d.acquireConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryBolt, "Sending ROUTE message")
+	response, err := newRouteMessage(d.urlResolver, nil).SendLimit(pc.Conn, d.config.MaxMessageSize)
+	if err == nil {
+		switch msg := response.(type) {
+		case *messaging.Success:
+			d.netPool.Put(pc, nil)
+			return msg.Metadata(), nil
+		case *messaging.Failure:
+			err = &DatabaseError{Code: msg.Code(), Message: msg.Message()}
+			pc.markDirty()
+		default:
+			err = fmt.Errorf("unexpected ROUTE response type: 0x%02X", response.Signature())
+		}
+	}
+	d.netPool.Put(pc, err)
+	return nil, err
+}
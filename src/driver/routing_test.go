@@ -0,0 +1,145 @@
+package driver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/seuros/gopher-cypher/src/connection_url_resolver"
+	"github.com/seuros/gopher-cypher/src/driver/testserver"
+)
+
+func TestNewRouteMessage_RoutingContextFromURL(t *testing.T) {
+	resolver := connection_url_resolver.NewConnectionUrlResolver("neo4j://host/db?policy=eu&region=west")
+	route := newRouteMessage(resolver, nil)
+
+	routing := route.Metadata()
+	if routing["policy"] != "eu" {
+		t.Errorf("Expected policy 'eu', got %v", routing["policy"])
+	}
+	if routing["region"] != "west" {
+		t.Errorf("Expected region 'west', got %v", routing["region"])
+	}
+	if routing["address"] != "host:7687" {
+		t.Errorf("Expected address 'host:7687', got %v", routing["address"])
+	}
+
+	if db := route.Extra()["db"]; db != "db" {
+		t.Errorf("Expected db 'db' in ROUTE extra, got %v", db)
+	}
+	if fields := route.Fields(); len(fields) != 3 {
+		t.Errorf("Expected 3 ROUTE fields, got %d", len(fields))
+	}
+}
+
+func fakeRouteResponse(ttl int64, writer string) map[string]interface{} {
+	return map[string]interface{}{
+		"rt": map[string]interface{}{
+			"ttl": ttl,
+			"db":  "neo4j",
+			"servers": []interface{}{
+				map[string]interface{}{"role": "ROUTE", "addresses": []interface{}{"r1:7687", "r2:7687"}},
+				map[string]interface{}{"role": "READ", "addresses": []interface{}{"r2:7687"}},
+				map[string]interface{}{"role": "WRITE", "addresses": []interface{}{writer}},
+			},
+		},
+	}
+}
+
+func TestRouter_RefreshesAfterTTL(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fetches := 0
+	r := newRouter(func(ctx context.Context) (map[string]interface{}, error) {
+		fetches++
+		return fakeRouteResponse(1, "w1:7687"), nil
+	})
+	r.now = func() time.Time { return now }
+
+	table, err := r.routingTable(context.Background())
+	if err != nil {
+		t.Fatalf("routingTable failed: %v", err)
+	}
+	if !table.expiresAt.Equal(now.Add(time.Second)) {
+		t.Errorf("Expected expiry one second after the fetch, got %v", table.expiresAt)
+	}
+	if len(table.routers) != 2 || len(table.readers) != 1 || table.writers[0] != "w1:7687" || table.database != "neo4j" {
+		t.Errorf("Unexpected routing table %+v", table)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if _, err := r.routingTable(context.Background()); err != nil {
+		t.Fatalf("routingTable failed: %v", err)
+	}
+	if fetches != 1 {
+		t.Errorf("Expected the cached table before expiry, got %d fetches", fetches)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if _, err := r.routingTable(context.Background()); err != nil {
+		t.Fatalf("routingTable failed: %v", err)
+	}
+	if fetches != 2 {
+		t.Errorf("Expected a refresh after expiry, got %d fetches", fetches)
+	}
+}
+
+func TestRouter_InvalidatedByClusterError(t *testing.T) {
+	fetches := 0
+	r := newRouter(func(ctx context.Context) (map[string]interface{}, error) {
+		fetches++
+		return fakeRouteResponse(300, "w1:7687"), nil
+	})
+
+	if _, err := r.routingTable(context.Background()); err != nil {
+		t.Fatalf("routingTable failed: %v", err)
+	}
+	r.observe(&DatabaseError{Code: "Neo.ClientError.Statement.SyntaxError", Message: "bad"})
+	if _, err := r.routingTable(context.Background()); err != nil {
+		t.Fatalf("routingTable failed: %v", err)
+	}
+	if fetches != 1 {
+		t.Fatalf("Expected non-cluster errors to keep the table, got %d fetches", fetches)
+	}
+
+	r.observe(&DatabaseError{Code: "Neo.ClientError.Cluster.NotALeader", Message: "No write operations are allowed"})
+	if _, err := r.routingTable(context.Background()); err != nil {
+		t.Fatalf("routingTable failed: %v", err)
+	}
+	if fetches != 2 {
+		t.Errorf("Expected a cluster error to force a refresh, got %d fetches", fetches)
+	}
+}
+
+func TestParseRoutingTable_Invalid(t *testing.T) {
+	if _, err := parseRoutingTable(map[string]interface{}{}, time.Now()); err == nil {
+		t.Error("Expected an error for a response without rt")
+	}
+	if _, err := parseRoutingTable(map[string]interface{}{"rt": map[string]interface{}{}}, time.Now()); err == nil {
+		t.Error("Expected an error for a missing ttl")
+	}
+}
+
+func TestDriver_RouteAgainstServer(t *testing.T) {
+	srv, err := testserver.New()
+	if err != nil {
+		t.Fatalf("failed to start test server: %v", err)
+	}
+	defer srv.Close()
+
+	d, err := NewDriverWithConfig(srv.URL(), nil)
+	if err != nil {
+		t.Fatalf("failed to create driver: %v", err)
+	}
+	defer d.Close()
+
+	table, err := d.(*driver).router.routingTable(context.Background())
+	if err != nil {
+		t.Fatalf("routingTable failed: %v", err)
+	}
+	if len(table.writers) != 1 || table.writers[0] != srv.Addr() {
+		t.Errorf("Expected the server as writer, got %v", table.writers)
+	}
+	if table.ttl != 300*time.Second {
+		t.Errorf("Expected a 300s ttl, got %v", table.ttl)
+	}
+}
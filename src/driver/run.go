@@ -23,7 +23,7 @@ func (d *driver) ensureAuthenticated(conn net.Conn) (*pooledConn, error) {
 	// Liveness check for already-authenticated connections
 	if d.config.ConnectionPool.EnableLivenessCheck && pc.isAuthenticated() {
 		if !pc.isAlive() {
-			d.logger.Warn("Pooled connection dead, discarding")
+			logEvent(d.logger, d.config.Logging, LogLevelWarn, LogCategoryConnection, "Pooled connection dead, discarding")
 			// Mark as bad and get a fresh one
 			d.netPool.Put(conn, errors.New("connection dead"))
 
@@ -40,47 +40,55 @@ func (d *driver) ensureAuthenticated(conn net.Conn) (*pooledConn, error) {
 
 	// Skip handshake if connection is still authenticated and not idle too long
 	if !pc.needsReauth(d.config.ConnectionPool.MaxIdleTime) {
-		if d.config.Logging != nil && d.config.Logging.LogConnectionPool {
-			d.logger.Debug("Reusing authenticated connection", "idle_time", pc.idleTime())
-		}
+		logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryConnection, "Reusing authenticated connection", "idle_time", pc.idleTime())
 		pc.touch()
 		return pc, nil
 	}
 
-	// Need full handshake
-	if d.config.Logging != nil && d.config.Logging.LogBoltMessages {
-		d.logger.Debug("Performing Bolt handshake")
+	// Need full handshake, which always starts uncompressed.
+	if cc, ok := pc.Conn.(*compressedConn); ok {
+		pc.Conn = cc.Conn
 	}
+	logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryBolt, "Performing Bolt handshake")
 
 	major, minor, err := boltutil.CheckVersion(pc.Conn)
 	if err != nil {
-		d.logger.Error("Bolt version check failed", "error", err)
+		logEvent(d.logger, d.config.Logging, LogLevelError, LogCategoryBolt, "Bolt version check failed", "error", err)
 		return nil, err
 	}
 
-	if d.config.Logging != nil && d.config.Logging.LogBoltMessages {
-		d.logger.Debug("Bolt version negotiated", "major", major, "minor", minor)
-	}
+	logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryBolt, "Bolt version negotiated", "major", major, "minor", minor)
 
-	err = boltutil.SendHello(pc.Conn)
+	helloExtra := d.config.NotificationFilter.metadata()
+	if d.config.EnableCompression {
+		for k, v := range compressionMetadata() {
+			helloExtra[k] = v
+		}
+	}
+	helloMetadata, err := boltutil.Hello(pc.Conn, helloExtra, d.config.MaxMessageSize)
 	if err != nil {
-		d.logger.Error("HELLO message failed", "error", err)
+		logEvent(d.logger, d.config.Logging, LogLevelError, LogCategoryBolt, "HELLO message failed", "error", err)
 		return nil, err
 	}
 
-	if d.config.Logging != nil && d.config.Logging.LogBoltMessages {
-		d.logger.Debug("HELLO message successful")
+	logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryBolt, "HELLO message successful")
+
+	if d.config.EnableCompression {
+		if codec := negotiatedCompression(helloMetadata); codec != "" {
+			pc.Conn = newCompressedConn(pc.Conn, d.config.MaxMessageSize)
+			logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryBolt, "Message compression enabled", "codec", codec)
+		} else {
+			logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryBolt, "Server declined message compression")
+		}
 	}
 
-	err = boltutil.Authenticate(pc.Conn, d.urlResolver)
+	err = boltutil.Authenticate(pc.Conn, d.urlResolver, d.config.MaxMessageSize)
 	if err != nil {
-		d.logger.Error("Authentication failed", "error", err)
+		logEvent(d.logger, d.config.Logging, LogLevelError, LogCategoryAuth, "Authentication failed", "error", err)
 		return nil, err
 	}
 
-	if d.config.Logging != nil && d.config.Logging.LogBoltMessages {
-		d.logger.Debug("Authentication successful")
-	}
+	logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryAuth, "Authentication successful")
 
 	pc.markAuthenticated(major, minor)
 	return pc, nil
@@ -92,13 +100,18 @@ func (d *driver) Run(ctx context.Context, query string, params map[string]interf
 }
 
 func (d *driver) RunWithContext(ctx context.Context, query string, params map[string]interface{}, metaData map[string]interface{}) ([]string, []map[string]interface{}, *ResultSummary, error) {
+	query, params, err := d.prepare(ctx, query, params)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	startTime := time.Now()
 
 	// Log query execution start
 	if d.config.Logging != nil && d.config.Logging.LogQueryTiming {
-		d.logger.Info("Executing query", "query", query, "param_count", len(params))
+		logEvent(d.logger, d.config.Logging, LogLevelInfo, LogCategoryQuery, "Executing query", "query", query, "param_count", len(params))
 	} else {
-		d.logger.Debug("Executing query", "query", query, "params", params, "metadata", metaData)
+		logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryQuery, "Executing query", "query", query, "params", params, "metadata", metaData)
 	}
 
 	// Initialize summary
@@ -123,31 +136,10 @@ func (d *driver) RunWithContext(ctx context.Context, query string, params map[st
 		d.observability.recordConnectionEvent("connect", d.config.Observability, nil)
 	}
 
-	// init connection
-	if d.config.Logging != nil && d.config.Logging.LogConnectionPool {
-		d.logger.Debug("Acquiring connection from pool")
-	}
-
-	conn, err := d.netPool.Get()
-	if err != nil {
-		d.logger.Error("Failed to acquire connection from pool", "error", err)
-		if d.observability != nil && d.config.Observability != nil {
-			d.observability.recordConnectionEvent("connect", d.config.Observability, err)
-			d.observability.finishQuerySpan(spanCtx, summary, err, d.config.Observability)
-		}
-		return nil, nil, summary, err
-	}
-
-	if d.config.Logging != nil && d.config.Logging.LogConnectionPool {
-		d.logger.Debug("Connection acquired from pool")
-	}
-
-	// Ensure connection is authenticated (with liveness check and conditional handshake)
-	pc, err := d.ensureAuthenticated(conn)
+	pc, stage, err := d.acquireConn(ctx)
 	if err != nil {
-		d.netPool.Put(conn, err)
 		if d.observability != nil && d.config.Observability != nil {
-			d.observability.recordConnectionEvent("authenticate", d.config.Observability, err)
+			d.observability.recordConnectionEvent(stage, d.config.Observability, err)
 			d.observability.finishQuerySpan(spanCtx, summary, err, d.config.Observability)
 		}
 		return nil, nil, summary, err
@@ -158,12 +150,19 @@ func (d *driver) RunWithContext(ctx context.Context, query string, params map[st
 		d.observability.recordConnectionEvent("authenticate", d.config.Observability, nil)
 	}
 
-	if d.config.Logging != nil && d.config.Logging.LogBoltMessages {
-		d.logger.Debug("Sending RUN message", "query_type", summary.QueryType)
-	}
+	logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryBolt, "Sending RUN message", "query_type", summary.QueryType)
 
-	runMessage := messaging.NewRun(query, params, metaData)
-	cols, rows, queryErr := runMessage.Send(pc.Conn)
+	runMessage := messaging.NewRun(query, d.runParams(params), d.runMetadata(metaData))
+	cols, rows, successMeta, queryErr := runMessage.SendWithSummaryLimit(pc.Conn, d.config.MaxMessageSize)
+	if bookmark, ok := successMeta["bookmark"].(string); ok {
+		summary.Bookmark = bookmark
+	}
+	summary.updateFromTimings(successMeta)
+	for _, row := range rows {
+		for key, value := range row {
+			row[key] = decodeGraphValue(value)
+		}
+	}
 
 	// Complete summary
 	summary.ExecutionTime = time.Since(startTime)
@@ -174,17 +173,18 @@ func (d *driver) RunWithContext(ctx context.Context, query string, params map[st
 
 	// Log query completion
 	if queryErr != nil {
-		d.logger.Error("Query execution failed", "error", queryErr, "duration", summary.ExecutionTime)
+		logEvent(d.logger, d.config.Logging, LogLevelError, LogCategoryQuery, "Query execution failed", "error", queryErr, "duration", summary.ExecutionTime)
 		pc.markDirty()
+		d.router.observe(queryErr)
 	} else {
 		if d.config.Logging != nil && d.config.Logging.LogQueryTiming {
-			d.logger.Info("Query completed", "duration", summary.ExecutionTime, "records", summary.RecordsConsumed, "query_type", summary.QueryType)
+			logEvent(d.logger, d.config.Logging, LogLevelInfo, LogCategoryQuery, "Query completed", "duration", summary.ExecutionTime, "records", summary.RecordsConsumed, "query_type", summary.QueryType)
 		} else {
-			d.logger.Debug("Query completed", "duration", summary.ExecutionTime, "records", summary.RecordsConsumed, "columns", len(cols))
+			logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryQuery, "Query completed", "duration", summary.ExecutionTime, "records", summary.RecordsConsumed, "columns", len(cols))
 		}
 	}
 
-	d.netPool.Put(conn, queryErr)
+	d.netPool.Put(pc, queryErr)
 
 	// Finish observability span
 	if d.observability != nil && d.config.Observability != nil {
@@ -215,13 +215,18 @@ func (d *driver) RunWithRetry(ctx context.Context, policy *RetryPolicy, query st
 
 // RunStream implements StreamingDriver interface for memory-efficient query processing
 func (d *driver) RunStream(ctx context.Context, query string, params map[string]interface{}, metaData map[string]interface{}) (Result, error) {
+	query, params, err := d.prepare(ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+
 	startTime := time.Now()
 
 	// Log query execution start
 	if d.config.Logging != nil && d.config.Logging.LogQueryTiming {
-		d.logger.Info("Executing streaming query", "query", query, "param_count", len(params))
+		logEvent(d.logger, d.config.Logging, LogLevelInfo, LogCategoryQuery, "Executing streaming query", "query", query, "param_count", len(params))
 	} else {
-		d.logger.Debug("Executing streaming query", "query", query, "params", params, "metadata", metaData)
+		logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryQuery, "Executing streaming query", "query", query, "params", params, "metadata", metaData)
 	}
 
 	// Initialize summary
@@ -246,36 +251,12 @@ func (d *driver) RunStream(ctx context.Context, query string, params map[string]
 		d.observability.recordConnectionEvent("connect", d.config.Observability, nil)
 	}
 
-	// Get connection from pool
-	if d.config.Logging != nil && d.config.Logging.LogConnectionPool {
-		d.logger.Debug("Acquiring connection from pool for streaming")
-	}
-
-	conn, err := d.netPool.Get()
+	// Acquire an authenticated connection. We don't defer Put() here because
+	// the streaming connection keeps it until the result is consumed.
+	pc, stage, err := d.acquireConn(ctx)
 	if err != nil {
-		// Return connection to pool even on Get() error - pool may have allocated resources
-		d.netPool.Put(conn, err)
-		d.logger.Error("Failed to acquire connection from pool", "error", err)
 		if d.observability != nil && d.config.Observability != nil {
-			d.observability.recordConnectionEvent("connect", d.config.Observability, err)
-			d.observability.finishQuerySpan(spanCtx, summary, err, d.config.Observability)
-		}
-		return nil, err
-	}
-
-	// Note: We don't defer Put() here because the streaming connection needs to keep
-	// the connection alive until the result is consumed
-
-	if d.config.Logging != nil && d.config.Logging.LogConnectionPool {
-		d.logger.Debug("Connection acquired from pool for streaming")
-	}
-
-	// Ensure connection is authenticated (with liveness check and conditional handshake)
-	pc, err := d.ensureAuthenticated(conn)
-	if err != nil {
-		d.netPool.Put(conn, err)
-		if d.observability != nil && d.config.Observability != nil {
-			d.observability.recordConnectionEvent("authenticate", d.config.Observability, err)
+			d.observability.recordConnectionEvent(stage, d.config.Observability, err)
 			d.observability.finishQuerySpan(spanCtx, summary, err, d.config.Observability)
 		}
 		return nil, err
@@ -291,8 +272,8 @@ func (d *driver) RunStream(ctx context.Context, query string, params map[string]
 		conn:          pc,
 		netPool:       d.netPool,
 		query:         query,
-		params:        params,
-		metaData:      metaData,
+		params:        d.runParams(params),
+		metaData:      d.runMetadata(metaData),
 		logger:        d.logger,
 		config:        d.config,
 		observability: d.observability,
@@ -300,16 +281,25 @@ func (d *driver) RunStream(ctx context.Context, query string, params map[string]
 		summary:       summary,
 		startTime:     startTime,
 	}
+	if d.config.AutoReconnectReads && summary.QueryType == queryTypeRead {
+		streamConn.reconnect = func(ctx context.Context) (*pooledConn, error) {
+			pc, _, err := d.acquireConn(ctx)
+			return pc, err
+		}
+	}
 
 	// Send RUN message and get keys
 	err = streamConn.sendRun(ctx)
 	if err != nil {
+		d.router.observe(err)
 		_ = streamConn.Close()
 		return nil, err
 	}
+	streamConn.startIdleWatchdog(d.config.StreamIdleTimeout)
 
 	// Create streaming result
 	result := NewStreamingResult(streamConn, query, params)
+	result.fetchSize = d.config.FetchSize
 
 	return result, nil
 }
@@ -324,16 +314,12 @@ func (d *driver) RunReactive(ctx context.Context, query string, params map[strin
 
 	// Create reactive configuration
 	config := DefaultReactiveConfig()
-	if d.config.Logging != nil {
-		d.logger.Debug("Creating reactive result", "query", query, "buffer_size", config.BufferSize)
-	}
+	logEvent(d.logger, d.config.Logging, LogLevelDebug, LogCategoryReactive, "Creating reactive result", "query", query, "buffer_size", config.BufferSize)
 
 	// Wrap streaming result in reactive interface
 	reactiveResult := NewReactiveResult(streamingResult, query, params, config)
 
-	if d.config.Logging != nil && d.config.Logging.LogQueryTiming {
-		d.logger.Info("Reactive query initialized", "query_type", inferQueryType(query))
-	}
+	logEvent(d.logger, d.config.Logging, LogLevelInfo, LogCategoryQuery, "Reactive query initialized", "query_type", inferQueryType(query))
 
 	return reactiveResult, nil
 }
@@ -2,11 +2,15 @@ package driver
 
 import (
 	"context"
+	"errors"
 	"net"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/seuros/gopher-cypher/src/bolt/messaging"
 	"github.com/seuros/gopher-cypher/src/connection_url_resolver"
+	"github.com/seuros/gopher-cypher/src/driver/testserver"
 	"github.com/seuros/gopher-cypher/src/internal/testutil"
 )
 
@@ -18,6 +22,44 @@ func dialOrSkip(t *testing.T, url string) {
 	}
 }
 
+func TestRun_MaxMessageSize(t *testing.T) {
+	srv, err := testserver.New()
+	if err != nil {
+		t.Fatalf("failed to start test server: %v", err)
+	}
+	defer srv.Close()
+
+	const query = "MATCH (d:Document) RETURN d.body AS blob"
+	blob := strings.Repeat("x", 8<<10)
+	srv.Handle(query, testserver.Result{Fields: []string{"blob"}, Records: [][]interface{}{{blob}}})
+
+	config := DefaultConfig()
+	config.MaxMessageSize = 4 << 10
+	d, err := NewDriverWithConfig(srv.URL(), config)
+	if err != nil {
+		t.Fatalf("failed to create driver: %v", err)
+	}
+	defer d.Close()
+
+	if _, _, err := d.Run(context.Background(), query, nil, nil); !errors.Is(err, messaging.ErrMessageTooLarge) {
+		t.Fatalf("Expected ErrMessageTooLarge for an 8 KiB record under a 4 KiB limit, got %v", err)
+	}
+
+	// The same record fits under the default limit.
+	d2, err := NewDriverWithConfig(srv.URL(), nil)
+	if err != nil {
+		t.Fatalf("failed to create driver: %v", err)
+	}
+	defer d2.Close()
+	_, rows, err := d2.Run(context.Background(), query, nil, nil)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(rows) != 1 || rows[0]["blob"] != blob {
+		t.Errorf("Expected the record back, got %d rows", len(rows))
+	}
+}
+
 func TestRunWithWrongAuth(t *testing.T) {
 	dialOrSkip(t, testutil.InvalidCredentialsURL())
 	_, err := NewDriver(testutil.InvalidCredentialsURL())
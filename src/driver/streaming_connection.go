@@ -2,16 +2,20 @@ package driver
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/seuros/gopher-cypher/src/bolt/messaging"
-	"github.com/yudhasubki/netpool"
 )
 
 // streamingConnectionWrapper implements StreamConnection interface
 type streamingConnectionWrapper struct {
 	conn          *pooledConn
-	netPool       *netpool.Netpool
+	netPool       *connPool
 	query         string
 	params        map[string]interface{}
 	metaData      map[string]interface{}
@@ -27,12 +31,90 @@ type streamingConnectionWrapper struct {
 	startTime     time.Time
 	lastErr       error
 	pending       []*Record
+
+	// reconnect, when set, acquires a fresh connection so a read whose
+	// connection breaks before any record was delivered can be re-run.
+	reconnect func(ctx context.Context) (*pooledConn, error)
+	delivered bool
+
+	// exchange is held for the duration of each request/response exchange.
+	exchange sync.Mutex
+
+	// idleTimer closes the stream after idleTimeout without a PULL.
+	idleTimer   *time.Timer
+	idleTimeout time.Duration
+	// idleClosed records that the watchdog, not the consumer, closed the
+	// stream; PullNext then reports lastErr or serves what was buffered.
+	idleClosed bool
+	closeMu    sync.Mutex
+}
+
+// startIdleWatchdog arms a timer that closes the stream once it has gone
+// timeout without a PullNext. A timeout <= 0 disables the watchdog.
+func (sc *streamingConnectionWrapper) startIdleWatchdog(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+	sc.idleTimeout = timeout
+	sc.idleTimer = time.AfterFunc(timeout, sc.idleExpired)
+}
+
+// idleExpired closes a stream the consumer abandoned. If an exchange is in
+// flight the stream is evidently still in use, so the timer is re-armed.
+func (sc *streamingConnectionWrapper) idleExpired() {
+	if !sc.exchange.TryLock() {
+		sc.idleTimer.Reset(sc.idleTimeout)
+		return
+	}
+	defer sc.exchange.Unlock()
+
+	if sc.isClosed() {
+		return
+	}
+	logEvent(sc.logger, sc.config.Logging, LogLevelWarn, LogCategoryStreaming, "Closing streaming result left idle without Close or Consume", "idle_timeout", sc.idleTimeout, "query_type", sc.summary.QueryType)
+	// A stream the server has finished only holds buffered records, which
+	// remain readable; an unfinished one ends with an error rather than
+	// looking complete.
+	if !sc.exhausted && sc.lastErr == nil {
+		sc.lastErr = NewUsageError(fmt.Sprintf("Streaming result closed after idle timeout of %v", sc.idleTimeout))
+	}
+	sc.idleClosed = true
+	_ = sc.Close()
+}
+
+// readMessage reads the next server message, enforcing Config.MaxMessageSize.
+func (sc *streamingConnectionWrapper) readMessage() (messaging.Message, error) {
+	return messaging.ReadChunkedMessageLimit(sc.conn.Conn, sc.config.MaxMessageSize)
+}
+
+func (sc *streamingConnectionWrapper) isClosed() bool {
+	sc.closeMu.Lock()
+	defer sc.closeMu.Unlock()
+	return sc.closed
+}
+
+// acquire claims the connection for one message exchange. Bolt serves one
+// request at a time, so a concurrent caller gets a UsageError rather than
+// interleaving its frames with the exchange in flight. The caller must
+// release sc.exchange when done.
+func (sc *streamingConnectionWrapper) acquire() error {
+	if !sc.exchange.TryLock() {
+		return NewUsageError("Streaming connection is already in use by another goroutine")
+	}
+	return nil
 }
 
 func (sc *streamingConnectionWrapper) sendRun(ctx context.Context) error {
-	if sc.config.Logging != nil && sc.config.Logging.LogBoltMessages {
-		sc.logger.Debug("Sending RUN message for streaming", "query_type", sc.summary.QueryType)
+	if err := sc.acquire(); err != nil {
+		return err
 	}
+	defer sc.exchange.Unlock()
+	return sc.run(ctx)
+}
+
+// run sends RUN and reads the field names. The caller holds sc.exchange.
+func (sc *streamingConnectionWrapper) run(ctx context.Context) error {
+	logEvent(sc.logger, sc.config.Logging, LogLevelDebug, LogCategoryBolt, "Sending RUN message for streaming", "query_type", sc.summary.QueryType)
 
 	// Send RUN message
 	runMessage := messaging.NewRun(sc.query, sc.params, sc.metaData)
@@ -51,7 +133,7 @@ func (sc *streamingConnectionWrapper) sendRun(ctx context.Context) error {
 	}
 
 	// Read SUCCESS response with field metadata
-	response, err := messaging.ReadChunkedMessage(sc.conn.Conn)
+	response, err := sc.readMessage()
 	if err != nil {
 		sc.lastErr = err
 		return err
@@ -71,6 +153,10 @@ func (sc *streamingConnectionWrapper) sendRun(ctx context.Context) error {
 		return usageErr
 	}
 
+	if response.Signature() == messaging.IgnoredSignature {
+		return sc.ignored("RUN")
+	}
+
 	if response.Signature() != messaging.SuccessSignature {
 		usageErr := NewUsageError("Unexpected response to RUN message")
 		sc.lastErr = usageErr
@@ -89,15 +175,14 @@ func (sc *streamingConnectionWrapper) sendRun(ctx context.Context) error {
 							sc.keys[i] = fieldStr
 						} else {
 							// Log type mismatch and use empty string to avoid panic
-							if sc.logger != nil {
-								sc.logger.Warn("Field name is not a string", "index", i, "type", field)
-							}
+							logEvent(sc.logger, sc.config.Logging, LogLevelWarn, LogCategoryBolt, "Field name is not a string", "index", i, "type", field)
 							sc.keys[i] = ""
 						}
 					}
 					sc.hasKeys = true
 				}
 			}
+			sc.summary.updateFromTimings(metadata)
 		}
 	}
 
@@ -118,21 +203,108 @@ func (sc *streamingConnectionWrapper) GetKeys() ([]string, error) {
 }
 
 func (sc *streamingConnectionWrapper) PullNext(ctx context.Context, batchSize int) (*Record, *ResultSummary, error) {
-	if sc.exhausted || sc.closed {
-		return nil, nil, nil
+	if err := sc.acquire(); err != nil {
+		return nil, nil, err
+	}
+	defer sc.exchange.Unlock()
+
+	if sc.idleTimer != nil {
+		sc.idleTimer.Stop()
+		defer sc.idleTimer.Reset(sc.idleTimeout)
+	}
+
+	if sc.closed {
+		if !sc.idleClosed {
+			return nil, nil, nil
+		}
+		if len(sc.pending) > 0 {
+			record := sc.pending[0]
+			sc.pending = sc.pending[1:]
+			return record, nil, nil
+		}
+		if sc.lastErr != nil {
+			return nil, nil, sc.lastErr
+		}
+		return nil, sc.summary, nil
 	}
 
-	// Serve buffered records first (from a previous PULL response).
+	// Serve buffered records first (from a previous PULL response), even
+	// when the SUCCESS that ended that response also ended the stream.
 	if len(sc.pending) > 0 {
 		record := sc.pending[0]
 		sc.pending = sc.pending[1:]
 		return record, nil, nil
 	}
 
+	if sc.exhausted {
+		return nil, sc.summary, nil
+	}
+
 	if batchSize <= 0 {
 		batchSize = 1
 	}
 
+	record, summary, err := sc.pull(batchSize)
+	if err != nil && sc.reconnect != nil && !sc.delivered && isBrokenConnError(err) {
+		if rerr := sc.rerun(ctx, err); rerr != nil {
+			return nil, nil, rerr
+		}
+		record, summary, err = sc.pull(batchSize)
+	}
+	if record != nil {
+		sc.delivered = true
+	}
+	return record, summary, err
+}
+
+// rerun replaces a connection that broke before any record was delivered
+// and runs the query again from the start. It is only attempted once per
+// stream. The caller holds sc.exchange.
+//
+// Close doesn't take sc.exchange, so the failure may come from a concurrent
+// Close having returned the connection to the pool; the stream then stays
+// closed. closeMu is held while the connection is swapped so that Close
+// returns either the old connection or the new one, never both.
+func (sc *streamingConnectionWrapper) rerun(ctx context.Context, cause error) error {
+	sc.closeMu.Lock()
+	if sc.closed {
+		sc.closeMu.Unlock()
+		return cause
+	}
+
+	logEvent(sc.logger, sc.config.Logging, LogLevelWarn, LogCategoryStreaming, "Connection broke before any record was delivered, re-running query", "error", cause, "query_type", sc.summary.QueryType)
+
+	reconnect := sc.reconnect
+	sc.reconnect = nil
+	sc.netPool.Put(sc.conn, cause)
+
+	pc, err := reconnect(ctx)
+	if err != nil {
+		// The broken connection is already back in the pool; Close must not
+		// return it again.
+		sc.closed = true
+		sc.exhausted = true
+		sc.closeMu.Unlock()
+		return err
+	}
+	sc.conn = pc
+	sc.closeMu.Unlock()
+	sc.lastErr = nil
+	sc.pending = nil
+	sc.hasKeys = false
+	return sc.run(ctx)
+}
+
+// isBrokenConnError reports whether err means the server went away, as
+// opposed to rejecting the request. net.ErrClosed is not among them: it
+// means this side closed the connection, for example through Close.
+func isBrokenConnError(err error) bool {
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// sendPull asks the server for the next batchSize records.
+func (sc *streamingConnectionWrapper) sendPull(batchSize int) error {
 	// Touch connection to update last used time
 	sc.conn.touch()
 
@@ -145,12 +317,22 @@ func (sc *streamingConnectionWrapper) PullNext(ctx context.Context, batchSize in
 	messageBytes, err := messaging.PackMessage(pullMsg.Signature(), pullMsg.Fields())
 	if err != nil {
 		sc.lastErr = err
-		return nil, nil, err
+		return err
 	}
 
 	err = sc.writeChunkedMessage(messageBytes)
 	if err != nil {
 		sc.lastErr = err
+		return err
+	}
+	return nil
+}
+
+// pull sends PULL until a batch yields a record or the stream ends, and
+// returns the first record, buffering the rest. The caller holds
+// sc.exchange.
+func (sc *streamingConnectionWrapper) pull(batchSize int) (*Record, *ResultSummary, error) {
+	if err := sc.sendPull(batchSize); err != nil {
 		return nil, nil, err
 	}
 
@@ -158,7 +340,7 @@ func (sc *streamingConnectionWrapper) PullNext(ctx context.Context, batchSize in
 	// SUCCESS/FAILURE. Read until the terminal message to keep the connection in
 	// a consistent state for subsequent PULLs.
 	for {
-		response, err := messaging.ReadChunkedMessage(sc.conn.Conn)
+		response, err := sc.readMessage()
 		if err != nil {
 			sc.lastErr = err
 			return nil, nil, err
@@ -181,7 +363,7 @@ func (sc *streamingConnectionWrapper) PullNext(ctx context.Context, batchSize in
 			record := make(Record)
 			for i, key := range sc.keys {
 				if i < len(values) {
-					record[key] = values[i]
+					record[key] = decodeGraphValue(values[i])
 				}
 			}
 			sc.pending = append(sc.pending, &record)
@@ -196,37 +378,12 @@ func (sc *streamingConnectionWrapper) PullNext(ctx context.Context, batchSize in
 						hasMore = v
 					}
 
-					// Only the final SUCCESS (has_more == false) is treated as end-of-stream.
-					if !hasMore {
-						// Update summary with final statistics
-						if stats, exists := metadata["stats"]; exists {
-							sc.summary.updateFromStats(stats)
-						}
-						if bookmark, exists := metadata["bookmark"]; exists {
-							if bookmarkStr, ok := bookmark.(string); ok {
-								sc.summary.Bookmark = bookmarkStr
-							} else if sc.logger != nil {
-								sc.logger.Warn("Bookmark is not a string", "type", bookmark)
-							}
-						}
-					}
 				}
 			}
 
+			// Only the final SUCCESS (has_more == false) is treated as end-of-stream.
 			if !hasMore {
-				sc.exhausted = true
-
-				sc.summary.ExecutionTime = time.Since(sc.startTime)
-
-				// Log completion
-				if sc.config.Logging != nil && sc.config.Logging.LogQueryTiming {
-					sc.logger.Info("Streaming query completed", "duration", sc.summary.ExecutionTime, "query_type", sc.summary.QueryType)
-				}
-
-				// Finish observability span
-				if sc.observability != nil && sc.config.Observability != nil {
-					sc.observability.finishQuerySpan(sc.spanCtx, sc.summary, nil, sc.config.Observability)
-				}
+				sc.finish(response)
 			}
 
 			// Return the first buffered record if we have one.
@@ -238,7 +395,12 @@ func (sc *streamingConnectionWrapper) PullNext(ctx context.Context, batchSize in
 			if sc.exhausted {
 				return nil, sc.summary, nil
 			}
-			return nil, nil, nil
+
+			// An empty batch with more to come: ask for the next one rather
+			// than reporting a missing record, which would end the result.
+			if err := sc.sendPull(batchSize); err != nil {
+				return nil, nil, err
+			}
 
 		case messaging.FailureSignature:
 			sc.exhausted = true
@@ -260,6 +422,9 @@ func (sc *streamingConnectionWrapper) PullNext(ctx context.Context, batchSize in
 			sc.lastErr = usageErr
 			return nil, nil, usageErr
 
+		case messaging.IgnoredSignature:
+			return nil, nil, sc.ignored("PULL")
+
 		default:
 			usageErr := NewUsageError("Unexpected response from server")
 			sc.lastErr = usageErr
@@ -268,6 +433,206 @@ func (sc *streamingConnectionWrapper) PullNext(ctx context.Context, batchSize in
 	}
 }
 
+// ignored handles an IGNORED response: the server has been ignoring requests
+// since an earlier FAILURE and will keep doing so until RESET. That earlier
+// failure is the real cause, so it is returned when known. The stream ends
+// and the connection is marked dirty so it is not reused without a reset.
+func (sc *streamingConnectionWrapper) ignored(request string) error {
+	logEvent(sc.logger, sc.config.Logging, LogLevelWarn, LogCategoryBolt, "Server ignored request after a previous failure; connection needs RESET", "request", request, "query_type", sc.summary.QueryType)
+
+	sc.exhausted = true
+	sc.pending = nil
+	sc.conn.markDirty()
+	if sc.lastErr == nil {
+		sc.lastErr = fmt.Errorf("%s: %w", request, messaging.ErrIgnored)
+	}
+	return sc.lastErr
+}
+
+// successHasMore reports whether a SUCCESS message announces more records.
+func successHasMore(response messaging.Message) bool {
+	fields := response.Fields()
+	if len(fields) == 0 {
+		return false
+	}
+	metadata, ok := fields[0].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	hasMore, _ := metadata["has_more"].(bool)
+	return hasMore
+}
+
+// finish records the final SUCCESS of the stream into the summary and marks
+// the stream exhausted.
+func (sc *streamingConnectionWrapper) finish(response messaging.Message) {
+	if fields := response.Fields(); len(fields) > 0 {
+		if metadata, ok := fields[0].(map[string]interface{}); ok {
+			// Update summary with final statistics
+			if stats, exists := metadata["stats"]; exists {
+				sc.summary.updateFromStats(stats)
+			}
+			if bookmark, exists := metadata["bookmark"]; exists {
+				if bookmarkStr, ok := bookmark.(string); ok {
+					sc.summary.Bookmark = bookmarkStr
+				} else {
+					logEvent(sc.logger, sc.config.Logging, LogLevelWarn, LogCategoryBolt, "Bookmark is not a string", "type", bookmark)
+				}
+			}
+			if plan, exists := metadata["plan"]; exists {
+				sc.summary.Plan = parseQueryPlan(plan)
+			}
+			if profile, exists := metadata["profile"]; exists {
+				sc.summary.Profile = parseQueryProfile(profile)
+			}
+			sc.summary.updateFromTimings(metadata)
+		}
+	}
+
+	sc.exhausted = true
+	sc.summary.ExecutionTime = time.Since(sc.startTime)
+
+	// Log completion
+	logEvent(sc.logger, sc.config.Logging, LogLevelInfo, LogCategoryQuery, "Streaming query completed", "duration", sc.summary.ExecutionTime, "query_type", sc.summary.QueryType)
+
+	// Finish observability span
+	if sc.observability != nil && sc.config.Observability != nil {
+		sc.observability.finishQuerySpan(sc.spanCtx, sc.summary, nil, sc.config.Observability)
+	}
+}
+
+// Discard drops any buffered records and asks the server to throw away the
+// rest of the result with DISCARD, instead of pulling records only to ignore
+// them. It returns the final summary.
+func (sc *streamingConnectionWrapper) Discard(ctx context.Context) (*ResultSummary, error) {
+	if err := sc.acquire(); err != nil {
+		return nil, err
+	}
+	defer sc.exchange.Unlock()
+
+	sc.pending = nil
+	if sc.closed {
+		return nil, sc.lastErr
+	}
+	if sc.exhausted {
+		return sc.summary, sc.lastErr
+	}
+
+	logEvent(sc.logger, sc.config.Logging, LogLevelDebug, LogCategoryBolt, "Sending DISCARD for remaining records", "query_type", sc.summary.QueryType)
+
+	sc.conn.touch()
+	for {
+		discardMsg := messaging.NewDiscard(map[string]interface{}{
+			"n":   -1,
+			"qid": -1,
+		})
+		messageBytes, err := messaging.PackMessage(discardMsg.Signature(), discardMsg.Fields())
+		if err != nil {
+			sc.lastErr = err
+			return nil, err
+		}
+		if err := sc.writeChunkedMessage(messageBytes); err != nil {
+			sc.lastErr = err
+			return nil, err
+		}
+
+		response, err := sc.readMessage()
+		if err != nil {
+			sc.lastErr = err
+			return nil, err
+		}
+
+		switch response.Signature() {
+		case messaging.SuccessSignature:
+			// n=-1 should discard everything, but honour has_more if a
+			// server still reports a partial discard.
+			if successHasMore(response) {
+				continue
+			}
+			sc.finish(response)
+			return sc.summary, nil
+		case messaging.FailureSignature:
+			sc.exhausted = true
+			dbErr := &DatabaseError{}
+			if failure, ok := response.(*messaging.Failure); ok {
+				dbErr.Code = failure.Code()
+				dbErr.Message = failure.Message()
+			}
+			sc.lastErr = dbErr
+			if sc.observability != nil && sc.config.Observability != nil {
+				sc.observability.finishQuerySpan(sc.spanCtx, sc.summary, dbErr, sc.config.Observability)
+			}
+			return nil, dbErr
+		case messaging.IgnoredSignature:
+			return nil, sc.ignored("DISCARD")
+		default:
+			usageErr := NewUsageError("Unexpected response to DISCARD message")
+			sc.lastErr = usageErr
+			return nil, usageErr
+		}
+	}
+}
+
+// Reset aborts the running query with a RESET message. Once the server
+// acknowledges it the connection is back in a clean state, so Close returns it
+// to the pool instead of discarding it.
+func (sc *streamingConnectionWrapper) Reset(ctx context.Context) error {
+	if err := sc.acquire(); err != nil {
+		return err
+	}
+	defer sc.exchange.Unlock()
+
+	if sc.closed || sc.exhausted {
+		return nil
+	}
+
+	logEvent(sc.logger, sc.config.Logging, LogLevelDebug, LogCategoryBolt, "Sending RESET to cancel streaming query", "query_type", sc.summary.QueryType)
+
+	resetMsg := messaging.NewReset()
+	messageBytes, err := messaging.PackMessage(resetMsg.Signature(), resetMsg.Fields())
+	if err != nil {
+		sc.lastErr = err
+		return err
+	}
+
+	if err := sc.writeChunkedMessage(messageBytes); err != nil {
+		sc.lastErr = err
+		return err
+	}
+
+	// Requests queued before the RESET are answered with IGNORED; the RESET
+	// itself gets the final SUCCESS or FAILURE.
+	for {
+		response, err := sc.readMessage()
+		if err != nil {
+			sc.lastErr = err
+			return err
+		}
+
+		switch response.Signature() {
+		case messaging.IgnoredSignature, messaging.RecordSignature:
+			continue
+		case messaging.SuccessSignature:
+			sc.exhausted = true
+			sc.pending = nil
+			sc.lastErr = nil
+			return nil
+		case messaging.FailureSignature:
+			dbErr := &DatabaseError{}
+			if failure, ok := response.(*messaging.Failure); ok {
+				dbErr.Code = failure.Code()
+				dbErr.Message = failure.Message()
+			}
+			sc.lastErr = dbErr
+			return dbErr
+		default:
+			usageErr := NewUsageError("Unexpected response to RESET message")
+			sc.lastErr = usageErr
+			return usageErr
+		}
+	}
+}
+
 func (sc *streamingConnectionWrapper) writeChunkedMessage(messageBytes []byte) error {
 	messageSize := len(messageBytes)
 	chunkHeader := make([]byte, 2)
@@ -290,9 +655,14 @@ func (sc *streamingConnectionWrapper) writeChunkedMessage(messageBytes []byte) e
 }
 
 func (sc *streamingConnectionWrapper) Close() error {
+	sc.closeMu.Lock()
+	defer sc.closeMu.Unlock()
 	if sc.closed {
 		return nil
 	}
+	if sc.idleTimer != nil {
+		sc.idleTimer.Stop()
+	}
 
 	wasExhausted := sc.exhausted
 	sc.closed = true
@@ -380,3 +750,31 @@ func (rs *ResultSummary) updateFromStats(stats interface{}) {
 		}
 	}
 }
+
+// updateFromTimings records the server-reported timings from a SUCCESS. The
+// RUN response carries t_first and the final response t_last; Bolt v1 servers
+// used the result_available_after and result_consumed_after names instead.
+func (rs *ResultSummary) updateFromTimings(metadata map[string]interface{}) {
+	if d, ok := serverMillis(metadata, "t_first", "result_available_after"); ok {
+		rs.ResultAvailableAfter = d
+	}
+	if d, ok := serverMillis(metadata, "t_last", "result_consumed_after"); ok {
+		rs.ResultConsumedAfter = d
+	}
+}
+
+// serverMillis reads the first of keys present in metadata as a millisecond
+// count.
+func serverMillis(metadata map[string]interface{}, keys ...string) (time.Duration, bool) {
+	for _, key := range keys {
+		switch ms := metadata[key].(type) {
+		case int64:
+			return time.Duration(ms) * time.Millisecond, true
+		case int:
+			return time.Duration(ms) * time.Millisecond, true
+		case float64:
+			return time.Duration(ms * float64(time.Millisecond)), true
+		}
+	}
+	return 0, false
+}
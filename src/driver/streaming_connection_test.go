@@ -0,0 +1,611 @@
+package driver
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seuros/gopher-cypher/src/bolt/messaging"
+	"github.com/seuros/gopher-cypher/src/connection_url_resolver"
+	"github.com/yudhasubki/netpool"
+)
+
+// boltScriptConn is a net.Conn that serves pre-queued Bolt responses and
+// records every request the client writes.
+type boltScriptConn struct {
+	mockConn
+	in  bytes.Buffer
+	out bytes.Buffer
+}
+
+func (c *boltScriptConn) Read(b []byte) (int, error) {
+	if c.in.Len() == 0 {
+		if c.readTimeout {
+			return 0, &net.OpError{Op: "read", Err: &timeoutError{}}
+		}
+		return 0, io.EOF
+	}
+	return c.in.Read(b)
+}
+
+func (c *boltScriptConn) Write(b []byte) (int, error) {
+	return c.out.Write(b)
+}
+
+// queue appends a chunked server message to the read side of the connection.
+func (c *boltScriptConn) queue(t *testing.T, signature byte, fields ...interface{}) {
+	t.Helper()
+	data, err := messaging.PackMessage(signature, fields)
+	if err != nil {
+		t.Fatalf("failed to pack message: %v", err)
+	}
+	header := make([]byte, 2)
+	binary.BigEndian.PutUint16(header, uint16(len(data)))
+	c.in.Write(header)
+	c.in.Write(data)
+	c.in.Write([]byte{0x00, 0x00})
+}
+
+// sent decodes every request the client has written so far.
+func (c *boltScriptConn) sent(t *testing.T) []messaging.Message {
+	t.Helper()
+	reader := &boltScriptConn{}
+	reader.in.Write(c.out.Bytes())
+
+	var messages []messaging.Message
+	for reader.in.Len() > 0 {
+		msg, err := messaging.ReadChunkedMessage(reader)
+		if err != nil {
+			t.Fatalf("failed to decode sent message: %v", err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+// newScriptedStream builds a streaming connection wrapper over conn, checked
+// out of a single-connection pool so tests can observe whether it is returned.
+func newScriptedStream(t *testing.T, conn net.Conn) (*streamingConnectionWrapper, *connPool) {
+	t.Helper()
+	np, err := netpool.New(func() (net.Conn, error) {
+		return newPooledConn(conn), nil
+	}, netpool.WithMinPool(0), netpool.WithMaxPool(1))
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	pool := newConnPool(np, 1)
+	pooled, err := pool.Get()
+	if err != nil {
+		t.Fatalf("failed to get connection: %v", err)
+	}
+
+	return &streamingConnectionWrapper{
+		conn:    pooled.(*pooledConn),
+		netPool: pool,
+		query:   "UNWIND range(1, 100) AS n RETURN n",
+		logger:  &NoOpLogger{},
+		config:  DefaultConfig(),
+		summary: &ResultSummary{},
+	}, pool
+}
+
+// newScriptedDriver builds a driver whose pool hands out conn as an already
+// authenticated connection, so RunWithContext goes straight to RUN.
+func newScriptedDriver(t *testing.T, conn net.Conn) *driver {
+	t.Helper()
+	pc := newPooledConn(conn)
+	pc.markAuthenticated(5, 4)
+	pool, err := netpool.New(func() (net.Conn, error) {
+		return pc, nil
+	}, netpool.WithMinPool(0), netpool.WithMaxPool(1))
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+
+	config := DefaultConfig()
+	config.ConnectionPool.EnableLivenessCheck = false
+	return &driver{
+		urlResolver: connection_url_resolver.NewConnectionUrlResolver("neo4j://localhost:7687"),
+		netPool:     newConnPool(pool, 1),
+		config:      config,
+		logger:      &NoOpLogger{},
+	}
+}
+
+func TestStreamingResult_CancelSendsReset(t *testing.T) {
+	conn := &boltScriptConn{}
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"fields": []interface{}{"n"}})
+	conn.queue(t, messaging.RecordSignature, []interface{}{1})
+	conn.queue(t, messaging.RecordSignature, []interface{}{2})
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"has_more": true})
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{})
+
+	stream, pool := newScriptedStream(t, conn)
+	if err := stream.sendRun(context.Background()); err != nil {
+		t.Fatalf("sendRun failed: %v", err)
+	}
+
+	result := NewStreamingResult(stream, stream.query, nil)
+	result.SetFetchSize(2)
+	ctx := context.Background()
+	if !result.Next(ctx) {
+		t.Fatalf("expected a first record, err=%v", result.Err())
+	}
+
+	if err := result.Cancel(ctx); err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+	if result.IsOpen() || result.Next(ctx) {
+		t.Error("expected result to be closed after Cancel")
+	}
+
+	sent := conn.sent(t)
+	if last := sent[len(sent)-1]; last.Signature() != messaging.ResetSignature {
+		t.Errorf("expected RESET to be the last message sent, got 0x%02X", last.Signature())
+	}
+	if pool.Len() != 1 {
+		t.Errorf("expected connection to be returned to the pool, idle=%d", pool.Len())
+	}
+}
+
+func TestStreamingResult_ConsumeDiscardsRemainingRecords(t *testing.T) {
+	conn := &boltScriptConn{}
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"fields": []interface{}{"n"}})
+	conn.queue(t, messaging.RecordSignature, []interface{}{1})
+	conn.queue(t, messaging.RecordSignature, []interface{}{2})
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"has_more": true})
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"bookmark": "bm:1"})
+
+	stream, pool := newScriptedStream(t, conn)
+	if err := stream.sendRun(context.Background()); err != nil {
+		t.Fatalf("sendRun failed: %v", err)
+	}
+
+	result := NewStreamingResult(stream, stream.query, nil)
+	result.SetFetchSize(2)
+	ctx := context.Background()
+	if !result.Next(ctx) {
+		t.Fatalf("expected a first record, err=%v", result.Err())
+	}
+
+	summary, err := result.Consume(ctx)
+	if err != nil {
+		t.Fatalf("Consume failed: %v", err)
+	}
+	if summary == nil || summary.Bookmark != "bm:1" {
+		t.Errorf("expected summary from the DISCARD response, got %+v", summary)
+	}
+
+	var signatures []byte
+	for _, msg := range conn.sent(t) {
+		signatures = append(signatures, msg.Signature())
+	}
+	want := []byte{messaging.RunSignature, messaging.PullSignature, messaging.DiscardSignature}
+	if !bytes.Equal(signatures, want) {
+		t.Errorf("expected RUN, PULL, DISCARD to be sent, got % X", signatures)
+	}
+	if pool.Len() != 1 {
+		t.Errorf("expected connection to be returned to the pool, idle=%d", pool.Len())
+	}
+}
+
+// gatedConn holds every Read until release is closed and reports each Write,
+// so a test can keep an exchange in flight.
+type gatedConn struct {
+	boltScriptConn
+	release chan struct{}
+	wrote   chan struct{}
+}
+
+func (c *gatedConn) Read(b []byte) (int, error) {
+	<-c.release
+	return c.boltScriptConn.Read(b)
+}
+
+func (c *gatedConn) Write(b []byte) (int, error) {
+	n, err := c.boltScriptConn.Write(b)
+	select {
+	case c.wrote <- struct{}{}:
+	default:
+	}
+	return n, err
+}
+
+func TestStreamingConnection_RejectsConcurrentPull(t *testing.T) {
+	conn := &gatedConn{release: make(chan struct{}), wrote: make(chan struct{}, 1)}
+	conn.queue(t, messaging.RecordSignature, []interface{}{1})
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"has_more": true})
+
+	stream, _ := newScriptedStream(t, conn)
+	stream.hasKeys = true
+	stream.keys = []string{"n"}
+
+	type pullResult struct {
+		record *Record
+		err    error
+	}
+	first := make(chan pullResult, 1)
+	go func() {
+		record, _, err := stream.PullNext(context.Background(), 1)
+		first <- pullResult{record, err}
+	}()
+	<-conn.wrote // the first PULL is on the wire, waiting for its response
+
+	if _, _, err := stream.PullNext(context.Background(), 1); err == nil {
+		t.Fatal("Expected an error for concurrent PullNext")
+	} else if _, ok := err.(*UsageError); !ok {
+		t.Fatalf("Expected UsageError, got %T: %v", err, err)
+	}
+
+	close(conn.release)
+	result := <-first
+	if result.err != nil {
+		t.Fatalf("First PullNext failed: %v", result.err)
+	}
+	if result.record == nil || (*result.record)["n"] != int64(1) {
+		t.Errorf("Expected record n=1, got %v", result.record)
+	}
+
+	sent := conn.sent(t)
+	if len(sent) != 1 || sent[0].Signature() != messaging.PullSignature {
+		t.Errorf("Expected exactly one PULL on the wire, got %d messages", len(sent))
+	}
+}
+
+func TestStreamingConnection_IdleWatchdogClosesAbandonedStream(t *testing.T) {
+	conn := &boltScriptConn{}
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"fields": []interface{}{"n"}})
+
+	stream, pool := newScriptedStream(t, conn)
+	if err := stream.sendRun(context.Background()); err != nil {
+		t.Fatalf("sendRun failed: %v", err)
+	}
+	stream.startIdleWatchdog(20 * time.Millisecond)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !stream.isClosed() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the idle watchdog to close the stream")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !conn.closed || pool.Len() != 0 {
+		t.Errorf("expected the unconsumed connection to be discarded, closed=%v idle=%d", conn.closed, pool.Len())
+	}
+
+	record, summary, err := stream.PullNext(context.Background(), 10)
+	if _, ok := err.(*UsageError); record != nil || summary != nil || !ok {
+		t.Errorf("expected the idle-closed stream to report a UsageError, got %v %v %v", record, summary, err)
+	}
+}
+
+// waitIdleClosed waits for the idle watchdog to close stream.
+func waitIdleClosed(t *testing.T, stream *streamingConnectionWrapper) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !stream.isClosed() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the idle watchdog to close the stream")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestStreamingResult_IdleTimeoutReportsError(t *testing.T) {
+	conn := &boltScriptConn{}
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"fields": []interface{}{"n"}})
+	conn.queue(t, messaging.RecordSignature, []interface{}{1})
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"has_more": true})
+
+	stream, _ := newScriptedStream(t, conn)
+	if err := stream.sendRun(context.Background()); err != nil {
+		t.Fatalf("sendRun failed: %v", err)
+	}
+	stream.startIdleWatchdog(20 * time.Millisecond)
+
+	result := NewStreamingResult(stream, stream.query, nil)
+	result.SetFetchSize(1)
+	ctx := context.Background()
+	if !result.Next(ctx) {
+		t.Fatalf("expected a first record, err=%v", result.Err())
+	}
+
+	waitIdleClosed(t, stream)
+	if result.Next(ctx) {
+		t.Fatal("expected no more records after the idle timeout")
+	}
+	if _, ok := result.Err().(*UsageError); !ok || !strings.Contains(result.Err().Error(), "idle timeout") {
+		t.Errorf("expected an idle timeout error, got %v", result.Err())
+	}
+}
+
+func TestStreamingResult_IdleTimeoutKeepsBufferedRecords(t *testing.T) {
+	conn := &boltScriptConn{}
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"fields": []interface{}{"n"}})
+	conn.queue(t, messaging.RecordSignature, []interface{}{1})
+	conn.queue(t, messaging.RecordSignature, []interface{}{2})
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"bookmark": "bm:1"})
+
+	stream, pool := newScriptedStream(t, conn)
+	if err := stream.sendRun(context.Background()); err != nil {
+		t.Fatalf("sendRun failed: %v", err)
+	}
+	stream.startIdleWatchdog(20 * time.Millisecond)
+
+	result := NewStreamingResult(stream, stream.query, nil)
+	result.SetFetchSize(2)
+	ctx := context.Background()
+	if !result.Next(ctx) {
+		t.Fatalf("expected a first record, err=%v", result.Err())
+	}
+
+	waitIdleClosed(t, stream)
+	if pool.Len() != 1 {
+		t.Errorf("expected the finished stream's connection back in the pool, idle=%d", pool.Len())
+	}
+	if !result.Next(ctx) || (*result.Record())["n"] != int64(2) {
+		t.Fatalf("expected the buffered record after the idle timeout, err=%v", result.Err())
+	}
+	if result.Next(ctx) {
+		t.Fatal("expected the stream to end after the buffered record")
+	}
+	if result.Err() != nil {
+		t.Errorf("expected a finished stream to end cleanly, got %v", result.Err())
+	}
+	summary, err := result.Consume(ctx)
+	if err != nil || summary.Bookmark != "bm:1" {
+		t.Errorf("expected the final summary, got %+v %v", summary, err)
+	}
+}
+
+func TestStreamingConnection_IgnoredAfterFailure(t *testing.T) {
+	conn := &boltScriptConn{}
+	conn.queue(t, messaging.FailureSignature, map[string]interface{}{
+		"code":    "Neo.ClientError.Statement.SyntaxError",
+		"message": "Invalid input",
+	})
+	conn.queue(t, messaging.IgnoredSignature)
+
+	stream, pool := newScriptedStream(t, conn)
+	stream.conn.markAuthenticated(5, 4)
+	runErr := stream.sendRun(context.Background())
+	var dbErr *DatabaseError
+	if !errors.As(runErr, &dbErr) {
+		t.Fatalf("expected the RUN failure, got %v", runErr)
+	}
+
+	_, _, err := stream.PullNext(context.Background(), 10)
+	if err != runErr {
+		t.Fatalf("expected the IGNORED PULL to surface the RUN failure, got %v", err)
+	}
+	if stream.conn.isAuthenticated() {
+		t.Error("expected the connection to be marked dirty")
+	}
+
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if pool.Len() != 0 {
+		t.Errorf("expected the ignored connection to be discarded, idle=%d", pool.Len())
+	}
+}
+
+func TestRunWithContext_Ignored(t *testing.T) {
+	conn := &boltScriptConn{}
+	conn.queue(t, messaging.IgnoredSignature)
+
+	d := newScriptedDriver(t, conn)
+	_, _, _, err := d.RunWithContext(context.Background(), "RETURN 1", nil, nil)
+	if !errors.Is(err, messaging.ErrIgnored) {
+		t.Fatalf("expected ErrIgnored, got %v", err)
+	}
+	if strings.Contains(err.Error(), "unexpected") {
+		t.Errorf("expected IGNORED to be recognised, got %v", err)
+	}
+}
+
+func TestStreamingConnection_MaxMessageSize(t *testing.T) {
+	conn := &boltScriptConn{}
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"fields": []interface{}{"n"}})
+	conn.queue(t, messaging.RecordSignature, []interface{}{strings.Repeat("x", 4096)})
+
+	stream, pool := newScriptedStream(t, conn)
+	stream.config.MaxMessageSize = 1024
+	stream.conn.markAuthenticated(5, 4)
+	if err := stream.sendRun(context.Background()); err != nil {
+		t.Fatalf("sendRun failed: %v", err)
+	}
+
+	_, _, err := stream.PullNext(context.Background(), 10)
+	if !errors.Is(err, messaging.ErrMessageTooLarge) {
+		t.Fatalf("expected ErrMessageTooLarge, got %v", err)
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if pool.Len() != 0 {
+		t.Errorf("expected the connection to be discarded mid-message, idle=%d", pool.Len())
+	}
+}
+
+func TestStreamingConnection_DrainsBatchEndingTheStream(t *testing.T) {
+	conn := &boltScriptConn{}
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"fields": []interface{}{"n"}})
+	for i := int64(1); i <= 3; i++ {
+		conn.queue(t, messaging.RecordSignature, []interface{}{i})
+	}
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"has_more": false})
+
+	stream, _ := newScriptedStream(t, conn)
+	stream.conn.markAuthenticated(5, 4)
+	if err := stream.sendRun(context.Background()); err != nil {
+		t.Fatalf("sendRun failed: %v", err)
+	}
+
+	// The final SUCCESS arrives with the first PULL; the buffered records
+	// must still all be served.
+	for want := int64(1); want <= 3; want++ {
+		rec, _, err := stream.PullNext(context.Background(), 10)
+		if err != nil || rec == nil {
+			t.Fatalf("record %d: got %v, %v", want, rec, err)
+		}
+		if (*rec)["n"] != want {
+			t.Errorf("expected n=%d, got %v", want, (*rec)["n"])
+		}
+	}
+	if rec, _, err := stream.PullNext(context.Background(), 10); rec != nil || err != nil {
+		t.Errorf("expected the stream to be exhausted, got %v, %v", rec, err)
+	}
+}
+
+func TestRunStream_AutoReconnectReads(t *testing.T) {
+	// The first connection answers RUN, then hangs up before any record.
+	broken := &boltScriptConn{}
+	broken.queue(t, messaging.SuccessSignature, map[string]interface{}{"fields": []interface{}{"n"}})
+
+	healthy := &boltScriptConn{}
+	healthy.queue(t, messaging.SuccessSignature, map[string]interface{}{"fields": []interface{}{"n"}})
+	for i := int64(1); i <= 2; i++ {
+		healthy.queue(t, messaging.RecordSignature, []interface{}{i})
+	}
+	healthy.queue(t, messaging.SuccessSignature, map[string]interface{}{"has_more": false})
+
+	conns := []*boltScriptConn{broken, healthy}
+	np, err := netpool.New(func() (net.Conn, error) {
+		if len(conns) == 0 {
+			return nil, errors.New("no more connections")
+		}
+		pc := newPooledConn(conns[0])
+		pc.markAuthenticated(5, 4)
+		conns = conns[1:]
+		return pc, nil
+	}, netpool.WithMinPool(0), netpool.WithMaxPool(1))
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	d := newScriptedDriver(t, &boltScriptConn{})
+	d.netPool = newConnPool(np, 1)
+	d.config.AutoReconnectReads = true
+
+	result, err := d.RunStream(context.Background(), "MATCH (n) RETURN n", nil, nil)
+	if err != nil {
+		t.Fatalf("RunStream failed: %v", err)
+	}
+	records, err := result.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("expected the query to be re-run on a fresh connection, got %v", err)
+	}
+	if len(records) != 2 || (*records[0])["n"] != int64(1) || (*records[1])["n"] != int64(2) {
+		t.Errorf("expected records 1 and 2, got %v", records)
+	}
+	if runs := healthy.sent(t); len(runs) == 0 || runs[0].Signature() != messaging.RunSignature {
+		t.Errorf("expected the query to be sent again on the new connection, got %v", runs)
+	}
+}
+
+func TestStreamingConnection_NoReconnectAfterDelivery(t *testing.T) {
+	conn := &boltScriptConn{}
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"fields": []interface{}{"n"}})
+	conn.queue(t, messaging.RecordSignature, []interface{}{int64(1)})
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"has_more": true})
+
+	stream, _ := newScriptedStream(t, conn)
+	stream.conn.markAuthenticated(5, 4)
+	reconnects := 0
+	stream.reconnect = func(ctx context.Context) (*pooledConn, error) {
+		reconnects++
+		return nil, errors.New("unexpected reconnect")
+	}
+	if err := stream.sendRun(context.Background()); err != nil {
+		t.Fatalf("sendRun failed: %v", err)
+	}
+
+	if rec, _, err := stream.PullNext(context.Background(), 1); err != nil || rec == nil {
+		t.Fatalf("expected the first record, got %v, %v", rec, err)
+	}
+	// The caller has seen a record, so re-running would repeat it.
+	if _, _, err := stream.PullNext(context.Background(), 1); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected the broken connection to surface, got %v", err)
+	}
+	if reconnects != 0 {
+		t.Errorf("expected no reconnect after a record was delivered, got %d", reconnects)
+	}
+}
+
+func TestStreamingConnection_NoReconnectAfterClose(t *testing.T) {
+	conn := &boltScriptConn{}
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"fields": []interface{}{"n"}})
+
+	stream, pool := newScriptedStream(t, conn)
+	stream.conn.markAuthenticated(5, 4)
+	reconnects := 0
+	stream.reconnect = func(ctx context.Context) (*pooledConn, error) {
+		reconnects++
+		return nil, errors.New("unexpected reconnect")
+	}
+	if err := stream.sendRun(context.Background()); err != nil {
+		t.Fatalf("sendRun failed: %v", err)
+	}
+
+	// A concurrent Close returns the connection while a PULL is in flight;
+	// the read that fails as a result must not re-dial.
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if isBrokenConnError(net.ErrClosed) {
+		t.Errorf("expected a locally closed connection not to count as broken")
+	}
+	if err := stream.rerun(context.Background(), io.EOF); !errors.Is(err, io.EOF) {
+		t.Errorf("expected the original error from a closed stream, got %v", err)
+	}
+	if reconnects != 0 {
+		t.Errorf("expected no reconnect after Close, got %d", reconnects)
+	}
+	if inUse := pool.stats().InUse; inUse != 0 {
+		t.Errorf("expected the connection returned exactly once, in use=%d", inUse)
+	}
+}
+
+func TestStreamingResult_EmptyIntermediateBatch(t *testing.T) {
+	conn := &boltScriptConn{}
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"fields": []interface{}{"n"}})
+	// The first PULL yields no records but more are coming.
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"has_more": true})
+	conn.queue(t, messaging.RecordSignature, []interface{}{int64(1)})
+	conn.queue(t, messaging.RecordSignature, []interface{}{int64(2)})
+	conn.queue(t, messaging.SuccessSignature, map[string]interface{}{"has_more": false})
+
+	stream, pool := newScriptedStream(t, conn)
+	stream.conn.markAuthenticated(5, 4)
+	if err := stream.sendRun(context.Background()); err != nil {
+		t.Fatalf("sendRun failed: %v", err)
+	}
+
+	result := NewStreamingResult(stream, stream.query, nil)
+	records, err := result.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected the records after the empty batch, got %d", len(records))
+	}
+
+	pulls := 0
+	for _, msg := range conn.sent(t) {
+		if msg.Signature() == messaging.PullSignature {
+			pulls++
+		}
+	}
+	if pulls != 2 {
+		t.Errorf("expected a second PULL after the empty batch, got %d", pulls)
+	}
+	if result.IsOpen() || pool.Len() != 1 {
+		t.Errorf("expected the result closed and its connection returned, open=%v idle=%d", result.IsOpen(), pool.Len())
+	}
+}
@@ -0,0 +1,152 @@
+package driver
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// StructToParams converts a struct (or pointer to struct) into a parameter map
+// suitable for queries such as `CREATE (n) SET n = $props`.
+//
+// Field names come from the `cypher` struct tag, falling back to the Go field
+// name; `cypher:"-"` skips a field and `cypher:"name,omitempty"` drops it when
+// it holds its zero value. Unexported fields are ignored and embedded structs
+// are flattened into the parent. Nested structs become maps, pointers are
+// dereferenced (nil becomes null), byte slices stay bytes, other slices and
+// arrays become lists, and numbers are widened to int64, uint64 or float64.
+// time.Time values become DateTime values carrying their UTC offset.
+func StructToParams(v interface{}) (map[string]interface{}, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, NewUsageError("StructToParams requires a non-nil struct")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, NewUsageError(fmt.Sprintf("StructToParams requires a struct, got %T", v))
+	}
+
+	params := make(map[string]interface{})
+	if err := structFields(rv, params); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+func structFields(rv reflect.Value, out map[string]interface{}) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag := field.Tag.Get("cypher")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		fv := rv.Field(i)
+
+		if field.Anonymous && name == "" {
+			embedded := fv
+			if embedded.Kind() == reflect.Pointer {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct && embedded.Type() != timeType {
+				if err := structFields(embedded, out); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if opts == "omitempty" && fv.IsZero() {
+			continue
+		}
+
+		value, err := paramValue(fv)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		out[name] = value
+	}
+	return nil
+}
+
+// paramValue converts a reflected value into the plain types the packer
+// understands.
+func paramValue(rv reflect.Value) (interface{}, error) {
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return paramValue(rv.Elem())
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.Slice:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return append([]byte(nil), rv.Bytes()...), nil
+		}
+		fallthrough
+	case reflect.Array:
+		list := make([]interface{}, rv.Len())
+		for i := range list {
+			item, err := paramValue(rv.Index(i))
+			if err != nil {
+				return nil, fmt.Errorf("index %d: %w", i, err)
+			}
+			list[i] = item
+		}
+		return list, nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map key type %s is not a string", rv.Type().Key())
+		}
+		if rv.IsNil() {
+			return nil, nil
+		}
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			item, err := paramValue(iter.Value())
+			if err != nil {
+				return nil, fmt.Errorf("key %q: %w", iter.Key().String(), err)
+			}
+			m[iter.Key().String()] = item
+		}
+		return m, nil
+	case reflect.Struct:
+		if rv.Type() == timeType {
+			return dateTimeStructure(rv.Interface().(time.Time)), nil
+		}
+		m := make(map[string]interface{})
+		if err := structFields(rv, m); err != nil {
+			return nil, err
+		}
+		return m, nil
+	default:
+		return nil, fmt.Errorf("unsupported parameter type %s", rv.Type())
+	}
+}
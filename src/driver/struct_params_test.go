@@ -0,0 +1,97 @@
+package driver
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/seuros/gopher-cypher/src/bolt/packstream"
+)
+
+type paramAddress struct {
+	City string `cypher:"city"`
+	Zip  *int   `cypher:"zip"`
+}
+
+type paramAudit struct {
+	CreatedBy string `cypher:"created_by"`
+}
+
+type paramPerson struct {
+	paramAudit
+	Name     string        `cypher:"name"`
+	Age      int32         `cypher:"age"`
+	Score    float32       `cypher:"score"`
+	Nickname *string       `cypher:"nickname"`
+	Manager  *string       `cypher:"manager"`
+	Email    string        `cypher:"email,omitempty"`
+	Tags     []string      `cypher:"tags"`
+	Address  paramAddress  `cypher:"address"`
+	Previous *paramAddress `cypher:"previous"`
+	Born     time.Time     `cypher:"born"`
+	Photo    []byte        `cypher:"photo"`
+	Secret   string        `cypher:"-"`
+	Active   bool
+	internal string
+}
+
+func TestStructToParams(t *testing.T) {
+	nickname := "Al"
+	zip := 12345
+	born := time.Date(1990, 1, 2, 3, 4, 5, 6, time.FixedZone("CET", 3600))
+	p := paramPerson{
+		paramAudit: paramAudit{CreatedBy: "importer"},
+		Name:       "Alice",
+		Age:        30,
+		Score:      1.5,
+		Nickname:   &nickname,
+		Tags:       []string{"a", "b"},
+		Address:    paramAddress{City: "Paris", Zip: &zip},
+		Born:       born,
+		Photo:      []byte{0xFF, 0xD8},
+		Secret:     "hidden",
+		Active:     true,
+		internal:   "ignored",
+	}
+
+	got, err := StructToParams(&p)
+	if err != nil {
+		t.Fatalf("StructToParams failed: %v", err)
+	}
+	want := map[string]interface{}{
+		"created_by": "importer",
+		"name":       "Alice",
+		"age":        int64(30),
+		"score":      float64(1.5),
+		"nickname":   "Al",
+		"manager":    nil,
+		"tags":       []interface{}{"a", "b"},
+		"address":    map[string]interface{}{"city": "Paris", "zip": int64(12345)},
+		"previous":   nil,
+		"born": packstream.Structure{
+			Signature: dateTimeSignature,
+			Fields:    []interface{}{born.Unix(), int64(6), int64(3600)},
+		},
+		"photo":  []byte{0xFF, 0xD8},
+		"Active": true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected params\n got: %#v\nwant: %#v", got, want)
+	}
+	for _, key := range []string{"born", "photo"} {
+		if err := packstream.NewPacker(&bytes.Buffer{}).Pack(got[key]); err != nil {
+			t.Errorf("expected %s to pack, got %v", key, err)
+		}
+	}
+}
+
+func TestStructToParams_RejectsNonStruct(t *testing.T) {
+	if _, err := StructToParams(map[string]interface{}{"a": 1}); err == nil {
+		t.Error("expected an error for a map")
+	}
+	var p *paramPerson
+	if _, err := StructToParams(p); err == nil {
+		t.Error("expected an error for a nil pointer")
+	}
+}
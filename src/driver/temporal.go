@@ -0,0 +1,126 @@
+package driver
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/seuros/gopher-cypher/src/bolt/packstream"
+)
+
+// Bolt 5 structure signatures for temporal values.
+const (
+	dateSignature          = 0x44 // Date(days)
+	localDateTimeSignature = 0x64 // LocalDateTime(seconds, nanoseconds)
+	dateTimeSignature      = 0x49 // DateTime(seconds, nanoseconds, tz_offset_seconds), seconds in UTC
+	durationSignature      = 0x45 // Duration(months, days, seconds, nanoseconds)
+)
+
+var (
+	isoDatePattern     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	isoDateTimePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d{1,9})?(Z|[+-]\d{2}:\d{2})?$`)
+	isoDurationPattern = regexp.MustCompile(`^P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)(?:\.(\d{1,9}))?S)?)?$`)
+)
+
+// runParams returns the parameters to send with RUN, applying
+// Config.CoerceTemporalStrings. The caller's map is never mutated.
+func (d *driver) runParams(params map[string]interface{}) map[string]interface{} {
+	if !d.config.CoerceTemporalStrings || len(params) == 0 {
+		return params
+	}
+	return coerceTemporalValue(params).(map[string]interface{})
+}
+
+// coerceTemporalValue converts ISO-8601 strings, including those nested in
+// maps and lists, into temporal structures. Other values are returned as is.
+func coerceTemporalValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case string:
+		if s, ok := parseTemporalString(x); ok {
+			return s
+		}
+		return x
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(x))
+		for k, item := range x {
+			out[k] = coerceTemporalValue(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(x))
+		for i, item := range x {
+			out[i] = coerceTemporalValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// parseTemporalString recognises an ISO-8601 date ("2023-01-02"), local
+// date-time ("2023-01-02T03:04:05"), zoned date-time ("...Z" or "...+02:00")
+// or non-negative duration ("P1DT2H"). Strings that merely look similar but
+// don't denote a valid value (such as month 13) are not matched.
+func parseTemporalString(s string) (packstream.Structure, bool) {
+	switch {
+	case isoDatePattern.MatchString(s):
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return packstream.Structure{}, false
+		}
+		return packstream.Structure{Signature: dateSignature, Fields: []interface{}{floorDiv(t.Unix(), 86400)}}, true
+
+	case isoDateTimePattern.MatchString(s):
+		m := isoDateTimePattern.FindStringSubmatch(s)
+		if m[2] == "" {
+			t, err := time.Parse("2006-01-02T15:04:05.999999999", s)
+			if err != nil {
+				return packstream.Structure{}, false
+			}
+			return packstream.Structure{Signature: localDateTimeSignature, Fields: []interface{}{t.Unix(), int64(t.Nanosecond())}}, true
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return packstream.Structure{}, false
+		}
+		return dateTimeStructure(t), true
+
+	case len(s) > 1 && !strings.HasSuffix(s, "T") && isoDurationPattern.MatchString(s):
+		m := isoDurationPattern.FindStringSubmatch(s)
+		n := make([]int64, 8)
+		for i := 1; i <= 7; i++ {
+			if m[i] == "" {
+				continue
+			}
+			v, err := strconv.ParseInt(m[i], 10, 64)
+			if err != nil {
+				return packstream.Structure{}, false
+			}
+			n[i] = v
+		}
+		var nanos int64
+		if frac := m[8]; frac != "" {
+			nanos, _ = strconv.ParseInt(frac+strings.Repeat("0", 9-len(frac)), 10, 64)
+		}
+		months := n[1]*12 + n[2]
+		days := n[3]*7 + n[4]
+		seconds := n[5]*3600 + n[6]*60 + n[7]
+		return packstream.Structure{Signature: durationSignature, Fields: []interface{}{months, days, seconds, nanos}}, true
+	}
+	return packstream.Structure{}, false
+}
+
+// dateTimeStructure encodes t as a DateTime with the UTC offset t has at that
+// instant; a named zone is not kept.
+func dateTimeStructure(t time.Time) packstream.Structure {
+	_, offset := t.Zone()
+	return packstream.Structure{Signature: dateTimeSignature, Fields: []interface{}{t.Unix(), int64(t.Nanosecond()), int64(offset)}}
+}
+
+// floorDiv divides rounding toward negative infinity, so dates before the
+// epoch map to the right day.
+func floorDiv(a, b int64) int64 {
+	return int64(math.Floor(float64(a) / float64(b)))
+}
@@ -0,0 +1,103 @@
+package driver
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/seuros/gopher-cypher/src/bolt/messaging"
+	"github.com/seuros/gopher-cypher/src/bolt/packstream"
+	"github.com/seuros/gopher-cypher/src/driver/testserver"
+)
+
+func TestCoerceTemporalValue(t *testing.T) {
+	tests := []struct {
+		name string
+		in   interface{}
+		want interface{}
+	}{
+		{"datetime utc", "2023-01-02T03:04:05Z",
+			packstream.Structure{Signature: dateTimeSignature, Fields: []interface{}{int64(1672628645), int64(0), int64(0)}}},
+		{"datetime offset", "2023-01-02T05:04:05.5+02:00",
+			packstream.Structure{Signature: dateTimeSignature, Fields: []interface{}{int64(1672628645), int64(500000000), int64(7200)}}},
+		{"local datetime", "2023-01-02T03:04:05",
+			packstream.Structure{Signature: localDateTimeSignature, Fields: []interface{}{int64(1672628645), int64(0)}}},
+		{"date", "2023-01-02", packstream.Structure{Signature: dateSignature, Fields: []interface{}{int64(19359)}}},
+		{"date before epoch", "1969-12-31", packstream.Structure{Signature: dateSignature, Fields: []interface{}{int64(-1)}}},
+		{"duration", "P1Y2M3W4DT5H6M7.25S",
+			packstream.Structure{Signature: durationSignature, Fields: []interface{}{int64(14), int64(25), int64(18367), int64(250000000)}}},
+		{"plain string", "Alice", "Alice"},
+		{"invalid date", "2023-13-45", "2023-13-45"},
+		{"bare P", "P", "P"},
+		{"empty time part", "P1DT", "P1DT"},
+		{"time of day", "03:04:05", "03:04:05"},
+		{"non-string", int64(7), int64(7)},
+		{"nested", map[string]interface{}{"at": []interface{}{"2023-01-02", "x"}},
+			map[string]interface{}{"at": []interface{}{
+				packstream.Structure{Signature: dateSignature, Fields: []interface{}{int64(19359)}}, "x",
+			}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := coerceTemporalValue(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("coerceTemporalValue(%v) = %#v, want %#v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDriver_CoerceTemporalStrings(t *testing.T) {
+	const query = "MATCH (e:Event) WHERE e.at = $at AND e.name = $name RETURN e"
+
+	runParams := func(t *testing.T, coerce bool) map[string]interface{} {
+		t.Helper()
+		srv, err := testserver.New()
+		if err != nil {
+			t.Fatalf("failed to start test server: %v", err)
+		}
+		defer srv.Close()
+		srv.Handle(query, testserver.Result{Fields: []string{"e"}})
+
+		config := DefaultConfig()
+		config.CoerceTemporalStrings = coerce
+		d, err := NewDriverWithConfig(srv.URL(), config)
+		if err != nil {
+			t.Fatalf("failed to create driver: %v", err)
+		}
+		defer d.Close()
+
+		params := map[string]interface{}{"at": "2023-01-02T03:04:05Z", "name": "launch"}
+		if _, _, err := d.Run(context.Background(), query, params, nil); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if params["at"] != "2023-01-02T03:04:05Z" {
+			t.Errorf("caller's params were mutated: %v", params)
+		}
+		for _, msg := range srv.Requests() {
+			if msg.Signature() == messaging.RunSignature {
+				sent, _ := msg.Fields()[1].(map[string]interface{})
+				return sent
+			}
+		}
+		t.Fatal("no RUN message received")
+		return nil
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		sent := runParams(t, true)
+		want := []interface{}{byte(dateTimeSignature), []interface{}{int64(1672628645), int64(0), int64(0)}}
+		if !reflect.DeepEqual(sent["at"], want) {
+			t.Errorf("expected at to be sent as a DateTime, got %#v", sent["at"])
+		}
+		if sent["name"] != "launch" {
+			t.Errorf("expected name to stay a string, got %#v", sent["name"])
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		sent := runParams(t, false)
+		if sent["at"] != "2023-01-02T03:04:05Z" {
+			t.Errorf("expected at to stay a string, got %#v", sent["at"])
+		}
+	})
+}
@@ -0,0 +1,360 @@
+// Package testserver provides a minimal in-process Bolt server for exercising
+// the driver end-to-end without a database. It speaks just enough of Bolt 5 to
+// complete the handshake, accept HELLO/LOGON, and answer RUN/PULL/DISCARD with
+// scripted records and summaries.
+package testserver
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/seuros/gopher-cypher/src/bolt/messaging"
+)
+
+var boltMagic = []byte{0x60, 0x60, 0xB0, 0x17}
+
+// Result is the scripted response to a RUN of a particular query.
+type Result struct {
+	// Fields are the column names returned in the RUN SUCCESS.
+	Fields []string
+	// Records are the rows streamed in response to PULL, one value per field.
+	Records [][]interface{}
+	// Summary is merged into the SUCCESS that ends the stream (for example
+	// "bookmark" or "stats").
+	Summary map[string]interface{}
+	// FailureCode and FailureMessage, when FailureCode is set, make the RUN
+	// answer with FAILURE instead.
+	FailureCode    string
+	FailureMessage string
+}
+
+// Server is a scripted Bolt server. Queries are matched by exact text; a RUN of
+// an unscripted query fails with Neo.ClientError.Statement.SyntaxError.
+type Server struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	results  map[string]Result
+	requests []messaging.Message
+	conns    map[net.Conn]struct{}
+	closed   bool
+
+	wg sync.WaitGroup
+}
+
+// New starts a server listening on a random local TCP port.
+func New() (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		listener: listener,
+		results:  make(map[string]Result),
+		conns:    make(map[net.Conn]struct{}),
+	}
+	s.wg.Add(1)
+	go s.acceptLoop()
+	return s, nil
+}
+
+// Addr returns the host:port the server listens on.
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// URL returns a neo4j:// connection URL pointing at the server.
+func (s *Server) URL() string {
+	return "neo4j://neo4j:password@" + s.Addr()
+}
+
+// Handle scripts the response to query.
+func (s *Server) Handle(query string, result Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results[query] = result
+}
+
+// Requests returns every message received from clients so far, in order.
+func (s *Server) Requests() []messaging.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]messaging.Message(nil), s.requests...)
+}
+
+// Close stops accepting connections, closes the open ones and waits for
+// their handlers to return.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) acceptLoop() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			_ = s.ServeConn(conn)
+		}()
+	}
+}
+
+// ServeConn runs the Bolt protocol on conn until the client says GOODBYE or
+// the connection is closed. It can be used directly with one end of a
+// net.Pipe. The connection is closed when ServeConn returns.
+func (s *Server) ServeConn(conn net.Conn) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return conn.Close()
+	}
+	s.conns[conn] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		_ = conn.Close()
+	}()
+
+	if err := handshake(conn); err != nil {
+		return err
+	}
+
+	sess := &session{server: s, conn: conn}
+	for {
+		msg, err := readMessage(conn)
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, msg)
+		s.mu.Unlock()
+
+		if msg.Signature() == messaging.GoodbyeSignature {
+			return nil
+		}
+		if err := sess.handle(msg); err != nil {
+			return err
+		}
+	}
+}
+
+// handshake reads the client preamble and agrees on Bolt 5.8.
+func handshake(conn net.Conn) error {
+	preamble := make([]byte, 20)
+	if _, err := io.ReadFull(conn, preamble); err != nil {
+		return err
+	}
+	if !bytes.Equal(preamble[:4], boltMagic) {
+		return fmt.Errorf("testserver: bad handshake magic % X", preamble[:4])
+	}
+	_, err := conn.Write([]byte{0, 0, 8, 5})
+	return err
+}
+
+// session holds the per-connection protocol state.
+type session struct {
+	server  *Server
+	conn    net.Conn
+	failed  bool // a FAILURE was sent; everything but RESET is IGNORED
+	pending *stream
+}
+
+// stream is the not yet pulled remainder of a RUN.
+type stream struct {
+	records [][]interface{}
+	summary map[string]interface{}
+}
+
+func (sess *session) handle(msg messaging.Message) error {
+	if sess.failed && msg.Signature() != messaging.ResetSignature {
+		return sess.write(messaging.IgnoredSignature)
+	}
+
+	switch msg.Signature() {
+	case messaging.HelloSignature:
+		return sess.success(map[string]interface{}{
+			"server":        "Neo4j/5.26.0",
+			"connection_id": "bolt-testserver",
+		})
+	case messaging.LogonSignature, messaging.BeginSignature,
+		messaging.CommitSignature, messaging.RollbackSignature:
+		return sess.success(map[string]interface{}{})
+	case messaging.ResetSignature:
+		sess.failed = false
+		sess.pending = nil
+		return sess.success(map[string]interface{}{})
+	case messaging.RouteSignature:
+		addr := sess.server.Addr()
+		servers := []interface{}{}
+		for _, role := range []string{"ROUTE", "READ", "WRITE"} {
+			servers = append(servers, map[string]interface{}{
+				"role":      role,
+				"addresses": []interface{}{addr},
+			})
+		}
+		return sess.success(map[string]interface{}{
+			"rt": map[string]interface{}{"ttl": 300, "servers": servers},
+		})
+	case messaging.RunSignature:
+		return sess.run(msg.Fields())
+	case messaging.PullSignature:
+		return sess.pull(requestSize(msg.Fields()))
+	case messaging.DiscardSignature:
+		return sess.pull(0)
+	default:
+		return sess.failure("Neo.ClientError.Request.Invalid",
+			fmt.Sprintf("testserver: unsupported message 0x%02X", msg.Signature()))
+	}
+}
+
+func (sess *session) run(fields []interface{}) error {
+	query, _ := fieldAt(fields, 0).(string)
+
+	sess.server.mu.Lock()
+	result, ok := sess.server.results[query]
+	sess.server.mu.Unlock()
+	if !ok {
+		return sess.failure("Neo.ClientError.Statement.SyntaxError", "testserver: no result scripted for query: "+query)
+	}
+	if result.FailureCode != "" {
+		return sess.failure(result.FailureCode, result.FailureMessage)
+	}
+
+	keys := make([]interface{}, len(result.Fields))
+	for i, f := range result.Fields {
+		keys[i] = f
+	}
+	sess.pending = &stream{records: result.Records, summary: result.Summary}
+	return sess.success(map[string]interface{}{"fields": keys, "t_first": 0})
+}
+
+// pull sends up to n records (all of them when n < 0) followed by a SUCCESS
+// that either announces more or ends the stream. n == 0 discards the rest.
+func (sess *session) pull(n int) error {
+	if sess.pending == nil {
+		return sess.failure("Neo.ClientError.Request.Invalid", "testserver: no open result to pull from")
+	}
+	records := sess.pending.records
+	if n >= 0 && n < len(records) {
+		if n > 0 {
+			records = records[:n]
+		} else {
+			records = nil
+		}
+	}
+	for _, rec := range records {
+		if err := sess.write(messaging.RecordSignature, rec); err != nil {
+			return err
+		}
+	}
+	sess.pending.records = sess.pending.records[len(records):]
+
+	if n != 0 && len(sess.pending.records) > 0 {
+		return sess.success(map[string]interface{}{"has_more": true})
+	}
+	summary := map[string]interface{}{"t_last": 0}
+	for k, v := range sess.pending.summary {
+		summary[k] = v
+	}
+	sess.pending = nil
+	return sess.success(summary)
+}
+
+func (sess *session) success(metadata map[string]interface{}) error {
+	return sess.write(messaging.SuccessSignature, metadata)
+}
+
+func (sess *session) failure(code, message string) error {
+	sess.failed = true
+	sess.pending = nil
+	return sess.write(messaging.FailureSignature, map[string]interface{}{
+		"code":    code,
+		"message": message,
+	})
+}
+
+// write sends one chunked message.
+func (sess *session) write(signature byte, fields ...interface{}) error {
+	data, err := messaging.PackMessage(signature, fields)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	for len(data) > 0 {
+		size := len(data)
+		if size > 0xFFFF {
+			size = 0xFFFF
+		}
+		var header [2]byte
+		binary.BigEndian.PutUint16(header[:], uint16(size))
+		buf.Write(header[:])
+		buf.Write(data[:size])
+		data = data[size:]
+	}
+	buf.Write([]byte{0x00, 0x00})
+	_, err = sess.conn.Write(buf.Bytes())
+	return err
+}
+
+// readMessage reads one client message. Unlike messaging.ReadChunkedMessage
+// it blocks without a deadline, since clients may idle between requests.
+func readMessage(conn net.Conn) (messaging.Message, error) {
+	var data bytes.Buffer
+	header := make([]byte, 2)
+	for {
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return nil, err
+		}
+		size := binary.BigEndian.Uint16(header)
+		if size == 0 {
+			if data.Len() == 0 {
+				continue
+			}
+			break
+		}
+		if _, err := io.CopyN(&data, conn, int64(size)); err != nil {
+			return nil, err
+		}
+	}
+	return messaging.DecodeMessage(data.Bytes())
+}
+
+// requestSize returns the "n" of a PULL, defaulting to all records.
+func requestSize(fields []interface{}) int {
+	meta, _ := fieldAt(fields, 0).(map[string]interface{})
+	if n, ok := meta["n"].(int64); ok {
+		return int(n)
+	}
+	return -1
+}
+
+func fieldAt(fields []interface{}, i int) interface{} {
+	if i < len(fields) {
+		return fields[i]
+	}
+	return nil
+}
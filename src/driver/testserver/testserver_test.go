@@ -0,0 +1,124 @@
+package testserver_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/seuros/gopher-cypher/src/bolt/messaging"
+	"github.com/seuros/gopher-cypher/src/driver"
+	"github.com/seuros/gopher-cypher/src/driver/testserver"
+)
+
+func newServer(t *testing.T) *testserver.Server {
+	t.Helper()
+	srv, err := testserver.New()
+	if err != nil {
+		t.Fatalf("failed to start test server: %v", err)
+	}
+	t.Cleanup(func() { _ = srv.Close() })
+	return srv
+}
+
+func newDriver(t *testing.T, srv *testserver.Server, fetchSize int) driver.Driver {
+	t.Helper()
+	config := driver.DefaultConfig()
+	config.FetchSize = fetchSize
+	d, err := driver.NewDriverWithConfig(srv.URL(), config)
+	if err != nil {
+		t.Fatalf("failed to create driver: %v", err)
+	}
+	t.Cleanup(func() { _ = d.Close() })
+	return d
+}
+
+func TestServer_Run(t *testing.T) {
+	srv := newServer(t)
+	srv.Handle("MATCH (p:Person) RETURN p.name AS name, p.age AS age", testserver.Result{
+		Fields: []string{"name", "age"},
+		Records: [][]interface{}{
+			{"Alice", 30},
+			{"Bob", 42},
+		},
+		Summary: map[string]interface{}{"bookmark": "bm:1"},
+	})
+	d := newDriver(t, srv, 1000)
+
+	cols, rows, err := d.Run(context.Background(), "MATCH (p:Person) RETURN p.name AS name, p.age AS age", nil, nil)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(cols) != 2 || cols[0] != "name" || cols[1] != "age" {
+		t.Fatalf("unexpected columns %v", cols)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	if rows[0]["name"] != "Alice" || rows[0]["age"] != int64(30) {
+		t.Errorf("unexpected first row %v", rows[0])
+	}
+	if rows[1]["name"] != "Bob" || rows[1]["age"] != int64(42) {
+		t.Errorf("unexpected second row %v", rows[1])
+	}
+
+	var sawHello, sawLogon bool
+	for _, msg := range srv.Requests() {
+		switch msg.Signature() {
+		case messaging.HelloSignature:
+			sawHello = true
+		case messaging.LogonSignature:
+			sawLogon = true
+		}
+	}
+	if !sawHello || !sawLogon {
+		t.Errorf("expected HELLO and LOGON, saw hello=%v logon=%v", sawHello, sawLogon)
+	}
+}
+
+func TestServer_RunFailure(t *testing.T) {
+	srv := newServer(t)
+	srv.Handle("RETURN oops", testserver.Result{
+		FailureCode:    "Neo.ClientError.Statement.SyntaxError",
+		FailureMessage: "Variable `oops` not defined",
+	})
+	d := newDriver(t, srv, 1000)
+
+	_, _, err := d.Run(context.Background(), "RETURN oops", nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "SyntaxError") {
+		t.Fatalf("expected a syntax error, got %v", err)
+	}
+}
+
+func TestServer_RunStreamPullsInBatches(t *testing.T) {
+	srv := newServer(t)
+	srv.Handle("UNWIND range(1, 5) AS n RETURN n", testserver.Result{
+		Fields:  []string{"n"},
+		Records: [][]interface{}{{1}, {2}, {3}, {4}, {5}},
+	})
+	d := newDriver(t, srv, 2)
+
+	result, err := d.(driver.StreamingDriver).RunStream(context.Background(), "UNWIND range(1, 5) AS n RETURN n", nil, nil)
+	if err != nil {
+		t.Fatalf("RunStream failed: %v", err)
+	}
+	records, err := result.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if len(records) != 5 {
+		t.Fatalf("expected 5 records, got %d", len(records))
+	}
+	if v := (*records[4])["n"]; v != int64(5) {
+		t.Errorf("expected last record n=5, got %v", v)
+	}
+
+	pulls := 0
+	for _, msg := range srv.Requests() {
+		if msg.Signature() == messaging.PullSignature {
+			pulls++
+		}
+	}
+	if pulls != 3 {
+		t.Errorf("expected 3 PULLs with fetch size 2, got %d", pulls)
+	}
+}
@@ -70,36 +70,60 @@ func CheckVersion(conn net.Conn) (major, minor byte, err error) {
 	return major, minor, nil
 }
 
-// SendHello performs the HELLO handshake with the server.
-func SendHello(conn net.Conn) error {
+// HelloMetadata builds the HELLO metadata map. Entries in extra (for example
+// notification filters) are added on top of the agent identification.
+func HelloMetadata(extra map[string]interface{}) map[string]interface{} {
 	version := getLibraryVersion()
 	userAgent := fmt.Sprintf("gopher-cypher::Bolt/%s (Go/%s)", version, runtime.Version()[2:]) // Remove "go" prefix
 	platform := fmt.Sprintf("go %s [%s-%s]", runtime.Version()[2:], runtime.GOARCH, runtime.GOOS)
 
-	message := messaging.NewHello(map[string]interface{}{
-		"user_agent":                     userAgent,
-		"notifications_minimum_severity": "WARNING",
+	metadata := map[string]interface{}{
+		"user_agent": userAgent,
 		"bolt_agent": map[string]interface{}{
 			"product":          userAgent,
 			"platform":         platform,
 			"language":         fmt.Sprintf("%s/%s", runtime.GOOS, runtime.Version()),
 			"language_details": fmt.Sprintf("%s %s", runtime.Compiler, runtime.Version()),
 		},
-	})
+	}
+	for k, v := range extra {
+		metadata[k] = v
+	}
+	return metadata
+}
 
-	_, err := message.Send(conn)
+// SendHello performs the HELLO handshake with the server.
+func SendHello(conn net.Conn, extra map[string]interface{}) error {
+	_, err := Hello(conn, extra, 0)
 	return err
 }
 
+// Hello performs the HELLO handshake and returns the metadata of the
+// server's SUCCESS, or nil if it answered with anything else. The response
+// may be at most maxSize bytes; <= 0 uses messaging.DefaultMaxMessageSize.
+func Hello(conn net.Conn, extra map[string]interface{}, maxSize int) (map[string]interface{}, error) {
+	message := messaging.NewHello(HelloMetadata(extra))
+
+	response, err := message.SendLimit(conn, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	if success, ok := response.(*messaging.Success); ok {
+		return success.Metadata(), nil
+	}
+	return nil, nil
+}
+
 // Authenticate sends logon credentials to the server and checks for failure.
-func Authenticate(conn net.Conn, urlResolver *connection_url_resolver.ConnectionUrlResolver) error {
+// The response may be at most maxSize bytes, as for Hello.
+func Authenticate(conn net.Conn, urlResolver *connection_url_resolver.ConnectionUrlResolver, maxSize int) error {
 	messageLogon := messaging.NewLogon(map[string]interface{}{
 		"scheme":      "basic",
 		"principal":   urlResolver.ToHash().Username,
 		"credentials": urlResolver.ToHash().Password,
 	})
 
-	response, err := messageLogon.Send(conn)
+	response, err := messageLogon.SendLimit(conn, maxSize)
 	if err != nil {
 		return err
 	}
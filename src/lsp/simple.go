@@ -10,8 +10,8 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"unicode/utf16"
 
-	"github.com/alecthomas/participle/v2"
 	"github.com/seuros/gopher-cypher/src/parser"
 )
 
@@ -216,23 +216,35 @@ func (s *SimpleServer) handleDidChange(params interface{}) {
 }
 
 func (s *SimpleServer) publishDiagnostics(uri, text string) {
+	s.sendNotification("textDocument/publishDiagnostics", map[string]interface{}{
+		"uri":         uri,
+		"diagnostics": s.diagnostics(text),
+	})
+}
+
+// diagnostics parses text and reports the parse error, if any.
+func (s *SimpleServer) diagnostics(text string) []Diagnostic {
 	var diags []Diagnostic
 
 	if _, err := s.parser.Parse(text); err != nil {
 		start := Position{Line: 0, Character: 0}
 		end := Position{Line: 0, Character: 1}
 
-		var perr participle.Error
-		if errors.As(err, &perr) {
-			pos := perr.Position()
-			if pos.Line > 0 {
-				start.Line = pos.Line - 1
+		var serr *parser.SyntaxError
+		if errors.As(err, &serr) {
+			if serr.Line > 0 {
+				start.Line = serr.Line - 1
 				end.Line = start.Line
 			}
-			if pos.Column > 0 {
-				start.Character = pos.Column - 1
+			if serr.Column > 0 {
+				start.Character = serr.Column - 1
 				end.Character = start.Character + 1
 			}
+		} else if offset := rejectedCharOffset(text); offset >= 0 {
+			// Input validation errors carry no position; point at the
+			// character the parser rejected instead.
+			start = offsetPosition(text, offset)
+			end = Position{Line: start.Line, Character: start.Character + 1}
 		}
 
 		diags = append(diags, Diagnostic{
@@ -246,10 +258,26 @@ func (s *SimpleServer) publishDiagnostics(uri, text string) {
 		})
 	}
 
-	s.sendNotification("textDocument/publishDiagnostics", map[string]interface{}{
-		"uri":         uri,
-		"diagnostics": diags,
-	})
+	return diags
+}
+
+// rejectedCharOffset returns the byte offset of the first character the
+// parser's input validation rejects, checked in the same order (statement
+// separators, then single quotes), or -1 if there is none.
+func rejectedCharOffset(text string) int {
+	if i := strings.IndexByte(text, ';'); i >= 0 {
+		return i
+	}
+	return strings.IndexByte(text, '\'')
+}
+
+// offsetPosition converts a byte offset into an LSP position, whose
+// character counts UTF-16 code units.
+func offsetPosition(text string, offset int) Position {
+	before := text[:offset]
+	line := strings.Count(before, "\n")
+	lineStart := strings.LastIndexByte(before, '\n') + 1
+	return Position{Line: line, Character: len(utf16.Encode([]rune(before[lineStart:])))}
 }
 
 // readMessage reads a single JSON-RPC message according to LSP framing.
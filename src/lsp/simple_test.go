@@ -0,0 +1,53 @@
+package lsp
+
+import (
+	"testing"
+
+	"github.com/seuros/gopher-cypher/src/parser"
+)
+
+func TestDiagnostics_ValidationErrorRange(t *testing.T) {
+	p, err := parser.New()
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+	s := &SimpleServer{parser: p, documents: map[string]string{}}
+
+	tests := []struct {
+		name string
+		text string
+		want Position
+	}{
+		{name: "single quote", text: "MATCH (n)\nWHERE n.name = 'Ann'\nRETURN n", want: Position{Line: 1, Character: 15}},
+		{name: "semicolon", text: "MATCH (n) RETURN n; MATCH (m) RETURN m", want: Position{Line: 0, Character: 18}},
+		{name: "utf16 columns", text: "MATCH (n) WHERE n.é = 'x' RETURN n", want: Position{Line: 0, Character: 22}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := s.diagnostics(tt.text)
+			if len(diags) != 1 {
+				t.Fatalf("expected one diagnostic, got %v", diags)
+			}
+			r := diags[0].Range
+			if r.Start != tt.want {
+				t.Errorf("expected start %+v, got %+v", tt.want, r.Start)
+			}
+			if r.End != (Position{Line: tt.want.Line, Character: tt.want.Character + 1}) {
+				t.Errorf("expected a one-character range, got %+v", r)
+			}
+		})
+	}
+}
+
+func TestDiagnostics_ValidQuery(t *testing.T) {
+	p, err := parser.New()
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+	s := &SimpleServer{parser: p, documents: map[string]string{}}
+
+	if diags := s.diagnostics("MATCH (n) RETURN n"); len(diags) != 0 {
+		t.Errorf("expected no diagnostics, got %v", diags)
+	}
+}
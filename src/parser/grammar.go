@@ -1,5 +1,11 @@
 package parser
 
+import (
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
 type Query struct {
 	Clauses []*Clause `@@+`
 }
@@ -11,19 +17,48 @@ type Clause struct {
 	Where  *WhereClause  `| @@`
 	Set    *SetClause    `| @@`
 	Remove *RemoveClause `| @@`
+	Delete *DeleteClause `| @@`
+	With   *WithClause   `| @@`
 	Return *ReturnClause `| @@`
 	Skip   *SkipClause   `| @@`
 	Limit  *LimitClause  `| @@`
 }
 
 type MatchClause struct {
-	Optional bool     `"OPTIONAL"?`
+	Optional bool     `@"OPTIONAL"?`
 	Pattern  *Pattern `"MATCH" @@`
 }
 
 type Pattern struct {
-	Variable string `"(" @Ident`
-	Label    string `(":" @Ident)? ")"`
+	Start *NodePattern    `@@`
+	Chain []*PatternChain `@@*`
+}
+
+type NodePattern struct {
+	Variable   string       `"(" @(Ident | QuotedIdent)?`
+	Label      string       `(":" @(Ident | QuotedIdent))?`
+	Properties *PropertyMap `@@? ")"`
+}
+
+type PropertyMap struct {
+	Entries []*PropertyEntry `"{" (@@ ("," @@)*)? "}"`
+}
+
+type PropertyEntry struct {
+	Key   string `@(Ident | QuotedIdent) ":"`
+	Value *Value `@@`
+}
+
+type PatternChain struct {
+	Relationship *RelationshipPattern `@@`
+	Node         *NodePattern         `@@`
+}
+
+type RelationshipPattern struct {
+	Incoming bool   `@"<"? "-"`
+	Variable string `("[" @(Ident | QuotedIdent)?`
+	Type     string `(":" @(Ident | QuotedIdent))? "]")?`
+	Outgoing bool   `"-" @">"?`
 }
 
 type WhereClause struct {
@@ -31,21 +66,36 @@ type WhereClause struct {
 }
 
 type Condition struct {
-	Left     *PropertyAccess `@@`
-	Operator string          `@(">" | "<" | "=" | ">=" | "<=" | "!=")`
-	Right    *Value          `@@`
+	Left           *PropertyAccess `@@`
+	Operator       string          `( ( @(">" | "<" | "=" | ">=" | "<=" | "<>" | "!=" | "=~" | "CONTAINS")`
+	StringOperator string          `  | @("STARTS" | "ENDS") "WITH" )`
+	Right          *Value          `  @@`
+	NullCheck      bool            `| @"IS"`
+	NotNull        bool            `  @"NOT"? "NULL" )`
 }
 
 type PropertyAccess struct {
-	Variable string `@Ident`
-	Property string `"." @Ident`
+	Tokens   []lexer.Token
+	Variable string `@(Ident | QuotedIdent)`
+	Property string `"." @(Ident | QuotedIdent)`
 }
 
 type Value struct {
-	String *string `  @String`
-	Number *int    `| @Int`
-	Param  *string `| @Param`
-	List   *List   `| @@`
+	Tokens []lexer.Token
+
+	String *string  `  @String`
+	Number *int     `| @Int`
+	Bool   *Boolean `| @("true" | "false")`
+	Param  *string  `| @Param`
+	List   *List    `| @@`
+}
+
+// Boolean captures a true/false literal.
+type Boolean bool
+
+func (b *Boolean) Capture(values []string) error {
+	*b = Boolean(strings.EqualFold(values[0], "true"))
+	return nil
 }
 
 // SkipLimitValue removed
@@ -55,20 +105,31 @@ type List struct {
 }
 
 type ReturnClause struct {
-	Items []*ReturnItem `"RETURN" @@ ("," @@)*`
+	All   bool          `"RETURN" ( @"*"`
+	Items []*ReturnItem `  ("," @@)* | @@ ("," @@)* )`
+}
+
+type WithClause struct {
+	All   bool          `"WITH" ( @"*"`
+	Items []*ReturnItem `  ("," @@)* | @@ ("," @@)* )`
 }
 
 type ReturnItem struct {
 	Expression *ReturnExpression `@@`
-	Alias      *string           `("AS" @Ident)?`
+	Alias      *string           `("AS" @(Ident | QuotedIdent))?`
 }
 
 type ReturnExpression struct {
-	FunctionCall   *FunctionCall   `@@`
+	CountSubquery  *CountSubquery  `  @@`
+	FunctionCall   *FunctionCall   `| @@`
 	PropertyAccess *PropertyAccess `| @@`
 	MathExpression *MathExpression `| @@`
 }
 
+type CountSubquery struct {
+	Body []*Clause `"COUNT" "{" @@+ "}"`
+}
+
 type SimpleTerm struct {
 	Parameter *string `@Param`
 	Variable  *string `| @Ident`
@@ -77,27 +138,40 @@ type SimpleTerm struct {
 
 type MathExpression struct {
 	Left     *MathTerm `@@`
-	Operator string    `("+" | "-")?`
-	Right    *MathTerm `@@?`
+	Operator string    `(@("+" | "-")`
+	Right    *MathTerm `@@)?`
 }
 
 type MathTerm struct {
+	Tokens []lexer.Token
+
 	Parameter *string `@Param`
 	Variable  *string `| @Ident`
 	Number    *int    `| @Int`
 }
 
 type FunctionCall struct {
-	Name      string   `@Ident`
-	Arguments []*Value `"(" (@@ ("," @@)*)? ")"`
+	Name      string              `@Ident`
+	Arguments []*FunctionArgument `"(" (@@ ("," @@)*)? ")"`
+}
+
+type FunctionArgument struct {
+	FunctionCall   *FunctionCall   `  @@`
+	PropertyAccess *PropertyAccess `| @@`
+	Value          *Value          `| @@`
+	Variable       *string         `| @Ident`
 }
 
 type LimitClause struct {
+	Tokens []lexer.Token
+
 	LimitInt   *int    `  "LIMIT" @Int`
 	LimitParam *string `| "LIMIT" @Param`
 }
 
 type SkipClause struct {
+	Tokens []lexer.Token
+
 	SkipInt   *int    `  "SKIP" @Int`
 	SkipParam *string `| "SKIP" @Param`
 }
@@ -108,7 +182,7 @@ type MergeClause struct {
 
 type UnwindClause struct {
 	Expression *Value `"UNWIND" @@`
-	Alias      string `"AS" @Ident`
+	Alias      string `"AS" @(Ident | QuotedIdent)`
 }
 
 type SetClause struct {
@@ -123,3 +197,8 @@ type Assignment struct {
 type RemoveClause struct {
 	Properties []*PropertyAccess `"REMOVE" @@ ("," @@)*`
 }
+
+type DeleteClause struct {
+	Detach      bool     `@"DETACH"? "DELETE"`
+	Expressions []string `@(Ident | QuotedIdent) ("," @(Ident | QuotedIdent))*`
+}
@@ -1,7 +1,9 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/alecthomas/participle/v2"
@@ -11,29 +13,58 @@ import (
 
 var cypherLexer = lexer.MustSimple([]lexer.SimpleRule{
 	{Name: "String", Pattern: `"[^"]*"`},
-	{Name: "Param", Pattern: `\$[a-zA-Z_][a-zA-Z0-9_]*`}, // Added Param rule
+	// Param takes any dotted property path with it ($config.timeout), so
+	// property access on a map parameter stays a single token and renders
+	// back verbatim wherever parameters are passed through.
+	{Name: "Param", Pattern: `\$[a-zA-Z_][a-zA-Z0-9_]*(?:\.[a-zA-Z_][a-zA-Z0-9_]*)*`},
 	{Name: "Ident", Pattern: `[a-zA-Z_][a-zA-Z0-9_]*`},
+	// QuotedIdent keeps its backticks so names render back verbatim; a
+	// doubled backtick escapes a literal one.
+	{Name: "QuotedIdent", Pattern: "`(?:[^`]|``)+`"},
 	{Name: "Int", Pattern: `\d+`},
-	{Name: "Operators", Pattern: `>=|<=|!=|>|<|=`},
-	{Name: "Punct", Pattern: `[(),.:\[\]\+\-]`}, // Removed $ from Punct
+	{Name: "Operators", Pattern: `>=|<=|<>|!=|=~|>|<|=`},
+	{Name: "Punct", Pattern: `[(),.:\[\]{}\+\-\*]`}, // Removed $ from Punct
 	{Name: "whitespace", Pattern: `\s+`},
 })
 
 type Parser struct {
 	parser *participle.Parser[Query]
+	// canonicalOrder sorts parsed clauses into the cypher package's
+	// canonical order instead of keeping them as written.
+	canonicalOrder bool
 }
 
-func New() (*Parser, error) {
+// Option configures a Parser.
+type Option func(*Parser)
+
+// CanonicalClauseOrder makes parsed queries compile their clauses in the
+// canonical order the cypher package sorts clauses into, rather than the
+// order they were written. Parsed queries used to be sorted this way by
+// default, so LIMIT 2 SKIP 1 was rendered as SKIP then LIMIT; they are now
+// rendered as written unless this option is given. Queries that repeat
+// clauses, such as MATCH ... WITH ... MATCH, change meaning when reordered,
+// so it only suits single-part queries.
+func CanonicalClauseOrder() Option {
+	return func(p *Parser) { p.canonicalOrder = true }
+}
+
+func New(opts ...Option) (*Parser, error) {
 	parser, err := participle.Build[Query](
 		participle.Lexer(cypherLexer),
 		participle.Unquote("String"),
-		participle.CaseInsensitive("MATCH", "WHERE", "RETURN", "LIMIT", "SKIP", "OPTIONAL", "MERGE", "UNWIND", "AS", "SET", "REMOVE"),
+		// Keywords are Ident tokens, so matching Ident literals without
+		// regard to case lets "match" and "MATCH" parse alike.
+		participle.CaseInsensitive("Ident"),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build parser: %w", err)
 	}
 
-	return &Parser{parser: parser}, nil
+	p := &Parser{parser: parser}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p, nil
 }
 
 func (p *Parser) Parse(input string) (*cypher.Query, error) {
@@ -43,10 +74,58 @@ func (p *Parser) Parse(input string) (*cypher.Query, error) {
 
 	query, err := p.parser.ParseString("", input)
 	if err != nil {
-		return nil, fmt.Errorf("parse error: %w", err)
+		return nil, newSyntaxError(clarifyParseError(err))
+	}
+
+	q, err := convertToAST(query)
+	if err != nil {
+		return nil, err
+	}
+	if !p.canonicalOrder {
+		q.PreserveClauseOrder()
+	}
+	return q, nil
+}
+
+// SyntaxError is a query that does not match the grammar. Line and Column
+// are 1-based and point at the offending token. It wraps the underlying
+// participle.Error.
+type SyntaxError struct {
+	Line    int
+	Column  int
+	Message string
+	err     error
+}
+
+// newSyntaxError converts a participle failure into a SyntaxError. Errors
+// without a position are wrapped as they were.
+func newSyntaxError(err error) error {
+	var perr participle.Error
+	if !errors.As(err, &perr) {
+		return fmt.Errorf("parse error: %w", err)
 	}
+	pos := perr.Position()
+	return &SyntaxError{Line: pos.Line, Column: pos.Column, Message: perr.Message(), err: err}
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("parse error: %d:%d: %s", e.Line, e.Column, e.Message)
+}
 
-	return convertToAST(query)
+func (e *SyntaxError) Unwrap() error {
+	return e.err
+}
+
+// clarifyParseError replaces participle's conversion failure for an
+// integer literal that overflows int64 with a readable error at the same
+// position.
+func clarifyParseError(err error) error {
+	var perr participle.Error
+	var numErr *strconv.NumError
+	if errors.As(err, &perr) && errors.As(err, &numErr) && errors.Is(numErr.Err, strconv.ErrRange) {
+		return participle.Errorf(perr.Position(), "integer literal %s is out of range for a 64-bit integer", numErr.Num)
+	}
+	return err
 }
 
 func validateInput(input string) error {
@@ -63,28 +142,28 @@ func validateInput(input string) error {
 
 func convertToAST(query *Query) (*cypher.Query, error) {
 	q := cypher.NewQuery()
+	for _, n := range convertClauses(q, query.Clauses) {
+		q.AddClause(cypher.NewClauseAdapter(n))
+	}
+	return q, nil
+}
 
-	for _, clause := range query.Clauses {
+// convertClauses converts parsed clauses into AST nodes, registering their
+// literal values as parameters on q.
+func convertClauses(q *cypher.Query, clauses []*Clause) []cypher.Node {
+	var nodes []cypher.Node
+	for _, clause := range clauses {
 		if clause.Match != nil {
-			pattern := fmt.Sprintf("(%s", clause.Match.Pattern.Variable)
-			if clause.Match.Pattern.Label != "" {
-				pattern += ":" + clause.Match.Pattern.Label
+			matchNode := &cypher.MatchNode{
+				Pattern:  renderPattern(q, clause.Match.Pattern),
+				Optional: clause.Match.Optional,
 			}
-			pattern += ")"
-
-			matchNode := &cypher.MatchNode{Pattern: pattern}
-			q.AddClause(cypher.NewClauseAdapter(matchNode))
+			nodes = append(nodes, matchNode)
 		}
 
 		if clause.Merge != nil {
-			pattern := fmt.Sprintf("(%s", clause.Merge.Pattern.Variable)
-			if clause.Merge.Pattern.Label != "" {
-				pattern += ":" + clause.Merge.Pattern.Label
-			}
-			pattern += ")"
-
-			mergeNode := &cypher.MergeNode{Pattern: pattern}
-			q.AddClause(cypher.NewClauseAdapter(mergeNode))
+			mergeNode := &cypher.MergeNode{Pattern: renderPattern(q, clause.Merge.Pattern)}
+			nodes = append(nodes, mergeNode)
 		}
 
 		if clause.Unwind != nil {
@@ -93,8 +172,12 @@ func convertToAST(query *Query) (*cypher.Query, error) {
 				expression = *clause.Unwind.Expression.String
 			} else if clause.Unwind.Expression.Number != nil {
 				expression = *clause.Unwind.Expression.Number
+				recordToken(q, expression, clause.Unwind.Expression.Tokens[0])
+			} else if clause.Unwind.Expression.Bool != nil {
+				expression = bool(*clause.Unwind.Expression.Bool)
+				recordToken(q, expression, clause.Unwind.Expression.Tokens[0])
 			} else if clause.Unwind.Expression.Param != nil {
-				expression = *clause.Unwind.Expression.Param // Removed "$"
+				expression = &cypher.ParameterExpr{Name: strings.TrimPrefix(*clause.Unwind.Expression.Param, "$")}
 			} else if clause.Unwind.Expression.List != nil {
 				elements := make([]interface{}, len(clause.Unwind.Expression.List.Elements))
 				for i, elem := range clause.Unwind.Expression.List.Elements {
@@ -102,8 +185,10 @@ func convertToAST(query *Query) (*cypher.Query, error) {
 						elements[i] = *elem.String
 					} else if elem.Number != nil {
 						elements[i] = *elem.Number
+					} else if elem.Bool != nil {
+						elements[i] = bool(*elem.Bool)
 					} else if elem.Param != nil {
-						elements[i] = *elem.Param // Removed "$"
+						elements[i] = &cypher.ParameterExpr{Name: strings.TrimPrefix(*elem.Param, "$")}
 					}
 				}
 				expression = elements
@@ -113,28 +198,48 @@ func convertToAST(query *Query) (*cypher.Query, error) {
 				Expression: expression,
 				AliasName:  clause.Unwind.Alias,
 			}
-			q.AddClause(cypher.NewClauseAdapter(unwindNode))
+			nodes = append(nodes, unwindNode)
 		}
 
 		if clause.Where != nil {
-			cond := &cypher.ComparisonExpr{
-				LHS: &cypher.PropertyAccessExpr{
-					Variable:     &cypher.LiteralExpr{Value: clause.Where.Condition.Left.Variable},
-					PropertyName: clause.Where.Condition.Left.Property,
-				},
-				Op: clause.Where.Condition.Operator,
+			condition := clause.Where.Condition
+			lhs := &cypher.PropertyAccessExpr{
+				Variable:     &cypher.VariableExpr{Name: condition.Left.Variable},
+				PropertyName: condition.Left.Property,
 			}
 
-			if clause.Where.Condition.Right.String != nil {
-				cond.RHS = &cypher.LiteralExpr{Value: *clause.Where.Condition.Right.String}
-			} else if clause.Where.Condition.Right.Number != nil {
-				cond.RHS = &cypher.LiteralExpr{Value: *clause.Where.Condition.Right.Number}
-			} else if clause.Where.Condition.Right.Param != nil {
-				cond.RHS = &cypher.LiteralExpr{Value: *clause.Where.Condition.Right.Param} // Removed "$"
+			var expr cypher.Expression
+			if condition.NullCheck {
+				expr = &cypher.NullCheckExpr{Operand: lhs, Negated: condition.NotNull}
+			} else {
+				// Keyword operators are captured as written; store them in
+				// canonical upper case.
+				cond := &cypher.ComparisonExpr{LHS: lhs, Op: strings.ToUpper(condition.Operator)}
+				if condition.StringOperator != "" {
+					cond.Op = strings.ToUpper(condition.StringOperator) + " WITH"
+				}
+				if cond.Op == "!=" {
+					// Accepted for convenience, but Cypher spells inequality <>.
+					cond.Op = "<>"
+				}
+
+				if condition.Right.String != nil {
+					cond.RHS = &cypher.LiteralExpr{Value: *condition.Right.String}
+					recordToken(q, *condition.Right.String, condition.Right.Tokens[0])
+				} else if condition.Right.Number != nil {
+					cond.RHS = &cypher.LiteralExpr{Value: *condition.Right.Number}
+					recordToken(q, *condition.Right.Number, condition.Right.Tokens[0])
+				} else if condition.Right.Bool != nil {
+					cond.RHS = &cypher.LiteralExpr{Value: bool(*condition.Right.Bool)}
+					recordToken(q, bool(*condition.Right.Bool), condition.Right.Tokens[0])
+				} else if condition.Right.Param != nil {
+					cond.RHS = &cypher.LiteralExpr{Value: *condition.Right.Param} // Removed "$"
+				}
+				expr = cond
 			}
 
-			whereNode := &cypher.WhereNode{Conditions: []cypher.Expression{cond}}
-			q.AddClause(cypher.NewClauseAdapter(whereNode))
+			whereNode := &cypher.WhereNode{Conditions: []cypher.Expression{expr}}
+			nodes = append(nodes, whereNode)
 		}
 
 		if clause.Set != nil {
@@ -145,6 +250,8 @@ func convertToAST(query *Query) (*cypher.Query, error) {
 					value = *assignment.Value.String
 				} else if assignment.Value.Number != nil {
 					value = *assignment.Value.Number
+				} else if assignment.Value.Bool != nil {
+					value = bool(*assignment.Value.Bool)
 				} else if assignment.Value.Param != nil {
 					value = *assignment.Value.Param // Removed "$"
 				}
@@ -156,7 +263,7 @@ func convertToAST(query *Query) (*cypher.Query, error) {
 				}
 			}
 			setNode := &cypher.SetNode{Assignments: assignments}
-			q.AddClause(cypher.NewClauseAdapter(setNode))
+			nodes = append(nodes, setNode)
 		}
 
 		if clause.Remove != nil {
@@ -166,94 +273,252 @@ func convertToAST(query *Query) (*cypher.Query, error) {
 				items[i] = &cypher.PropertyRemoval{Property: property}
 			}
 			removeNode := &cypher.RemoveNode{Items: items}
-			q.AddClause(cypher.NewClauseAdapter(removeNode))
+			nodes = append(nodes, removeNode)
 		}
 
-		if clause.Return != nil {
-			items := make([]interface{}, len(clause.Return.Items))
-			for i, item := range clause.Return.Items {
-				var baseItem interface{}
-
-				if item.Expression != nil {
-					expr := item.Expression
-					if expr.MathExpression != nil {
-						leftVal := convertMathTerm(expr.MathExpression.Left)
-
-						// Check if this is a full math expression or just a single term
-						if expr.MathExpression.Operator != "" && expr.MathExpression.Right != nil {
-							rightVal := convertMathTerm(expr.MathExpression.Right)
-							baseItem = &cypher.MathExpr{
-								Left:     leftVal,
-								Operator: expr.MathExpression.Operator,
-								Right:    rightVal,
-							}
-						} else {
-							// Just a single term, use it directly
-							baseItem = leftVal
-						}
-					} else if expr.FunctionCall != nil {
-						// Convert function arguments
-						args := make([]interface{}, len(expr.FunctionCall.Arguments))
-						for j, arg := range expr.FunctionCall.Arguments {
-							if arg.String != nil {
-								args[j] = *arg.String
-							} else if arg.Number != nil {
-								args[j] = *arg.Number
-							} else if arg.Param != nil {
-								args[j] = *arg.Param // Removed "$"
-							}
-						}
-
-						baseItem = &cypher.FunctionCallExpr{
-							Name:      expr.FunctionCall.Name,
-							Arguments: args,
-						}
-					} else if expr.PropertyAccess != nil {
-						baseItem = &cypher.PropertyAccessExpr{
-							Variable:     &cypher.LiteralExpr{Value: expr.PropertyAccess.Variable},
-							PropertyName: expr.PropertyAccess.Property,
-						}
-					}
-				}
+		if clause.Delete != nil {
+			expressions := make([]interface{}, len(clause.Delete.Expressions))
+			for i, expr := range clause.Delete.Expressions {
+				expressions[i] = expr
+			}
+			deleteNode := &cypher.DeleteNode{Expressions: expressions, Detach: clause.Delete.Detach}
+			nodes = append(nodes, deleteNode)
+		}
 
-				// Handle aliases if present
-				if item.Alias != nil && baseItem != nil {
-					items[i] = &cypher.AliasExpr{
-						Expression: baseItem,
-						Alias:      *item.Alias,
-					}
-				} else {
-					items[i] = baseItem
-				}
+		if clause.With != nil {
+			withNode := &cypher.WithNode{
+				Items: convertProjectionItems(q, clause.With.Items),
+				All:   clause.With.All,
 			}
-			returnNode := &cypher.ReturnNode{Items: items}
-			q.AddClause(cypher.NewClauseAdapter(returnNode))
+			nodes = append(nodes, withNode)
+		}
+
+		if clause.Return != nil {
+			returnNode := &cypher.ReturnNode{
+				Items: convertProjectionItems(q, clause.Return.Items),
+				All:   clause.Return.All,
+			}
+			nodes = append(nodes, returnNode)
 		}
 
 		if clause.Limit != nil {
 			var expressionValue interface{}
 			if clause.Limit.LimitInt != nil {
 				expressionValue = *clause.Limit.LimitInt
+				recordToken(q, expressionValue, clause.Limit.Tokens[len(clause.Limit.Tokens)-1])
 			} else if clause.Limit.LimitParam != nil {
 				expressionValue = *clause.Limit.LimitParam // Removed "$"
 			}
 			limitNode := &cypher.LimitNode{Expression: expressionValue}
-			q.AddClause(cypher.NewClauseAdapter(limitNode))
+			nodes = append(nodes, limitNode)
 		}
 
 		if clause.Skip != nil {
 			var amountValue interface{}
 			if clause.Skip.SkipInt != nil {
 				amountValue = *clause.Skip.SkipInt
+				recordToken(q, amountValue, clause.Skip.Tokens[len(clause.Skip.Tokens)-1])
 			} else if clause.Skip.SkipParam != nil {
 				amountValue = *clause.Skip.SkipParam // Removed "$"
 			}
 			skipNode := &cypher.SkipNode{Amount: amountValue}
-			q.AddClause(cypher.NewClauseAdapter(skipNode))
+			nodes = append(nodes, skipNode)
 		}
 	}
 
-	return q, nil
+	return nodes
+}
+
+// convertProjectionItems converts the items of a RETURN or WITH clause.
+func convertProjectionItems(q *cypher.Query, parsed []*ReturnItem) []interface{} {
+	items := make([]interface{}, len(parsed))
+	for i, item := range parsed {
+		var baseItem interface{}
+
+		if item.Expression != nil {
+			expr := item.Expression
+			if expr.MathExpression != nil {
+				leftVal := convertMathTerm(expr.MathExpression.Left)
+
+				// Check if this is a full math expression or just a single term
+				if expr.MathExpression.Operator != "" && expr.MathExpression.Right != nil {
+					// Both operands of a MathExpr become parameters.
+					rightVal := convertMathTerm(expr.MathExpression.Right)
+					recordToken(q, leftVal, expr.MathExpression.Left.Tokens[0])
+					recordToken(q, rightVal, expr.MathExpression.Right.Tokens[0])
+					baseItem = &cypher.MathExpr{
+						Left:     leftVal,
+						Operator: expr.MathExpression.Operator,
+						Right:    rightVal,
+					}
+				} else {
+					// Just a single term, use it directly
+					baseItem = leftVal
+					if expr.MathExpression.Left.Number != nil {
+						recordToken(q, leftVal, expr.MathExpression.Left.Tokens[0])
+					}
+				}
+			} else if expr.CountSubquery != nil {
+				baseItem = &cypher.CountExpr{Body: convertClauses(q, expr.CountSubquery.Body)}
+			} else if expr.FunctionCall != nil {
+				baseItem = convertFunctionCall(q, expr.FunctionCall)
+			} else if expr.PropertyAccess != nil {
+				baseItem = &cypher.PropertyAccessExpr{
+					Variable:     &cypher.VariableExpr{Name: expr.PropertyAccess.Variable},
+					PropertyName: expr.PropertyAccess.Property,
+				}
+			}
+		}
+
+		// Handle aliases if present
+		if item.Alias != nil && baseItem != nil {
+			items[i] = &cypher.AliasExpr{
+				Expression: baseItem,
+				Alias:      *item.Alias,
+			}
+		} else {
+			items[i] = baseItem
+		}
+	}
+	return items
+}
+
+// convertFunctionCall converts a function call, recursing into arguments
+// that are themselves function calls.
+func convertFunctionCall(q *cypher.Query, fn *FunctionCall) *cypher.FunctionCallExpr {
+	args := make([]interface{}, len(fn.Arguments))
+	for j, fnArg := range fn.Arguments {
+		switch {
+		case fnArg.FunctionCall != nil:
+			args[j] = convertFunctionCall(q, fnArg.FunctionCall)
+		case fnArg.PropertyAccess != nil:
+			args[j] = &cypher.PropertyAccessExpr{
+				Variable:     &cypher.VariableExpr{Name: fnArg.PropertyAccess.Variable},
+				PropertyName: fnArg.PropertyAccess.Property,
+			}
+		case fnArg.Variable != nil:
+			args[j] = &cypher.VariableExpr{Name: *fnArg.Variable}
+		default:
+			arg := fnArg.Value
+			if arg.String != nil {
+				args[j] = *arg.String
+				recordToken(q, args[j], arg.Tokens[0])
+			} else if arg.Number != nil {
+				args[j] = *arg.Number
+				recordToken(q, args[j], arg.Tokens[0])
+			} else if arg.Param != nil {
+				args[j] = *arg.Param // Removed "$"
+			}
+		}
+	}
+
+	return &cypher.FunctionCallExpr{
+		Name:      fn.Name,
+		Arguments: args,
+	}
+}
+
+// renderPattern converts a parsed pattern back into its Cypher text form.
+// Literal property values are registered as parameters on q.
+func renderPattern(q *cypher.Query, p *Pattern) string {
+	var sb strings.Builder
+	renderNodePattern(&sb, q, p.Start)
+	for _, link := range p.Chain {
+		rel := link.Relationship
+		if rel.Incoming {
+			sb.WriteString("<")
+		}
+		sb.WriteString("-")
+		if rel.Variable != "" || rel.Type != "" {
+			sb.WriteString("[")
+			sb.WriteString(rel.Variable)
+			if rel.Type != "" {
+				sb.WriteString(":" + rel.Type)
+			}
+			sb.WriteString("]")
+		}
+		sb.WriteString("-")
+		if rel.Outgoing {
+			sb.WriteString(">")
+		}
+		renderNodePattern(&sb, q, link.Node)
+	}
+	return sb.String()
+}
+
+func renderNodePattern(sb *strings.Builder, q *cypher.Query, n *NodePattern) {
+	sb.WriteString("(" + n.Variable)
+	if n.Label != "" {
+		sb.WriteString(":" + n.Label)
+	}
+	if n.Properties != nil {
+		if n.Variable != "" || n.Label != "" {
+			sb.WriteString(" ")
+		}
+		sb.WriteString("{")
+		for i, entry := range n.Properties.Entries {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(entry.Key + ": " + renderPropertyValue(q, entry.Value))
+		}
+		sb.WriteString("}")
+	}
+	sb.WriteString(")")
+}
+
+// renderPropertyValue renders a property map value: parameter references are
+// kept as written and literals become query parameters.
+func renderPropertyValue(q *cypher.Query, v *Value) string {
+	switch {
+	case v.Param != nil:
+		return *v.Param
+	case v.List != nil:
+		items := make([]string, len(v.List.Elements))
+		for i, elem := range v.List.Elements {
+			items[i] = renderPropertyValue(q, elem)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case v.String != nil:
+		recordToken(q, *v.String, v.Tokens[0])
+		return "$" + q.RegisterParameter(*v.String)
+	case v.Number != nil:
+		recordToken(q, *v.Number, v.Tokens[0])
+		return "$" + q.RegisterParameter(*v.Number)
+	case v.Bool != nil:
+		recordToken(q, bool(*v.Bool), v.Tokens[0])
+		return "$" + q.RegisterParameter(bool(*v.Bool))
+	}
+	return "null"
+}
+
+// stringToken is the lexer type of double-quoted strings.
+var stringToken = cypherLexer.Symbols()["String"]
+
+// recordToken records that the literal value was written as tok, so
+// Query.ParameterSpans can point back at it. Only record literals that
+// compile to parameters.
+func recordToken(q *cypher.Query, value interface{}, tok lexer.Token) {
+	raw := tok.Value
+	if tok.Type == stringToken {
+		// Strings were unquoted by the lexer; count their quotes back in.
+		raw = `"` + raw + `"`
+	}
+	end := tok.Pos
+	end.Offset += len(raw)
+	for _, r := range raw {
+		if r == '\n' {
+			end.Line++
+			end.Column = 1
+		} else {
+			end.Column++
+		}
+	}
+	q.RecordSpan(value, cypher.SourceSpan{
+		Offset: tok.Pos.Offset, EndOffset: end.Offset,
+		Line: tok.Pos.Line, Column: tok.Pos.Column,
+		EndLine: end.Line, EndColumn: end.Column,
+	})
 }
 
 func convertMathTerm(term *MathTerm) interface{} {
@@ -1,7 +1,12 @@
 package parser
 
 import (
+	"errors"
+	"strings"
 	"testing"
+
+	"github.com/alecthomas/participle/v2"
+	"github.com/seuros/gopher-cypher/src/cypher"
 )
 
 func TestBasicParsing(t *testing.T) {
@@ -54,3 +59,682 @@ func TestBasicParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestParseOptionalMatch(t *testing.T) {
+	parser, err := New()
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: `OPTIONAL MATCH (n)-[r]->(m)`, expected: "OPTIONAL MATCH (n)-[r]->(m)"},
+		{input: `OPTIONAL MATCH (a:User)<-[:FOLLOWS]-(b)`, expected: "OPTIONAL MATCH (a:User)<-[:FOLLOWS]-(b)"},
+		{input: `MATCH (a)--(b)`, expected: "MATCH (a)--(b)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			q, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			out, _ := q.BuildCypher()
+			if out != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, out)
+			}
+		})
+	}
+}
+
+func TestParseStringPredicates(t *testing.T) {
+	parser, err := New()
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+
+	tests := []struct {
+		input string
+		where string
+		value string
+	}{
+		{input: `MATCH (n) WHERE n.name STARTS WITH "A" RETURN n.name`, where: "WHERE n.name STARTS WITH $p1", value: "A"},
+		{input: `MATCH (n) WHERE n.name ENDS WITH "z" RETURN n.name`, where: "WHERE n.name ENDS WITH $p1", value: "z"},
+		{input: `MATCH (n) WHERE n.name CONTAINS "li" RETURN n.name`, where: "WHERE n.name CONTAINS $p1", value: "li"},
+		{input: `MATCH (n) WHERE n.email =~ ".*@example.com" RETURN n.email`, where: "WHERE n.email =~ $p1", value: ".*@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			q, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			out, params := q.BuildCypher()
+			if !strings.Contains(out, tt.where+"\n") {
+				t.Errorf("expected output to contain %q, got %q", tt.where, out)
+			}
+			if params["p1"] != tt.value {
+				t.Errorf("expected $p1 = %q, got %v", tt.value, params["p1"])
+			}
+		})
+	}
+}
+
+func TestParseNullCheck(t *testing.T) {
+	parser, err := New()
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+
+	tests := []struct {
+		input string
+		where string
+	}{
+		{input: `MATCH (n) WHERE n.deleted IS NULL RETURN n.name`, where: "WHERE n.deleted IS NULL"},
+		{input: `MATCH (n) WHERE n.deleted IS NOT NULL RETURN n.name`, where: "WHERE n.deleted IS NOT NULL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			q, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			out, params := q.BuildCypher()
+			if !strings.Contains(out, tt.where+"\n") {
+				t.Errorf("expected output to contain %q, got %q", tt.where, out)
+			}
+			if len(params) != 0 {
+				t.Errorf("expected no parameters, got %v", params)
+			}
+		})
+	}
+}
+
+func TestParseBacktickIdentifiers(t *testing.T) {
+	parser, err := New()
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+
+	q, err := parser.Parse("MATCH (n:`Weird Label`) RETURN n.`odd prop`")
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	out, _ := q.BuildCypher()
+	if !strings.Contains(out, "MATCH (n:`Weird Label`)\n") {
+		t.Errorf("expected quoted label to be preserved, got %q", out)
+	}
+	if !strings.HasSuffix(out, "RETURN n.`odd prop`") {
+		t.Errorf("expected quoted property to be preserved, got %q", out)
+	}
+
+	q, err = parser.Parse("MATCH (`my node`)-[:`KNOWS WELL`]->(m) WHERE `my node`.`first name` = \"Ann\" RETURN m.name AS `the name`")
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	out, _ = q.BuildCypher()
+	if !strings.Contains(out, "MATCH (`my node`)-[:`KNOWS WELL`]->(m)") {
+		t.Errorf("expected quoted variable and type to be preserved, got %q", out)
+	}
+	if !strings.Contains(out, "AS `the name`") {
+		t.Errorf("expected quoted alias to be preserved, got %q", out)
+	}
+}
+
+func TestParseUnwindParameter(t *testing.T) {
+	parser, err := New()
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "UNWIND $rows AS x RETURN x", want: "UNWIND $rows AS x\n"},
+		{input: `UNWIND [1, $extra, "s"] AS x RETURN x`, want: `UNWIND [1, $extra, "s"] AS x` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			q, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			out, params := q.BuildCypher()
+			if !strings.HasPrefix(out, tt.want) {
+				t.Errorf("expected output to start with %q, got %q", tt.want, out)
+			}
+			if len(params) != 0 {
+				t.Errorf("expected the parameter reference to register nothing, got %v", params)
+			}
+		})
+	}
+}
+
+func TestParseNodePropertyMap(t *testing.T) {
+	parser, err := New()
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+
+	q, err := parser.Parse("MATCH (n:User {id: $id, active: true}) RETURN n")
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	out, params := q.BuildCypher()
+	if !strings.HasPrefix(out, "MATCH (n:User {id: $id, active: $p1})\n") {
+		t.Errorf("unexpected pattern, got %q", out)
+	}
+	if params["p1"] != true {
+		t.Errorf("expected the literal to be parameterized, got %v", params)
+	}
+
+	q, err = parser.Parse(`MERGE (a {name: "Ann", tags: ["x", $tag]})-[:KNOWS]->(b:Person {}) RETURN b`)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	out, params = q.BuildCypher()
+	if !strings.HasPrefix(out, "MERGE (a {name: $p1, tags: [$p2, $tag]})-[:KNOWS]->(b:Person {})\n") {
+		t.Errorf("unexpected pattern, got %q", out)
+	}
+	if params["p1"] != "Ann" || params["p2"] != "x" {
+		t.Errorf("unexpected params %v", params)
+	}
+}
+
+func TestParseIntegerOverflow(t *testing.T) {
+	parser, err := New()
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+
+	_, err = parser.Parse("RETURN 99999999999999999999")
+	if err == nil {
+		t.Fatal("expected an error for an integer literal beyond int64")
+	}
+	if !strings.Contains(err.Error(), "integer literal 99999999999999999999 is out of range") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+	var perr participle.Error
+	if !errors.As(err, &perr) || perr.Position().Column != 8 {
+		t.Errorf("expected the error to point at the literal, got %v", err)
+	}
+
+	q, err := parser.Parse("RETURN 9223372036854775807")
+	if err != nil {
+		t.Fatalf("expected the largest int64 to parse: %v", err)
+	}
+	if _, params := q.BuildCypher(); params["p1"] != 9223372036854775807 {
+		t.Errorf("unexpected params %v", params)
+	}
+}
+
+func TestParseDottedParameter(t *testing.T) {
+	parser, err := New()
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "RETURN $config.timeout", want: "RETURN $config.timeout"},
+		{input: "UNWIND $config.rows AS x RETURN x", want: "UNWIND $config.rows AS x\nRETURN x"},
+		{input: "MATCH (n {id: $filter.user.id}) RETURN n", want: "MATCH (n {id: $filter.user.id})\nRETURN n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			q, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			out, params := q.BuildCypher()
+			if out != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, out)
+			}
+			if len(params) != 0 {
+				t.Errorf("expected the parameter reference to register nothing, got %v", params)
+			}
+		})
+	}
+
+	if _, err := parser.Parse("RETURN $config."); err == nil {
+		t.Error("expected a trailing dot after a parameter to be rejected")
+	}
+}
+
+func TestParseLowercaseKeywords(t *testing.T) {
+	parser, err := New()
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{
+			input: `optional match (Person:Person)-[:KNOWS]->(m) return m.firstName as myName limit 5`,
+			want:  "OPTIONAL MATCH (Person:Person)-[:KNOWS]->(m)\nRETURN m.firstName AS myName\nLIMIT $p1",
+		},
+		{
+			input: `match (n) where n.Name starts with "A" return n`,
+			want:  "MATCH (n)\nWHERE n.Name STARTS WITH $p1\nRETURN n",
+		},
+		{
+			input: `Match (n) Unwind [True, false] As x Where n.flag Is Not Null Return x`,
+			want:  "MATCH (n)\nUNWIND [TRUE, FALSE] AS x\nWHERE n.flag IS NOT NULL\nRETURN x",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			q, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			got := cypher.Format(q, cypher.FormatOptions{UppercaseKeywords: true})
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParameterSpans(t *testing.T) {
+	parser, err := New()
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+
+	input := "MATCH (n:User {name: \"Ann\",\n  age: 42})\nRETURN n"
+	q, err := parser.Parse(input)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	_, params := q.BuildCypher()
+	spans := q.ParameterSpans()
+
+	tests := []struct {
+		value interface{}
+		text  string
+		line  int
+		cols  [2]int
+	}{
+		{value: "Ann", text: `"Ann"`, line: 1, cols: [2]int{22, 27}},
+		{value: 42, text: "42", line: 2, cols: [2]int{8, 10}},
+	}
+	for _, tt := range tests {
+		var key string
+		for k, v := range params {
+			if v == tt.value {
+				key = k
+			}
+		}
+		if key == "" {
+			t.Fatalf("no parameter holds %v in %v", tt.value, params)
+		}
+		if len(spans[key]) != 1 {
+			t.Fatalf("expected one span for $%s, got %v", key, spans[key])
+		}
+		s := spans[key][0]
+		if got := input[s.Offset:s.EndOffset]; got != tt.text {
+			t.Errorf("$%s: expected span over %q, got %q", key, tt.text, got)
+		}
+		if s.Line != tt.line || s.EndLine != tt.line || s.Column != tt.cols[0] || s.EndColumn != tt.cols[1] {
+			t.Errorf("$%s: expected %d:%d-%d, got %+v", key, tt.line, tt.cols[0], tt.cols[1], s)
+		}
+	}
+}
+
+func TestParameterSpansWithoutDedup(t *testing.T) {
+	parser, err := New()
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+
+	input := `MATCH (n) WHERE n.age = 7 RETURN 8 + 7`
+	for _, tt := range []struct {
+		dedup bool
+		want  map[string][]int // parameter -> columns of its spans
+	}{
+		{dedup: true, want: map[string][]int{"p1": {25, 38}, "p2": {34}}},
+		{dedup: false, want: map[string][]int{"p1": {25}, "p2": {34}, "p3": {38}}},
+	} {
+		q, err := parser.Parse(input)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+		q.SetParameterDedup(tt.dedup)
+		q.BuildCypher()
+		spans := q.ParameterSpans()
+
+		if len(spans) != len(tt.want) {
+			t.Fatalf("dedup=%v: expected spans for %v, got %v", tt.dedup, tt.want, spans)
+		}
+		for key, columns := range tt.want {
+			if len(spans[key]) != len(columns) {
+				t.Fatalf("dedup=%v: expected %d spans for $%s, got %v", tt.dedup, len(columns), key, spans[key])
+			}
+			for i, s := range spans[key] {
+				if s.Column != columns[i] {
+					t.Errorf("dedup=%v: $%s: expected a span at column %d, got %+v", tt.dedup, key, columns[i], s)
+				}
+			}
+		}
+	}
+}
+
+func TestParseClauseOrder(t *testing.T) {
+	input := `MATCH (a) WITH a MATCH (b) RETURN a, b`
+
+	// Clauses keep the order they were written, so the MATCH stays after
+	// the WITH that carries a into it.
+	parser, err := New()
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+	q, err := parser.Parse(input)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	want := "MATCH (a)\nWITH a\nMATCH (b)\nRETURN a, b"
+	if out, _ := q.BuildCypher(); out != want {
+		t.Errorf("expected clauses in source order %q, got %q", want, out)
+	}
+	if errs := q.Validate(); len(errs) != 0 {
+		t.Errorf("expected the source order to validate, got %v", errs)
+	}
+	q, err = parser.Parse("MATCH (n) RETURN n LIMIT 2 SKIP 1")
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if out, _ := q.BuildCypher(); out != "MATCH (n)\nRETURN n\nLIMIT $p1\nSKIP $p2" {
+		t.Errorf("expected LIMIT and SKIP as written, got %q", out)
+	}
+
+	// CanonicalClauseOrder sorts them instead.
+	canonical, err := New(CanonicalClauseOrder())
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+	q, err = canonical.Parse(input)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if out, _ := q.BuildCypher(); out != "MATCH (a)\nMATCH (b)\nWITH a\nRETURN a, b" {
+		t.Errorf("expected canonical order, got %q", out)
+	}
+	q, err = canonical.Parse("MATCH (n) RETURN n LIMIT 2 SKIP 1")
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if out, _ := q.BuildCypher(); out != "MATCH (n)\nRETURN n\nSKIP $p1\nLIMIT $p2" {
+		t.Errorf("expected SKIP before LIMIT in canonical order, got %q", out)
+	}
+
+	// A projection term only continues through an arithmetic operator, so
+	// the next clause keyword is not taken for an operand.
+	q, err = parser.Parse("RETURN 1 + 2")
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if out, _ := q.BuildCypher(); out != "RETURN $p1 + $p2" {
+		t.Errorf("expected the operator to be kept, got %q", out)
+	}
+	if _, err := parser.Parse("RETURN a b"); err == nil {
+		t.Error("expected two adjacent terms to be rejected")
+	}
+}
+
+func TestParseProjectionAliases(t *testing.T) {
+	parser, err := New()
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+
+	tests := []struct {
+		input string
+		alias string
+		want  string
+	}{
+		{
+			input: "MATCH (n) WITH n.age AS age RETURN age",
+			alias: "age",
+			want:  "MATCH (n)\nWITH n.age AS age\nRETURN age",
+		},
+		{
+			input: "MATCH (n) RETURN count(n) AS total",
+			alias: "total",
+			want:  "MATCH (n)\nRETURN count(n) AS total",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			parsed, err := parser.parser.ParseString("", tt.input)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			projection := parsed.Clauses[1].Return
+			var items []*ReturnItem
+			if projection != nil {
+				items = projection.Items
+			} else {
+				items = parsed.Clauses[1].With.Items
+			}
+			converted := convertProjectionItems(cypher.NewQuery(), items)
+			alias, ok := converted[0].(*cypher.AliasExpr)
+			if !ok {
+				t.Fatalf("expected an *cypher.AliasExpr, got %T", converted[0])
+			}
+			if alias.Alias != tt.alias {
+				t.Errorf("expected alias %q, got %q", tt.alias, alias.Alias)
+			}
+
+			q, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			if out, _ := q.BuildCypher(); out != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, out)
+			}
+			if errs := q.Validate(); len(errs) != 0 {
+				t.Errorf("expected the aliased query to validate, got %v", errs)
+			}
+		})
+	}
+}
+
+func TestParseFunctionCallArguments(t *testing.T) {
+	parser, err := New()
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "RETURN count(n.id)", want: "RETURN count(n.id)"},
+		{input: "RETURN collect(n.name) AS names", want: "RETURN collect(n.name) AS names"},
+		{input: "RETURN size(collect(n.name)) AS total", want: "RETURN size(collect(n.name)) AS total"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			q, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			out, params := q.BuildCypher()
+			if out != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, out)
+			}
+			if len(params) != 0 {
+				t.Errorf("expected no parameters, got %v", params)
+			}
+		})
+	}
+
+	parsed, err := parser.parser.ParseString("", "RETURN collect(n.name) AS names")
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	converted := convertProjectionItems(cypher.NewQuery(), parsed.Clauses[0].Return.Items)
+	alias, ok := converted[0].(*cypher.AliasExpr)
+	if !ok {
+		t.Fatalf("expected an *cypher.AliasExpr, got %T", converted[0])
+	}
+	fn, ok := alias.Expression.(*cypher.FunctionCallExpr)
+	if !ok || fn.Name != "collect" || len(fn.Arguments) != 1 {
+		t.Fatalf("expected collect with one argument, got %#v", alias.Expression)
+	}
+	prop, ok := fn.Arguments[0].(*cypher.PropertyAccessExpr)
+	if !ok || prop.PropertyName != "name" {
+		t.Fatalf("expected a property access argument, got %#v", fn.Arguments[0])
+	}
+	if v, ok := prop.Variable.(*cypher.VariableExpr); !ok || v.Name != "n" {
+		t.Errorf("expected the property to be read from variable n, got %#v", prop.Variable)
+	}
+}
+
+func TestParseCountSubquery(t *testing.T) {
+	parser, err := New()
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+
+	tests := []struct {
+		input  string
+		want   string
+		params map[string]interface{}
+	}{
+		{
+			input: "RETURN COUNT { MATCH (n)-[:R]->() } AS c",
+			want:  "RETURN COUNT { MATCH (n)-[:R]->() } AS c",
+		},
+		{
+			input:  "MATCH (n {age: 30}) RETURN count { MATCH (n)-[:R]->(m {score: 5}) } AS c",
+			want:   "MATCH (n {age: $p1})\nRETURN COUNT { MATCH (n)-[:R]->(m {score: $p2}) } AS c",
+			params: map[string]interface{}{"p1": 30, "p2": 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			q, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			out, params := q.BuildCypher()
+			if out != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, out)
+			}
+			if len(params) != len(tt.params) {
+				t.Errorf("expected parameters %v, got %v", tt.params, params)
+			}
+			for k, v := range tt.params {
+				if params[k] != v {
+					t.Errorf("expected %s = %v, got %v", k, v, params[k])
+				}
+			}
+		})
+	}
+}
+
+func TestParseDelete(t *testing.T) {
+	parser, err := New()
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "MATCH (n) DETACH DELETE n", want: "MATCH (n)\nDETACH DELETE n"},
+		{input: "MATCH (a)-[r]->(b) DELETE r, b", want: "MATCH (a)-[r]->(b)\nDELETE r, b"},
+		{input: "match (n) detach delete n", want: "MATCH (n)\nDETACH DELETE n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			q, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			if out, params := q.BuildCypher(); out != tt.want || len(params) != 0 {
+				t.Errorf("expected %q without parameters, got %q %v", tt.want, out, params)
+			}
+			if errs := q.Validate(); len(errs) != 0 {
+				t.Errorf("expected the deleted variables to resolve, got %v", errs)
+			}
+		})
+	}
+}
+
+func TestParseProjectionAll(t *testing.T) {
+	parser, err := New()
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "MATCH (n) RETURN *", want: "MATCH (n)\nRETURN *"},
+		{input: "MATCH (n)-[r]->(m) WITH * RETURN n, m", want: "MATCH (n)-[r]->(m)\nWITH *\nRETURN n, m"},
+		{input: "MATCH (n) WITH *, count(n) AS total RETURN total", want: "MATCH (n)\nWITH *, count(n) AS total\nRETURN total"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			q, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			if out, _ := q.BuildCypher(); out != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, out)
+			}
+			if errs := q.Validate(); len(errs) != 0 {
+				t.Errorf("expected * to keep the variables in scope, got %v", errs)
+			}
+		})
+	}
+
+	if _, err := parser.Parse("MATCH (n) RETURN * n"); err == nil {
+		t.Error("expected items after * without a comma to be rejected")
+	}
+}
+
+func TestParseSyntaxError(t *testing.T) {
+	parser, err := New()
+	if err != nil {
+		t.Fatalf("failed to create parser: %v", err)
+	}
+
+	_, err = parser.Parse("MATCH (n)\nRETURN n.name AS")
+	var serr *SyntaxError
+	if !errors.As(err, &serr) {
+		t.Fatalf("expected a *SyntaxError, got %T: %v", err, err)
+	}
+	if serr.Line != 2 || serr.Column == 0 {
+		t.Errorf("expected a position on line 2, got %d:%d", serr.Line, serr.Column)
+	}
+	if serr.Message == "" || !strings.HasPrefix(err.Error(), "parse error: 2:") {
+		t.Errorf("unexpected error %q (message %q)", err, serr.Message)
+	}
+	var perr participle.Error
+	if !errors.As(err, &perr) {
+		t.Error("expected the participle error to stay reachable")
+	}
+}
@@ -6,12 +6,12 @@ import (
 )
 
 // TestRegressionProtection ensures that parser coverage doesn't regress
-// by testing all currently working fixtures that comprise our 40% coverage.
+// by testing all currently working fixtures.
 func TestRegressionProtection(t *testing.T) {
 	parser, err := New()
 	require.NoError(t, err)
 
-	// These are the 6 fixtures that MUST continue to work (our 40% coverage)
+	// These are the fixtures that MUST continue to work
 	workingFixtures := []struct {
 		name  string
 		query string
@@ -40,6 +40,38 @@ func TestRegressionProtection(t *testing.T) {
 			name:  "Match with property filter",
 			query: "MATCH (n:Person) WHERE n.age = 25 RETURN n",
 		},
+		{
+			name:  "Optional match",
+			query: "OPTIONAL MATCH (n:Person) RETURN n",
+		},
+		{
+			name:  "Basic return of parameters",
+			query: "RETURN $p1, $p2",
+		},
+		{
+			name:  "Function with parameters",
+			query: "RETURN substring($text, 0, 5)",
+		},
+		{
+			name:  "Multiple expressions",
+			query: "RETURN n.name, n.age",
+		},
+		{
+			name:  "Complex match pattern",
+			query: "MATCH (a)-[:KNOWS]->(b) RETURN a, b",
+		},
+		{
+			name:  "Set multiple properties",
+			query: "MATCH (n) SET n.name = $name, n.age = $age",
+		},
+		{
+			name:  "Unwind complex list",
+			query: "UNWIND [$a, $b, $c] AS item RETURN item",
+		},
+		{
+			name:  "Limit with parameter",
+			query: "MATCH (n) RETURN n LIMIT $limit",
+		},
 	}
 
 	for _, fixture := range workingFixtures {
@@ -56,7 +88,7 @@ func TestRegressionProtection(t *testing.T) {
 	}
 }
 
-// TestKnownFailures documents the 9 fixtures that should fail (our 60% missing coverage)
+// TestKnownFailures documents the fixtures the parser does not support yet.
 // These tests should pass when they fail - if any start passing, we've improved coverage!
 func TestKnownFailures(t *testing.T) {
 	parser, err := New()
@@ -67,46 +99,6 @@ func TestKnownFailures(t *testing.T) {
 		query  string
 		reason string
 	}{
-		{
-			name:   "Basic return of parameters",
-			query:  "RETURN $p1, $p2",
-			reason: "Multiple parameter returns not supported yet",
-		},
-		{
-			name:   "Function with parameters",
-			query:  "RETURN substring($text, 0, 5)",
-			reason: "Functions with parameters not supported yet",
-		},
-		{
-			name:   "Multiple expressions",
-			query:  "RETURN n.name, n.age",
-			reason: "Multiple return expressions not supported yet",
-		},
-		{
-			name:   "Complex match pattern",
-			query:  "MATCH (a)-[:KNOWS]->(b) RETURN a, b",
-			reason: "Relationship patterns not supported yet",
-		},
-		{
-			name:   "Optional match",
-			query:  "OPTIONAL MATCH (n:Person) RETURN n",
-			reason: "Optional match not fully implemented",
-		},
-		{
-			name:   "Set multiple properties",
-			query:  "MATCH (n) SET n.name = $name, n.age = $age",
-			reason: "Multiple SET assignments not supported yet",
-		},
-		{
-			name:   "Unwind complex list",
-			query:  "UNWIND [$a, $b, $c] AS item RETURN item",
-			reason: "Complex list unwinding not supported yet",
-		},
-		{
-			name:   "Limit with parameter",
-			query:  "MATCH (n) RETURN n LIMIT $limit",
-			reason: "Parameterized LIMIT not implemented",
-		},
 		{
 			name:   "Order by clause",
 			query:  "MATCH (n) RETURN n ORDER BY n.name",
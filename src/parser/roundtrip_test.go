@@ -40,7 +40,7 @@ func TestRoundtrip(t *testing.T) {
 			rebuilt, _ := parsed1.BuildCypher()
 
 			// Note: We can't do full roundtrip yet because generated Cypher
-			// contains parameters ($p1.age) that our grammar doesn't support.
+			// contains parameters ($p1) in places our grammar doesn't support.
 			// For now, we test that parsing succeeds and generates output.
 
 			if rebuilt == "" {